@@ -13,15 +13,21 @@ type client struct {
 	logRequests  bool
 	logResponses bool
 	serverName   string
+	retryPolicy  *types.RetryPolicy
+
+	// idempotent is non-zero if the server last advertised that it returns
+	// the outcome of a mutating request to the request's retries
+	idempotent int32
 }
 
 // New returns a new API client.
-func New(host string, transport *http.Transport) types.APIClient {
+func New(host string, transport http.RoundTripper) types.APIClient {
 	return &client{
 		Client: http.Client{
 			Transport: transport,
 		},
-		host: host,
+		host:        host,
+		retryPolicy: defaultRetryPolicy,
 	}
 }
 
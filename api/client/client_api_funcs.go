@@ -5,7 +5,9 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/codedellemc/libstorage/api/types"
 )
@@ -83,6 +85,84 @@ func (c *client) ServiceInspect(
 	return reply, nil
 }
 
+func (c *client) ServiceCreate(
+	ctx types.Context,
+	request *types.ServiceCreateRequest) (*types.ServiceInfo, error) {
+
+	reply := &types.ServiceInfo{}
+	if _, err := c.httpPost(ctx, "/services", request, reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (c *client) ServiceUpdate(
+	ctx types.Context,
+	name string,
+	request *types.ServiceUpdateRequest) (*types.ServiceInfo, error) {
+
+	reply := &types.ServiceInfo{}
+	if _, err := c.httpPatch(ctx,
+		fmt.Sprintf("/services/%s", name), request, reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (c *client) ServiceRemove(ctx types.Context, name string) error {
+	if _, err := c.httpDelete(
+		ctx, fmt.Sprintf("/services/%s", name), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *client) Quotas(ctx types.Context) (types.ServiceQuotaMap, error) {
+	reply := types.ServiceQuotaMap{}
+	if _, err := c.httpGet(ctx, "/quotas", &reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (c *client) Audit(
+	ctx types.Context,
+	query *types.AuditQuery) ([]*types.AuditRecord, error) {
+
+	params := url.Values{}
+	if query != nil {
+		for k, v := range map[string]string{
+			"route":      query.Route,
+			"service":    query.Service,
+			"subject":    query.Subject,
+			"volumeID":   query.VolumeID,
+			"snapshotID": query.SnapshotID,
+			"outcome":    string(query.Outcome),
+		} {
+			if v != "" {
+				params.Set(k, v)
+			}
+		}
+		if query.Since > 0 {
+			params.Set("since", strconv.FormatInt(query.Since, 10))
+		}
+		if query.Limit > 0 {
+			params.Set("limit", strconv.Itoa(query.Limit))
+		}
+	}
+
+	path := "/audit"
+	if len(params) > 0 {
+		path = fmt.Sprintf("%s?%s", path, params.Encode())
+	}
+
+	reply := []*types.AuditRecord{}
+	if _, err := c.httpGet(ctx, path, &reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
 func (c *client) Volumes(
 	ctx types.Context,
 	attachments types.VolumeAttachmentsTypes) (types.ServiceVolumeMap, error) {
@@ -108,6 +188,37 @@ func (c *client) VolumesByService(
 	return reply, nil
 }
 
+func (c *client) VolumesPage(
+	ctx types.Context,
+	attachments types.VolumeAttachmentsTypes,
+	page *types.PageOpts) ([]*types.ServiceVolume, string, error) {
+
+	reply := []*types.ServiceVolume{}
+	url := withPageQuery(
+		fmt.Sprintf("/volumes?attachments=%v", attachments), page)
+	res, err := c.httpGet(ctx, url, &reply)
+	if err != nil {
+		return nil, "", err
+	}
+	return reply, res.Header.Get(types.NextMarkerHeader), nil
+}
+
+func (c *client) VolumesByServicePage(
+	ctx types.Context,
+	service string,
+	attachments types.VolumeAttachmentsTypes,
+	page *types.PageOpts) ([]*types.Volume, string, error) {
+
+	reply := []*types.Volume{}
+	url := withPageQuery(
+		fmt.Sprintf("/volumes/%s?attachments=%v", service, attachments), page)
+	res, err := c.httpGet(ctx, url, &reply)
+	if err != nil {
+		return nil, "", err
+	}
+	return reply, res.Header.Get(types.NextMarkerHeader), nil
+}
+
 func (c *client) VolumeInspect(
 	ctx types.Context,
 	service, volumeID string,
@@ -178,6 +289,20 @@ func (c *client) VolumeCopy(
 	return &reply, nil
 }
 
+func (c *client) VolumeResize(
+	ctx types.Context,
+	service, volumeID string,
+	request *types.VolumeResizeRequest) (*types.Volume, error) {
+
+	reply := types.Volume{}
+	if _, err := c.httpPost(ctx,
+		fmt.Sprintf("/volumes/%s/%s?resize", service, volumeID),
+		request, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
 func (c *client) VolumeRemove(
 	ctx types.Context,
 	service, volumeID string,
@@ -286,6 +411,33 @@ func (c *client) SnapshotsByService(
 	return reply, nil
 }
 
+func (c *client) SnapshotsPage(
+	ctx types.Context,
+	page *types.PageOpts) ([]*types.ServiceSnapshot, string, error) {
+
+	reply := []*types.ServiceSnapshot{}
+	url := withPageQuery("/snapshots", page)
+	res, err := c.httpGet(ctx, url, &reply)
+	if err != nil {
+		return nil, "", err
+	}
+	return reply, res.Header.Get(types.NextMarkerHeader), nil
+}
+
+func (c *client) SnapshotsByServicePage(
+	ctx types.Context,
+	service string,
+	page *types.PageOpts) ([]*types.Snapshot, string, error) {
+
+	reply := []*types.Snapshot{}
+	url := withPageQuery(fmt.Sprintf("/snapshots/%s", service), page)
+	res, err := c.httpGet(ctx, url, &reply)
+	if err != nil {
+		return nil, "", err
+	}
+	return reply, res.Header.Get(types.NextMarkerHeader), nil
+}
+
 func (c *client) SnapshotInspect(
 	ctx types.Context,
 	service, snapshotID string) (*types.Snapshot, error) {
@@ -353,11 +505,22 @@ func (c *client) ExecutorHead(
 		return nil, err
 	}
 
-	return &types.ExecutorInfo{
+	ei := &types.ExecutorInfo{
 		Name:        name,
 		Size:        size,
 		MD5Checksum: fmt.Sprintf("%x", buf),
-	}, nil
+	}
+
+	digest := res.Header.Get(types.DigestHeader)
+	if strings.HasPrefix(digest, "SHA-256=") {
+		buf, err := base64.StdEncoding.DecodeString(digest[8:])
+		if err != nil {
+			return nil, err
+		}
+		ei.SHA256Checksum = fmt.Sprintf("%x", buf)
+	}
+
+	return ei, nil
 }
 
 func (c *client) ExecutorGet(
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/akutz/goof"
+	"golang.org/x/net/context/ctxhttp"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func (c *client) Events(
+	ctx types.Context,
+	filter *types.EventFilter) (<-chan *types.Event, error) {
+
+	params := url.Values{}
+	if filter != nil {
+		for _, t := range filter.Types {
+			params.Add("type", string(t))
+		}
+		for k, v := range map[string]string{
+			"service":    filter.Service,
+			"volumeID":   filter.VolumeID,
+			"snapshotID": filter.SnapshotID,
+		} {
+			if v != "" {
+				params.Set(k, v)
+			}
+		}
+	}
+
+	path := "/events"
+	if len(params) > 0 {
+		path = fmt.Sprintf("%s?%s", path, params.Encode())
+	}
+
+	ctx, req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", types.EventStreamContentType)
+
+	c.logRequest(req)
+
+	res, err := ctxhttp.Do(ctx, &c.Client, req)
+	if err != nil {
+		return nil, err
+	}
+	c.setServerName(res)
+
+	// the response is not logged since dumping it would read the stream
+	if res.StatusCode > 299 {
+		defer res.Body.Close()
+		httpErr, err := goof.DecodeHTTPError(res.Body)
+		if err != nil {
+			return nil, goof.WithField("status", res.StatusCode, "http error")
+		}
+		return nil, httpErr
+	}
+
+	events := make(chan *types.Event)
+	go func() {
+		defer close(events)
+		defer res.Body.Close()
+		if err := readEvents(ctx, res.Body, events); err != nil {
+			ctx.WithError(err).Debug("event stream ended")
+		}
+	}()
+
+	return events, nil
+}
+
+// readEvents reads server-sent events from r and sends them on the channel
+// until r is exhausted or the context is cancelled.
+func readEvents(
+	ctx types.Context, r io.Reader, events chan<- *types.Event) error {
+
+	var (
+		data    = &bytes.Buffer{}
+		scanner = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+
+		// a blank line dispatches the event
+		if len(line) == 0 {
+			if data.Len() == 0 {
+				continue
+			}
+			ev := &types.Event{}
+			if err := json.Unmarshal(data.Bytes(), ev); err != nil {
+				return err
+			}
+			data.Reset()
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			continue
+		}
+
+		// only the data field is used since the event's ID and type are
+		// included in the data
+		if bytes.HasPrefix(line, []byte("data:")) {
+			line = bytes.TrimPrefix(line[5:], []byte(" "))
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.Write(line)
+		}
+	}
+
+	return scanner.Err()
+}
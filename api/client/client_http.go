@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/akutz/goof"
 	"golang.org/x/net/context/ctxhttp"
@@ -48,6 +49,81 @@ func (c *client) httpDo(
 	method, path string,
 	payload, reply interface{}) (*http.Response, error) {
 
+	var (
+		req *http.Request
+		res *http.Response
+		err error
+	)
+
+	for attempt := 1; ; attempt++ {
+
+		ctx, req, err = c.newRequest(ctx, method, path, payload)
+		if err != nil {
+			return nil, err
+		}
+
+		c.logRequest(req)
+
+		res, err = ctxhttp.Do(ctx, &c.Client, req)
+		if err == nil {
+			c.logResponse(res)
+			c.setIdempotent(res)
+		}
+
+		wait, ok := c.retryWait(ctx, req, res, err, attempt)
+		if !ok {
+			break
+		}
+
+		fields := map[string]interface{}{
+			"attempt": attempt,
+			"wait":    wait,
+		}
+		if err != nil {
+			ctx.WithFields(fields).WithError(err).Warn(
+				"request failed; retrying request")
+		} else {
+			res.Body.Close()
+			fields["status"] = res.StatusCode
+			ctx.WithFields(fields).Warn("request failed; retrying request")
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer c.setServerName(res)
+
+	if res.StatusCode > 299 {
+		httpErr, err := goof.DecodeHTTPError(res.Body)
+		if err != nil {
+			return res, goof.WithField("status", res.StatusCode, "http error")
+		}
+		return res, httpErr
+	}
+
+	if req.Method != http.MethodHead && reply != nil {
+		if err := decRes(res.Body, reply); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// newRequest returns a new request with the headers for the transaction,
+// instance IDs, local devices, and auth token in the context. The returned
+// context includes the request's transaction.
+func (c *client) newRequest(
+	ctx types.Context,
+	method, path string,
+	payload interface{}) (types.Context, *http.Request, error) {
+
 	registerCustomKeyOnce.Do(func() {
 		context.RegisterCustomKeyWithContext(
 			ctx, transactionHeaderKey, context.CustomHeaderKey)
@@ -61,13 +137,13 @@ func (c *client) httpDo(
 
 	reqBody, err := encPayload(payload)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	url := fmt.Sprintf("http://%s%s", c.host, path)
 	req, err := http.NewRequest(method, url, reqBody)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	ctx = context.RequireTX(ctx)
@@ -157,31 +233,7 @@ func (c *client) httpDo(
 		}
 	}
 
-	c.logRequest(req)
-
-	res, err := ctxhttp.Do(ctx, &c.Client, req)
-	if err != nil {
-		return nil, err
-	}
-	defer c.setServerName(res)
-
-	c.logResponse(res)
-
-	if res.StatusCode > 299 {
-		httpErr, err := goof.DecodeHTTPError(res.Body)
-		if err != nil {
-			return res, goof.WithField("status", res.StatusCode, "http error")
-		}
-		return res, httpErr
-	}
-
-	if req.Method != http.MethodHead && reply != nil {
-		if err := decRes(res.Body, reply); err != nil {
-			return nil, err
-		}
-	}
-
-	return res, nil
+	return ctx, req, nil
 }
 
 func (c *client) setServerName(res *http.Response) {
@@ -212,6 +264,15 @@ func (c *client) httpPost(
 	return c.httpDo(ctx, "POST", path, payload, reply)
 }
 
+func (c *client) httpPatch(
+	ctx types.Context,
+	path string,
+	payload interface{},
+	reply interface{}) (*http.Response, error) {
+
+	return c.httpDo(ctx, "PATCH", path, payload, reply)
+}
+
 func (c *client) httpDelete(
 	ctx types.Context,
 	path string,
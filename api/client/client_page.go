@@ -0,0 +1,142 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// withPageQuery appends the page options to the path as query parameters.
+// The limit is always appended, even when there is none, so that the server
+// returns a page rather than the whole collection.
+func withPageQuery(path string, page *types.PageOpts) string {
+	if page == nil {
+		page = &types.PageOpts{}
+	}
+
+	q := url.Values{}
+	q.Set("limit", strconv.Itoa(page.Limit))
+	if page.Offset > 0 {
+		q.Set("offset", strconv.Itoa(page.Offset))
+	}
+	if page.Marker != "" {
+		q.Set("marker", page.Marker)
+	}
+	if page.Sort != "" {
+		q.Set("sort", page.Sort)
+	}
+
+	if strings.Contains(path, "?") {
+		return path + "&" + q.Encode()
+	}
+	return path + "?" + q.Encode()
+}
+
+// EachVolumesPage invokes f for each page of the volumes for all services,
+// beginning with the page specified by the page options. Iteration stops
+// when there are no more pages, f returns false, or an error occurs.
+func EachVolumesPage(
+	ctx types.Context,
+	c types.APIClient,
+	attachments types.VolumeAttachmentsTypes,
+	page *types.PageOpts,
+	f func([]*types.ServiceVolume) bool) error {
+
+	p := nextPageOpts(page, "")
+	for {
+		objs, next, err := c.VolumesPage(ctx, attachments, p)
+		if err != nil {
+			return err
+		}
+		if !f(objs) || next == "" {
+			return nil
+		}
+		p = nextPageOpts(p, next)
+	}
+}
+
+// EachVolumesByServicePage invokes f for each page of the volumes for a
+// service, beginning with the page specified by the page options. Iteration
+// stops when there are no more pages, f returns false, or an error occurs.
+func EachVolumesByServicePage(
+	ctx types.Context,
+	c types.APIClient,
+	service string,
+	attachments types.VolumeAttachmentsTypes,
+	page *types.PageOpts,
+	f func([]*types.Volume) bool) error {
+
+	p := nextPageOpts(page, "")
+	for {
+		objs, next, err := c.VolumesByServicePage(ctx, service, attachments, p)
+		if err != nil {
+			return err
+		}
+		if !f(objs) || next == "" {
+			return nil
+		}
+		p = nextPageOpts(p, next)
+	}
+}
+
+// EachSnapshotsPage invokes f for each page of the snapshots for all
+// services, beginning with the page specified by the page options. Iteration
+// stops when there are no more pages, f returns false, or an error occurs.
+func EachSnapshotsPage(
+	ctx types.Context,
+	c types.APIClient,
+	page *types.PageOpts,
+	f func([]*types.ServiceSnapshot) bool) error {
+
+	p := nextPageOpts(page, "")
+	for {
+		objs, next, err := c.SnapshotsPage(ctx, p)
+		if err != nil {
+			return err
+		}
+		if !f(objs) || next == "" {
+			return nil
+		}
+		p = nextPageOpts(p, next)
+	}
+}
+
+// EachSnapshotsByServicePage invokes f for each page of the snapshots for a
+// service, beginning with the page specified by the page options. Iteration
+// stops when there are no more pages, f returns false, or an error occurs.
+func EachSnapshotsByServicePage(
+	ctx types.Context,
+	c types.APIClient,
+	service string,
+	page *types.PageOpts,
+	f func([]*types.Snapshot) bool) error {
+
+	p := nextPageOpts(page, "")
+	for {
+		objs, next, err := c.SnapshotsByServicePage(ctx, service, p)
+		if err != nil {
+			return err
+		}
+		if !f(objs) || next == "" {
+			return nil
+		}
+		p = nextPageOpts(p, next)
+	}
+}
+
+// nextPageOpts returns a copy of the page options. If a marker is provided
+// the copy uses it in place of the original marker and offset, since the
+// marker already encodes the position of the next page.
+func nextPageOpts(page *types.PageOpts, marker string) *types.PageOpts {
+	p := &types.PageOpts{}
+	if page != nil {
+		*p = *page
+	}
+	if marker != "" {
+		p.Marker = marker
+		p.Offset = 0
+	}
+	return p
+}
@@ -0,0 +1,212 @@
+package client
+
+import (
+	"crypto/x509"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+const (
+	// maxRetryAfter is the longest the client waits to retry a request whose
+	// response asks the client to wait with a Retry-After header. Responses
+	// that ask the client to wait longer are returned as errors.
+	maxRetryAfter = time.Duration(30) * time.Second
+)
+
+// defaultRetryPolicy is the policy of a client that is not given one.
+var defaultRetryPolicy = &types.RetryPolicy{
+	MaxAttempts: 4,
+	Backoff:     time.Duration(250) * time.Millisecond,
+	MaxBackoff:  time.Duration(5) * time.Second,
+	StatusCodes: []int{
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+	Errors: []string{
+		types.RetryErrorConnect,
+		types.RetryErrorTimeout,
+		types.RetryErrorNetwork,
+	},
+	Mutating: true,
+}
+
+func (c *client) SetRetryPolicy(policy *types.RetryPolicy) {
+	if policy == nil {
+		policy = defaultRetryPolicy
+	}
+	c.retryPolicy = policy
+}
+
+// retryWait returns the time to wait before retrying a request that was sent
+// the given number of times and received the given response or error. A
+// false value is returned if the request should not be retried.
+func (c *client) retryWait(
+	ctx types.Context,
+	req *http.Request,
+	res *http.Response,
+	err error,
+	attempt int) (time.Duration, bool) {
+
+	p := c.retryPolicy
+	if attempt >= p.MaxAttempts || ctx.Err() != nil {
+		return 0, false
+	}
+
+	if err != nil {
+		class := retryErrorClass(err)
+		if !containsString(p.Errors, class) {
+			return 0, false
+		}
+		// a request that never reached the server is safe to send again
+		if class != types.RetryErrorConnect && !c.isRetrySafe(req, nil) {
+			return 0, false
+		}
+		return backoff(p, attempt), true
+	}
+
+	// a request that exceeded one of the server's rate limits was not
+	// executed, so it is retried once the server allows it
+	if res.StatusCode == http.StatusTooManyRequests {
+		return retryAfter(res)
+	}
+
+	if !containsInt(p.StatusCodes, res.StatusCode) ||
+		!c.isRetrySafe(req, res) {
+		return 0, false
+	}
+	if res.Header.Get(types.RetryAfterHeader) != "" {
+		return retryAfter(res)
+	}
+	return backoff(p, attempt), true
+}
+
+// isRetrySafe returns a flag indicating whether the request may be sent
+// again after it may have reached the server. Requests that do not modify
+// anything are always safe. Others are safe only if the policy allows it,
+// they carry the transaction with which the server identifies their retries,
+// and the server advertises that it returns the outcome of the original
+// request to the retries. The advertisement is read from the response, if
+// any, or else from the server's last response.
+func (c *client) isRetrySafe(req *http.Request, res *http.Response) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	}
+	if !c.retryPolicy.Mutating ||
+		req.Header.Get(types.TransactionHeader) == "" {
+		return false
+	}
+	if res != nil {
+		return isIdempotent(res)
+	}
+	return atomic.LoadInt32(&c.idempotent) != 0
+}
+
+// setIdempotent records whether the server that sent the response advertises
+// that it returns the outcome of a mutating request to its retries.
+func (c *client) setIdempotent(res *http.Response) {
+	var v int32
+	if isIdempotent(res) {
+		v = 1
+	}
+	atomic.StoreInt32(&c.idempotent, v)
+}
+
+func isIdempotent(res *http.Response) bool {
+	return res.Header.Get(types.IdempotencyHeader) ==
+		types.IdempotencyTransaction
+}
+
+// retryErrorClass returns the class of the error with which a request
+// failed. An empty string is returned for errors that are not transient,
+// such as those that occur when the server's certificate cannot be verified.
+func retryErrorClass(err error) string {
+	if ue, ok := err.(*url.Error); ok {
+		err = ue.Err
+	}
+	switch te := err.(type) {
+	case *types.ErrKnownHost,
+		*types.ErrKnownHostConflict,
+		x509.CertificateInvalidError,
+		x509.HostnameError,
+		x509.UnknownAuthorityError:
+		return ""
+	case *net.OpError:
+		if te.Op == "dial" {
+			return types.RetryErrorConnect
+		}
+	}
+	if ne, ok := err.(net.Error); ok && ne.Timeout() {
+		return types.RetryErrorTimeout
+	}
+	return types.RetryErrorNetwork
+}
+
+// backoff returns the time to wait before the given attempt's retry. The
+// time doubles with each attempt and is randomized between half of it and
+// all of it.
+func backoff(p *types.RetryPolicy, attempt int) time.Duration {
+	wait := p.Backoff
+	for i := 1; i < attempt && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(wait-half)+1))
+}
+
+// retryAfter returns the time to wait before retrying a request whose
+// response includes a Retry-After header. A false value is returned if the
+// response has no such header or asks the client to wait too long.
+func retryAfter(res *http.Response) (time.Duration, bool) {
+	v := res.Header.Get(types.RetryAfterHeader)
+	if v == "" {
+		return 0, false
+	}
+	var wait time.Duration
+	if secs, err := strconv.Atoi(v); err == nil {
+		wait = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		wait = t.Sub(time.Now())
+	} else {
+		return 0, false
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	if wait > maxRetryAfter {
+		return 0, false
+	}
+	return wait, true
+}
+
+func containsString(vals []string, val string) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(vals []int, val int) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func newRetryRequest(method string, tx bool) *http.Request {
+	req, _ := http.NewRequest(method, "http://libstorage-server/volumes", nil)
+	if tx {
+		req.Header.Set(
+			types.TransactionHeader,
+			"txID=5f5d8a2b-8c7b-4a6e-9a39-3c8a2f0d7a11, txCR=1491238950")
+	}
+	return req
+}
+
+func newRetryResponse(status int) *http.Response {
+	return &http.Response{StatusCode: status, Header: http.Header{}}
+}
+
+func newIdempotentRetryResponse(status int) *http.Response {
+	res := newRetryResponse(status)
+	res.Header.Set(types.IdempotencyHeader, types.IdempotencyTransaction)
+	return res
+}
+
+func TestRetryWaitStatus(t *testing.T) {
+	c := New("", nil).(*client)
+	ctx := context.Background()
+
+	// a request that does not modify anything is retried
+	_, ok := c.retryWait(ctx, newRetryRequest("GET", false),
+		newRetryResponse(http.StatusServiceUnavailable), nil, 1)
+	assert.True(t, ok)
+
+	// but not once it has been sent the maximum number of times
+	_, ok = c.retryWait(ctx, newRetryRequest("GET", false),
+		newRetryResponse(http.StatusServiceUnavailable), nil, 4)
+	assert.False(t, ok)
+
+	// nor if the status is not retryable
+	_, ok = c.retryWait(ctx, newRetryRequest("GET", false),
+		newRetryResponse(http.StatusInternalServerError), nil, 1)
+	assert.False(t, ok)
+
+	// a mutating request is retried only with its transaction, and only if
+	// the server returns the original outcome to the retries
+	_, ok = c.retryWait(ctx, newRetryRequest("POST", false),
+		newIdempotentRetryResponse(http.StatusServiceUnavailable), nil, 1)
+	assert.False(t, ok)
+	_, ok = c.retryWait(ctx, newRetryRequest("POST", true),
+		newRetryResponse(http.StatusServiceUnavailable), nil, 1)
+	assert.False(t, ok)
+	_, ok = c.retryWait(ctx, newRetryRequest("POST", true),
+		newIdempotentRetryResponse(http.StatusServiceUnavailable), nil, 1)
+	assert.True(t, ok)
+
+	// and only if the policy allows it
+	c.SetRetryPolicy(&types.RetryPolicy{
+		MaxAttempts: 4,
+		StatusCodes: []int{http.StatusServiceUnavailable},
+	})
+	_, ok = c.retryWait(ctx, newRetryRequest("POST", true),
+		newIdempotentRetryResponse(http.StatusServiceUnavailable), nil, 1)
+	assert.False(t, ok)
+}
+
+func TestRetryWaitRetryAfter(t *testing.T) {
+	c := New("", nil).(*client)
+	ctx := context.Background()
+
+	res := newRetryResponse(http.StatusTooManyRequests)
+	res.Header.Set(types.RetryAfterHeader, "2")
+	wait, ok := c.retryWait(ctx, newRetryRequest("POST", false), res, nil, 1)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, wait)
+
+	res.Header.Set(types.RetryAfterHeader, "60")
+	_, ok = c.retryWait(ctx, newRetryRequest("POST", false), res, nil, 1)
+	assert.False(t, ok)
+}
+
+func TestRetryWaitErrors(t *testing.T) {
+	c := New("", nil).(*client)
+	ctx := context.Background()
+
+	dialErr := &url.Error{
+		Op:  "Post",
+		URL: "http://libstorage-server/volumes",
+		Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("refused")},
+	}
+	netErr := &url.Error{
+		Op:  "Post",
+		URL: "http://libstorage-server/volumes",
+		Err: errors.New("connection reset by peer"),
+	}
+
+	// a request that never reached the server is always retried
+	_, ok := c.retryWait(ctx, newRetryRequest("POST", false), nil, dialErr, 1)
+	assert.True(t, ok)
+
+	// one that may have reached it is retried only with its transaction
+	// and only if the server last advertised that retries are safe
+	_, ok = c.retryWait(ctx, newRetryRequest("POST", true), nil, netErr, 1)
+	assert.False(t, ok)
+	c.setIdempotent(newIdempotentRetryResponse(http.StatusOK))
+	_, ok = c.retryWait(ctx, newRetryRequest("POST", false), nil, netErr, 1)
+	assert.False(t, ok)
+	_, ok = c.retryWait(ctx, newRetryRequest("POST", true), nil, netErr, 1)
+	assert.True(t, ok)
+	c.setIdempotent(newRetryResponse(http.StatusOK))
+	_, ok = c.retryWait(ctx, newRetryRequest("POST", true), nil, netErr, 1)
+	assert.False(t, ok)
+	_, ok = c.retryWait(ctx, newRetryRequest("GET", false), nil, netErr, 1)
+	assert.True(t, ok)
+
+	// errors that are not transient are not retried
+	khErr := &url.Error{
+		Op:  "Get",
+		URL: "http://libstorage-server/volumes",
+		Err: &types.ErrKnownHost{},
+	}
+	_, ok = c.retryWait(ctx, newRetryRequest("GET", false), nil, khErr, 1)
+	assert.False(t, ok)
+}
+
+func TestBackoff(t *testing.T) {
+	p := &types.RetryPolicy{
+		Backoff:    100 * time.Millisecond,
+		MaxBackoff: time.Second,
+	}
+	for attempt, max := range []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	} {
+		for i := 0; i < 10; i++ {
+			wait := backoff(p, attempt+1)
+			assert.True(t, wait >= max/2, "attempt %d: %v", attempt+1, wait)
+			assert.True(t, wait <= max, "attempt %d: %v", attempt+1, wait)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
 	gcontext "github.com/gorilla/context"
@@ -104,6 +105,36 @@ func Background() types.Context {
 	return New(nil)
 }
 
+// WithCancel returns a copy of parent with a new Done channel. The returned
+// context's Done channel is closed when the returned cancel function is
+// called or when the parent context's Done channel is closed, whichever
+// happens first.
+func WithCancel(parent types.Context) (types.Context, context.CancelFunc) {
+	cctx, cancel := context.WithCancel(parent)
+	ctx := newContext(cctx, nil, nil, nil, nil)
+	if p, ok := parent.(*lsc); ok {
+		ctx.logger = p.logger
+		ctx.pathConfig = p.pathConfig
+	}
+	return ctx, cancel
+}
+
+// WithTimeout returns a copy of parent whose Done channel is closed when the
+// timeout elapses, when the returned cancel function is called, or when the
+// parent context's Done channel is closed, whichever happens first.
+func WithTimeout(
+	parent types.Context,
+	timeout time.Duration) (types.Context, context.CancelFunc) {
+
+	cctx, cancel := context.WithTimeout(parent, timeout)
+	ctx := newContext(cctx, nil, nil, nil, nil)
+	if p, ok := parent.(*lsc); ok {
+		ctx.logger = p.logger
+		ctx.pathConfig = p.pathConfig
+	}
+	return ctx, cancel
+}
+
 // WithRequestRoute returns a new context with the injected *http.Request
 // and Route.
 func WithRequestRoute(
@@ -151,7 +182,14 @@ func WithStorageSession(parent context.Context) (types.Context, error) {
 
 	pctx := New(parent)
 
-	d, ok := MustDriver(parent).(types.StorageDriverWithLogin)
+	// optional driver interfaces are implemented by the driver that is
+	// wrapped by the storage driver manager
+	sd := MustDriver(parent)
+	if m, ok := sd.(types.StorageDriverManager); ok {
+		sd = m.Driver()
+	}
+
+	d, ok := sd.(types.StorageDriverWithLogin)
 	if !ok {
 		pctx.Debug("driver is not StorageDriverWithLogin")
 		return pctx, nil
@@ -454,6 +492,38 @@ func MustService(ctx context.Context) types.StorageService {
 	return v.(types.StorageService)
 }
 
+// VolumeID returns the ID of the volume targeted by the request. This value
+// is valid only for contexts created on the server and is available after the
+// ServiceValidator handler has processed a route with a volumeID variable.
+func VolumeID(ctx context.Context) (string, bool) {
+	return stringValue(ctx, VolumeIDKey)
+}
+
+// QuotaReservation returns the quota reservation of a request that creates a
+// volume. This value is valid only for contexts created on the server and is
+// available after the Quota handler has processed the request.
+func QuotaReservation(ctx context.Context) (types.QuotaReservation, bool) {
+	v, ok := ctx.Value(QuotaReservationKey).(types.QuotaReservation)
+	return v, ok
+}
+
+// AuditRecord returns the audit record of a mutating request. This value is
+// valid only for contexts created on the server and is available after the
+// Audit handler has processed the request.
+func AuditRecord(ctx context.Context) (*types.AuditRecord, bool) {
+	v, ok := ctx.Value(AuditRecordKey).(*types.AuditRecord)
+	return v, ok
+}
+
+// IdempotencyRecord returns the idempotency record of a mutating request. This
+// value is valid only for contexts created on the server and is available
+// after the Idempotency handler has processed the request.
+func IdempotencyRecord(
+	ctx context.Context) (*types.IdempotencyRecord, bool) {
+	v, ok := ctx.Value(IdempotencyRecordKey).(*types.IdempotencyRecord)
+	return v, ok
+}
+
 // ServiceName returns the context's service name. This value is valid for
 // contexts created on both the client and the server. On the server this
 // value is subject to the same restrictions as listed in the Service function.
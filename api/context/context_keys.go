@@ -43,6 +43,25 @@ const (
 	// EncodedAuthTokenKey is the key for an encoded authentication token.
 	EncodedAuthTokenKey
 
+	// QuotaReservationKey is the key for the quota reservation of a request
+	// that creates a volume.
+	QuotaReservationKey
+
+	// AuditRecordKey is the key for the audit record of a mutating request.
+	AuditRecordKey
+
+	// ServerReloadKey is the key for the func() error that reloads the
+	// server's configuration.
+	ServerReloadKey
+
+	// IdempotencyRecordKey is the key for the idempotency record of a
+	// mutating request.
+	IdempotencyRecordKey
+
+	// AuthRolesKey is the key for the names of the roles assigned to the
+	// request's auth token by the server's global auth config.
+	AuthRolesKey
+
 	// keyLoggable is the minimum value from which the succeeding keys should
 	// be checked when logging.
 	keyLoggable
@@ -89,6 +108,9 @@ const (
 	// TLSKey is a context key.
 	TLSKey
 
+	// VolumeIDKey is the key for the ID of the volume targeted by a request.
+	VolumeIDKey
+
 	// keyEOF should always be the final key
 	keyEOF
 )
@@ -116,6 +138,7 @@ var (
 		UserKey:           "user",
 		HostKey:           "host",
 		TLSKey:            "tls",
+		VolumeIDKey:       "volumeID",
 	}
 )
 
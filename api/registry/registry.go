@@ -30,6 +30,12 @@ var (
 
 	routers    = []types.Router{}
 	routersRWL = &sync.RWMutex{}
+
+	taskStoreCtors    = map[string]types.NewTaskStore{}
+	taskStoreCtorsRWL = &sync.RWMutex{}
+
+	auditSinkCtors    = map[string]types.NewAuditSink{}
+	auditSinkCtorsRWL = &sync.RWMutex{}
 )
 
 type cregW struct {
@@ -80,6 +86,20 @@ func RegisterIntegrationDriver(name string, ctor types.NewIntegrationDriver) {
 	intDriverCtors[strings.ToLower(name)] = ctor
 }
 
+// RegisterTaskStore registers a TaskStore.
+func RegisterTaskStore(name string, ctor types.NewTaskStore) {
+	taskStoreCtorsRWL.Lock()
+	defer taskStoreCtorsRWL.Unlock()
+	taskStoreCtors[strings.ToLower(name)] = ctor
+}
+
+// RegisterAuditSink registers an AuditSink.
+func RegisterAuditSink(name string, ctor types.NewAuditSink) {
+	auditSinkCtorsRWL.Lock()
+	defer auditSinkCtorsRWL.Unlock()
+	auditSinkCtors[strings.ToLower(name)] = ctor
+}
+
 // NewStorageExecutor returns a new instance of the executor specified by the
 // executor name.
 func NewStorageExecutor(name string) (types.StorageExecutor, error) {
@@ -160,6 +180,46 @@ func NewIntegrationDriver(name string) (types.IntegrationDriver, error) {
 	return NewIntegrationDriverManager(ctor()), nil
 }
 
+// NewTaskStore returns a new instance of the task store specified by the
+// task store name.
+func NewTaskStore(name string) (types.TaskStore, error) {
+
+	var ok bool
+	var ctor types.NewTaskStore
+
+	func() {
+		taskStoreCtorsRWL.RLock()
+		defer taskStoreCtorsRWL.RUnlock()
+		ctor, ok = taskStoreCtors[strings.ToLower(name)]
+	}()
+
+	if !ok {
+		return nil, goof.WithField("store", name, "invalid task store name")
+	}
+
+	return ctor(), nil
+}
+
+// NewAuditSink returns a new instance of the audit sink specified by the
+// audit sink name.
+func NewAuditSink(name string) (types.AuditSink, error) {
+
+	var ok bool
+	var ctor types.NewAuditSink
+
+	func() {
+		auditSinkCtorsRWL.RLock()
+		defer auditSinkCtorsRWL.RUnlock()
+		ctor, ok = auditSinkCtors[strings.ToLower(name)]
+	}()
+
+	if !ok {
+		return nil, goof.WithField("sink", name, "invalid audit sink name")
+	}
+
+	return ctor(), nil
+}
+
 // ConfigRegs returns a channel on which all registered configuration
 // registrations are returned.
 func ConfigRegs(ctx types.Context) <-chan gofig.ConfigRegistration {
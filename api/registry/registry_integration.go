@@ -1,15 +1,21 @@
 package registry
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
 	"sync"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
 	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
 
 	"github.com/codedellemc/libstorage/api/context"
 	"github.com/codedellemc/libstorage/api/types"
 	apiutils "github.com/codedellemc/libstorage/api/utils"
+	"github.com/codedellemc/libstorage/api/utils/flock"
 )
 
 type idm struct {
@@ -17,7 +23,9 @@ type idm struct {
 	sync.RWMutex
 	ctx        types.Context
 	config     gofig.Config
-	used       map[string]int
+	service    string
+	used       map[string]*types.VolumeRefs
+	refsPath   string
 	retryCount int
 	retryWait  time.Duration
 }
@@ -25,7 +33,7 @@ type idm struct {
 // NewIntegrationDriverManager returns a new integration driver manager.
 func NewIntegrationDriverManager(
 	d types.IntegrationDriver) types.IntegrationDriver {
-	return &idm{IntegrationDriver: d, used: map[string]int{}}
+	return &idm{IntegrationDriver: d, used: map[string]*types.VolumeRefs{}}
 }
 
 func (d *idm) Name() string {
@@ -39,7 +47,9 @@ func (d *idm) Init(ctx types.Context, config gofig.Config) error {
 
 	d.ctx = ctx
 	d.config = config
-	d.used = map[string]int{}
+	d.used = map[string]*types.VolumeRefs{}
+	d.service, _ = context.ServiceName(ctx)
+	d.refsPath = config.GetString(types.ConfigIgVolOpsMountRefsPath)
 	d.retryCount = config.GetInt(types.ConfigIgVolOpsMountRetryCount)
 	if v := config.GetString(types.ConfigIgVolOpsMountRetryWait); v != "" {
 		var err error
@@ -49,6 +59,10 @@ func (d *idm) Init(ctx types.Context, config gofig.Config) error {
 		}
 	}
 
+	if err := d.loadRefs(); err != nil {
+		return err
+	}
+	d.reconcileRefs(ctx)
 	d.initPathCache(ctx)
 
 	ctx.WithFields(log.Fields{
@@ -178,7 +192,7 @@ func (d *idm) Mount(
 		vol.Attachments[0].MountPoint = mp
 	}
 
-	d.incCount(volumeName)
+	d.incCount(volumeName, consumerID(opts.Opts))
 	return mp, vol, err
 }
 
@@ -193,6 +207,17 @@ func (d *idm) Unmount(
 		"opts":       opts}
 	ctx.WithFields(fields).Debug("unmounting volume")
 
+	cid := consumerID(opts)
+
+	// a consumer that does not hold a reference on the volume, such as one
+	// that already unmounted it, may not unmount it from under the consumers
+	// that do
+	if !d.ignoreUsedCount() && cid != "" && !d.isHeldBy(volumeName, cid) {
+		ctx.WithFields(fields).WithField("consumerID", cid).Info(
+			"skipping unmount; consumer does not hold volume")
+		return nil, nil
+	}
+
 	if d.ignoreUsedCount() ||
 		d.resetCount(volumeName) ||
 		!d.isCounted(volumeName) {
@@ -202,10 +227,23 @@ func (d *idm) Unmount(
 			ctx.Join(d.ctx), volumeID, volumeName, opts)
 	}
 
-	d.decCount(volumeName)
+	d.decCount(volumeName, cid)
 	return nil, nil
 }
 
+func (d *idm) Refs(ctx types.Context) (map[string]*types.VolumeRefs, error) {
+	d.RLock()
+	defer d.RUnlock()
+	refs := map[string]*types.VolumeRefs{}
+	for k, v := range d.used {
+		refs[k] = &types.VolumeRefs{
+			Count:     v.Count,
+			Consumers: append([]string{}, v.Consumers...),
+		}
+	}
+	return refs, nil
+}
+
 func (d *idm) Path(
 	ctx types.Context,
 	volumeID, volumeName string,
@@ -295,7 +333,9 @@ func (d *idm) Detach(
 func (d *idm) initCount(volumeName string) {
 	d.Lock()
 	defer d.Unlock()
-	d.used[volumeName] = 0
+	d.updateRefs(func() {
+		d.used[volumeName] = &types.VolumeRefs{}
+	})
 	d.ctx.WithFields(log.Fields{
 		"volumeName": volumeName,
 		"count":      0,
@@ -305,32 +345,65 @@ func (d *idm) initCount(volumeName string) {
 func (d *idm) resetCount(volumeName string) bool {
 	d.Lock()
 	defer d.Unlock()
-	c, ok := d.used[volumeName]
-	if ok && c < 2 {
+	reset := false
+	d.updateRefs(func() {
+		r, ok := d.used[volumeName]
+		if !ok || r.Count >= 2 {
+			return
+		}
 		d.ctx.WithFields(log.Fields{
 			"volumeName": volumeName,
-			"count":      c,
+			"count":      r.Count,
+			"consumers":  r.Consumers,
 		}).Info("count reset")
-		d.used[volumeName] = 0
-		return true
-	}
-	return false
+		d.used[volumeName] = &types.VolumeRefs{}
+		reset = true
+	})
+	return reset
 }
 
-func (d *idm) addCount(volumeName string, delta int) {
+func (d *idm) addCount(volumeName, consumerID string, delta int) {
 	d.Lock()
 	defer d.Unlock()
-	c, ok := d.used[volumeName]
-	if ok {
-		c = c + delta
-	} else {
-		c = 1
+	d.updateRefs(func() {
+		r, ok := d.used[volumeName]
+		if !ok {
+			r = &types.VolumeRefs{}
+			d.used[volumeName] = r
+			delta = 1
+		}
+		r.Count = r.Count + delta
+		if r.Count < 0 {
+			r.Count = 0
+		}
+		if delta > 0 && consumerID != "" {
+			r.Consumers = append(r.Consumers, consumerID)
+		} else if delta < 0 {
+			removeConsumer(r, consumerID)
+		}
+		d.ctx.WithFields(log.Fields{
+			"volumeName": volumeName,
+			"count":      r.Count,
+			"consumers":  r.Consumers,
+		}).Debug("set count")
+	})
+}
+
+// removeConsumer removes the consumer's reference from the volume's
+// consumers. If the consumer is not listed the most recent consumer is
+// removed when there are more consumers than references.
+func removeConsumer(r *types.VolumeRefs, consumerID string) {
+	if consumerID != "" {
+		for i, c := range r.Consumers {
+			if c == consumerID {
+				r.Consumers = append(r.Consumers[:i], r.Consumers[i+1:]...)
+				return
+			}
+		}
+	}
+	if len(r.Consumers) > r.Count {
+		r.Consumers = r.Consumers[:r.Count]
 	}
-	d.used[volumeName] = c
-	d.ctx.WithFields(log.Fields{
-		"volumeName": volumeName,
-		"count":      c,
-	}).Debug("set count")
 }
 
 func (d *idm) isCounted(volumeName string) bool {
@@ -340,12 +413,197 @@ func (d *idm) isCounted(volumeName string) bool {
 	return ok
 }
 
-func (d *idm) incCount(volumeName string) {
-	d.addCount(volumeName, 1)
+// isHeldBy returns a flag indicating whether the consumer may hold a
+// reference on the volume. References taken without a consumer ID may be
+// held by any consumer.
+func (d *idm) isHeldBy(volumeName, consumerID string) bool {
+	d.RLock()
+	defer d.RUnlock()
+	r, ok := d.used[volumeName]
+	if !ok {
+		return true
+	}
+	if r.Count > len(r.Consumers) {
+		return true
+	}
+	for _, c := range r.Consumers {
+		if c == consumerID {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *idm) incCount(volumeName, consumerID string) {
+	d.addCount(volumeName, consumerID, 1)
+}
+
+func (d *idm) decCount(volumeName, consumerID string) {
+	d.addCount(volumeName, consumerID, -1)
+}
+
+// mountRefs is the format of the file to which the references are
+// persisted. The file may be shared by processes that mount the volumes of
+// different services, so the references are recorded by service.
+type mountRefs struct {
+	Services map[string]map[string]*types.VolumeRefs `json:"services"`
+}
+
+// mountRefsLockTimeout is how long a process waits for the other processes
+// that share the mount refs file to update it.
+const mountRefsLockTimeout = time.Duration(30) * time.Second
+
+// readRefs reads the references of all of the services from the mount refs
+// file. The caller must hold the file's lock.
+func (d *idm) readRefs() (*mountRefs, error) {
+	refs := &mountRefs{}
+	buf, err := ioutil.ReadFile(d.refsPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, goof.WithFieldE(
+			"path", d.refsPath, "error reading mount refs", err)
+	}
+	if len(buf) > 0 {
+		if err := json.Unmarshal(buf, refs); err != nil {
+			return nil, goof.WithFieldE(
+				"path", d.refsPath, "error unmarshaling mount refs", err)
+		}
+	}
+	if refs.Services == nil {
+		refs.Services = map[string]map[string]*types.VolumeRefs{}
+	}
+	return refs, nil
+}
+
+// lockRefs acquires the lock that excludes the other processes that share
+// the mount refs file.
+func (d *idm) lockRefs() (*flock.Lock, error) {
+	if err := os.MkdirAll(path.Dir(d.refsPath), 0755); err != nil {
+		return nil, goof.WithFieldE(
+			"path", d.refsPath, "error creating mount refs dir", err)
+	}
+	return flock.Acquire(d.refsPath+".lock", mountRefsLockTimeout)
+}
+
+// loadRefs loads the service's references persisted by a previous process.
+func (d *idm) loadRefs() error {
+	if d.refsPath == "" {
+		return nil
+	}
+	lock, err := d.lockRefs()
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+	refs, err := d.readRefs()
+	if err != nil {
+		return err
+	}
+	d.setRefs(refs)
+	return nil
+}
+
+// setRefs replaces the references with the service's persisted references.
+func (d *idm) setRefs(refs *mountRefs) {
+	d.used = map[string]*types.VolumeRefs{}
+	for k, v := range refs.Services[d.service] {
+		if v != nil {
+			d.used[k] = v
+		}
+	}
+}
+
+// updateRefs updates the references with the given function and persists
+// them. The references are first reloaded from the mount refs file so that
+// the updates made by the other processes that share it are not lost. The
+// caller must hold the lock.
+func (d *idm) updateRefs(update func()) {
+	if d.refsPath == "" {
+		update()
+		return
+	}
+
+	lock, err := d.lockRefs()
+	if err != nil {
+		d.ctx.WithError(err).Error("error locking mount refs")
+		update()
+		return
+	}
+	defer lock.Release()
+
+	refs, err := d.readRefs()
+	if err != nil {
+		d.ctx.WithError(err).Error("error reloading mount refs")
+		update()
+		return
+	}
+	d.setRefs(refs)
+	update()
+
+	if len(d.used) > 0 {
+		refs.Services[d.service] = d.used
+	} else {
+		delete(refs.Services, d.service)
+	}
+	buf, err := json.Marshal(refs)
+	if err != nil {
+		d.ctx.WithError(err).Error("error marshaling mount refs")
+		return
+	}
+	if err := apiutils.WriteFileAtomic(d.refsPath, buf); err != nil {
+		d.ctx.WithError(err).Error("error persisting mount refs")
+	}
+}
+
+// reconcileRefs discards the persisted references on volumes that are no
+// longer mounted.
+func (d *idm) reconcileRefs(ctx types.Context) {
+	if len(d.used) == 0 {
+		return
+	}
+
+	if d.service == "" {
+		ctx.Info("mount refs reconciliation disabled; no service name in ctx")
+		return
+	}
+
+	volMaps, err := d.IntegrationDriver.List(
+		ctx, apiutils.NewStoreWithData(initPathCacheMap))
+	if err != nil {
+		ctx.WithError(err).Error("error reconciling mount refs")
+		return
+	}
+
+	mounted := map[string]bool{}
+	for _, vm := range volMaps {
+		if vm.MountPoint() != "" {
+			mounted[vm.VolumeName()] = true
+		}
+	}
+
+	// only the references of the service's volumes are reconciled, since
+	// those are the only volumes that are listed
+	d.Lock()
+	defer d.Unlock()
+	d.updateRefs(func() {
+		for volumeName, r := range d.used {
+			if mounted[volumeName] {
+				continue
+			}
+			ctx.WithFields(log.Fields{
+				"volumeName": volumeName,
+				"count":      r.Count,
+				"consumers":  r.Consumers,
+			}).Info("discarded refs of unmounted volume")
+			delete(d.used, volumeName)
+		}
+	})
 }
 
-func (d *idm) decCount(volumeName string) {
-	d.addCount(volumeName, -1)
+func consumerID(opts types.Store) string {
+	if opts == nil {
+		return ""
+	}
+	return opts.GetString(types.VolumeConsumerIDOptKey)
 }
 
 func (d *idm) preempt() bool {
@@ -0,0 +1,236 @@
+package registry
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	gofigCore "github.com/akutz/gofig"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+// testIntegrationDriver mounts volumes by recording their names.
+type testIntegrationDriver struct {
+	types.IntegrationDriver
+	mounted  map[string]bool
+	unmounts int
+}
+
+func newTestIntegrationDriver(mounted ...string) *testIntegrationDriver {
+	d := &testIntegrationDriver{mounted: map[string]bool{}}
+	for _, v := range mounted {
+		d.mounted[v] = true
+	}
+	return d
+}
+
+func (d *testIntegrationDriver) Name() string {
+	return "test"
+}
+
+func (d *testIntegrationDriver) Init(
+	ctx types.Context, config gofig.Config) error {
+	return nil
+}
+
+func (d *testIntegrationDriver) List(
+	ctx types.Context,
+	opts types.Store) ([]types.VolumeMapping, error) {
+
+	volMaps := []types.VolumeMapping{}
+	for name := range d.mounted {
+		volMaps = append(volMaps, &types.Volume{
+			Name: name,
+			Attachments: []*types.VolumeAttachment{
+				{MountPoint: path.Join("/mnt", name)},
+			},
+		})
+	}
+	return volMaps, nil
+}
+
+func (d *testIntegrationDriver) Mount(
+	ctx types.Context,
+	volumeID, volumeName string,
+	opts *types.VolumeMountOpts) (string, *types.Volume, error) {
+
+	d.mounted[volumeName] = true
+	return path.Join("/mnt", volumeName), &types.Volume{Name: volumeName}, nil
+}
+
+func (d *testIntegrationDriver) Unmount(
+	ctx types.Context,
+	volumeID, volumeName string,
+	opts types.Store) (*types.Volume, error) {
+
+	delete(d.mounted, volumeName)
+	d.unmounts++
+	return nil, nil
+}
+
+func newTestIntegrationDriverManager(
+	t *testing.T,
+	service, refsPath string,
+	d *testIntegrationDriver) *idm {
+
+	config := gofigCore.New()
+	config.Set(types.ConfigIgVolOpsMountRefsPath, refsPath)
+
+	ctx := context.Background()
+	if service != "" {
+		ctx = ctx.WithValue(context.ServiceKey, service)
+	}
+
+	m := NewIntegrationDriverManager(d).(*idm)
+	if err := m.Init(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func newTestRefsPath(t *testing.T) (string, string) {
+	dir, err := ioutil.TempDir("", "libstorage-refs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path.Join(dir, "mountrefs.json"), dir
+}
+
+func consumerOpts(consumerID string) types.Store {
+	return utils.NewStoreWithData(map[string]interface{}{
+		types.VolumeConsumerIDOptKey: consumerID,
+	})
+}
+
+func mountTestVolume(
+	t *testing.T, m *idm, volumeName, consumerID string) {
+
+	_, _, err := m.Mount(
+		context.Background(), "", volumeName,
+		&types.VolumeMountOpts{Opts: consumerOpts(consumerID)})
+	assert.NoError(t, err)
+}
+
+func unmountTestVolume(
+	t *testing.T, m *idm, volumeName, consumerID string) {
+
+	_, err := m.Unmount(
+		context.Background(), "", volumeName, consumerOpts(consumerID))
+	assert.NoError(t, err)
+}
+
+func getTestRefs(t *testing.T, m *idm) map[string]*types.VolumeRefs {
+	refs, err := m.Refs(context.Background())
+	assert.NoError(t, err)
+	return refs
+}
+
+func TestIntegrationRefsPersisted(t *testing.T) {
+	refsPath, dir := newTestRefsPath(t)
+	defer os.RemoveAll(dir)
+
+	d := newTestIntegrationDriver()
+	m := newTestIntegrationDriverManager(t, "vfs", refsPath, d)
+	mountTestVolume(t, m, "vol-000", "c-000")
+	mountTestVolume(t, m, "vol-000", "c-001")
+
+	refs := getTestRefs(t, m)
+	if assert.Contains(t, refs, "vol-000") {
+		assert.Equal(t, 2, refs["vol-000"].Count)
+		assert.Equal(t,
+			[]string{"c-000", "c-001"}, refs["vol-000"].Consumers)
+	}
+
+	// the references are loaded by the next process
+	m = newTestIntegrationDriverManager(t, "vfs", refsPath, d)
+	assert.Equal(t, refs, getTestRefs(t, m))
+
+	// and the references returned are copies
+	getTestRefs(t, m)["vol-000"].Consumers[0] = "c-002"
+	assert.Equal(t, "c-000", getTestRefs(t, m)["vol-000"].Consumers[0])
+}
+
+func TestIntegrationRefsSharedFile(t *testing.T) {
+	refsPath, dir := newTestRefsPath(t)
+	defer os.RemoveAll(dir)
+
+	// the processes that share the file do not discard each other's
+	// references
+	d := newTestIntegrationDriver()
+	m1 := newTestIntegrationDriverManager(t, "vfs", refsPath, d)
+	m2 := newTestIntegrationDriverManager(t, "vfs", refsPath, d)
+	m3 := newTestIntegrationDriverManager(t, "ebs", refsPath, d)
+	mountTestVolume(t, m1, "vol-000", "c-000")
+	mountTestVolume(t, m2, "vol-001", "c-001")
+	mountTestVolume(t, m3, "vol-002", "c-002")
+
+	m := newTestIntegrationDriverManager(t, "vfs", refsPath, d)
+	refs := getTestRefs(t, m)
+	assert.Len(t, refs, 2)
+	assert.Contains(t, refs, "vol-000")
+	assert.Contains(t, refs, "vol-001")
+
+	m = newTestIntegrationDriverManager(t, "ebs", refsPath, d)
+	refs = getTestRefs(t, m)
+	assert.Len(t, refs, 1)
+	assert.Contains(t, refs, "vol-002")
+}
+
+func TestIntegrationRefsReconcile(t *testing.T) {
+	refsPath, dir := newTestRefsPath(t)
+	defer os.RemoveAll(dir)
+
+	d := newTestIntegrationDriver()
+	m := newTestIntegrationDriverManager(t, "vfs", refsPath, d)
+	mountTestVolume(t, m, "vol-000", "c-000")
+	mountTestVolume(t, m, "vol-001", "c-001")
+	m = newTestIntegrationDriverManager(t, "ebs", refsPath, d)
+	mountTestVolume(t, m, "vol-002", "c-002")
+
+	// the references on the service's volumes that are no longer mounted
+	// are discarded
+	m = newTestIntegrationDriverManager(
+		t, "vfs", refsPath, newTestIntegrationDriver("vol-000"))
+	refs := getTestRefs(t, m)
+	assert.Len(t, refs, 1)
+	assert.Contains(t, refs, "vol-000")
+
+	// while those on the volumes of the other services are not
+	m = newTestIntegrationDriverManager(
+		t, "ebs", refsPath, newTestIntegrationDriver("vol-002"))
+	refs = getTestRefs(t, m)
+	assert.Len(t, refs, 1)
+	assert.Contains(t, refs, "vol-002")
+}
+
+func TestIntegrationRefsConsumerUnmount(t *testing.T) {
+	d := newTestIntegrationDriver()
+	m := newTestIntegrationDriverManager(t, "vfs", "", d)
+	mountTestVolume(t, m, "vol-000", "c-000")
+	mountTestVolume(t, m, "vol-000", "c-001")
+
+	// a consumer that does not hold the volume does not unmount it
+	unmountTestVolume(t, m, "vol-000", "c-002")
+	assert.Equal(t, 0, d.unmounts)
+	assert.Equal(t, 2, getTestRefs(t, m)["vol-000"].Count)
+
+	// a consumer that holds the volume releases its reference
+	unmountTestVolume(t, m, "vol-000", "c-000")
+	assert.Equal(t, 0, d.unmounts)
+	refs := getTestRefs(t, m)
+	assert.Equal(t, 1, refs["vol-000"].Count)
+	assert.Equal(t, []string{"c-001"}, refs["vol-000"].Consumers)
+
+	// and the volume is unmounted once the last reference is released
+	unmountTestVolume(t, m, "vol-000", "c-000")
+	assert.Equal(t, 0, d.unmounts)
+	unmountTestVolume(t, m, "vol-000", "c-001")
+	assert.Equal(t, 1, d.unmounts)
+	assert.Equal(t, 0, getTestRefs(t, m)["vol-000"].Count)
+}
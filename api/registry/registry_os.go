@@ -69,3 +69,25 @@ func (d *odm) Format(
 
 	return d.OSDriver.Format(ctx, deviceName, opts)
 }
+
+func (d *odm) GrowFS(
+	ctx types.Context,
+	deviceName, mountPoint string,
+	opts types.Store) error {
+
+	ctx = ctx.Join(d.Context)
+
+	if !path.IsAbs(deviceName) {
+		return nil
+	}
+
+	if _, err := os.Stat(deviceName); os.IsNotExist(err) {
+		return nil
+	}
+
+	dgf, ok := d.OSDriver.(types.OSDriverWithGrowFS)
+	if !ok {
+		return types.ErrNotImplemented
+	}
+	return dgf.GrowFS(ctx, deviceName, mountPoint, opts)
+}
@@ -0,0 +1,298 @@
+package registry
+
+import (
+	"time"
+
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils/metrics"
+)
+
+var (
+	storDriverCallDuration = metrics.NewHistogramVec(
+		"libstorage_storage_driver_call_duration_seconds",
+		"The latency of storage driver calls.",
+		nil, "driver", "method")
+
+	storDriverCallErrors = metrics.NewCounterVec(
+		"libstorage_storage_driver_call_errors_total",
+		"The number of storage driver calls that returned an error.",
+		"driver", "method")
+)
+
+// sdm is a storage driver manager that records the latency and errors of
+// the calls to the driver it wraps and publishes an event for each volume
+// and snapshot the driver changes.
+type sdm struct {
+	types.StorageDriver
+	events types.EventFunc
+}
+
+// NewStorageDriverManager returns a new storage driver manager. Optional
+// driver interfaces, such as types.StorageDriverWithLogin, must be asserted
+// against the manager's wrapped driver, except for
+// types.StorageDriverWithVolumeResize, which the manager implements. The
+// events function may be nil.
+func NewStorageDriverManager(
+	d types.StorageDriver,
+	events types.EventFunc) types.StorageDriverManager {
+	return &sdm{StorageDriver: d, events: events}
+}
+
+// UnwrapStorageDriver returns the driver wrapped by a storage driver manager
+// or, if d is not a manager, d itself.
+func UnwrapStorageDriver(d types.StorageDriver) types.StorageDriver {
+	if m, ok := d.(types.StorageDriverManager); ok {
+		return m.Driver()
+	}
+	return d
+}
+
+func (d *sdm) Driver() types.StorageDriver {
+	return d.StorageDriver
+}
+
+// observe records the latency of the call to the method that started at the
+// specified time as well as the error returned by the call, if any.
+func (d *sdm) observe(method string, start time.Time, err error) {
+	name := d.StorageDriver.Name()
+	storDriverCallDuration.ObserveSince(start, name, method)
+	if err != nil {
+		storDriverCallErrors.Inc(name, method)
+	}
+}
+
+// publish publishes an event of the specified type for the volume and
+// snapshot.
+func (d *sdm) publish(
+	ctx types.Context,
+	eventType types.EventType,
+	volumeID, snapshotID string) {
+
+	if d.events == nil {
+		return
+	}
+	d.events(ctx, &types.Event{
+		Type:       eventType,
+		VolumeID:   volumeID,
+		SnapshotID: snapshotID,
+	})
+}
+
+func (d *sdm) NextDeviceInfo(
+	ctx types.Context) (*types.NextDeviceInfo, error) {
+
+	start := time.Now()
+	v, err := d.StorageDriver.NextDeviceInfo(ctx)
+	d.observe("NextDeviceInfo", start, err)
+	return v, err
+}
+
+func (d *sdm) Type(ctx types.Context) (types.StorageType, error) {
+	start := time.Now()
+	v, err := d.StorageDriver.Type(ctx)
+	d.observe("Type", start, err)
+	return v, err
+}
+
+func (d *sdm) InstanceInspect(
+	ctx types.Context,
+	opts types.Store) (*types.Instance, error) {
+
+	start := time.Now()
+	v, err := d.StorageDriver.InstanceInspect(ctx, opts)
+	d.observe("InstanceInspect", start, err)
+	return v, err
+}
+
+func (d *sdm) Volumes(
+	ctx types.Context,
+	opts *types.VolumesOpts) ([]*types.Volume, error) {
+
+	start := time.Now()
+	v, err := d.StorageDriver.Volumes(ctx, opts)
+	d.observe("Volumes", start, err)
+	return v, err
+}
+
+func (d *sdm) VolumeInspect(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeInspectOpts) (*types.Volume, error) {
+
+	start := time.Now()
+	v, err := d.StorageDriver.VolumeInspect(ctx, volumeID, opts)
+	d.observe("VolumeInspect", start, err)
+	return v, err
+}
+
+func (d *sdm) VolumeCreate(
+	ctx types.Context,
+	name string,
+	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+
+	start := time.Now()
+	v, err := d.StorageDriver.VolumeCreate(ctx, name, opts)
+	d.observe("VolumeCreate", start, err)
+	if err == nil && v != nil {
+		d.publish(ctx, types.EventVolumeCreated, v.ID, "")
+	}
+	return v, err
+}
+
+func (d *sdm) VolumeCreateFromSnapshot(
+	ctx types.Context,
+	snapshotID, volumeName string,
+	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+
+	start := time.Now()
+	v, err := d.StorageDriver.VolumeCreateFromSnapshot(
+		ctx, snapshotID, volumeName, opts)
+	d.observe("VolumeCreateFromSnapshot", start, err)
+	if err == nil && v != nil {
+		d.publish(ctx, types.EventVolumeCreated, v.ID, snapshotID)
+	}
+	return v, err
+}
+
+func (d *sdm) VolumeCopy(
+	ctx types.Context,
+	volumeID, volumeName string,
+	opts types.Store) (*types.Volume, error) {
+
+	start := time.Now()
+	v, err := d.StorageDriver.VolumeCopy(ctx, volumeID, volumeName, opts)
+	d.observe("VolumeCopy", start, err)
+	if err == nil && v != nil {
+		d.publish(ctx, types.EventVolumeCreated, v.ID, "")
+	}
+	return v, err
+}
+
+func (d *sdm) VolumeSnapshot(
+	ctx types.Context,
+	volumeID, snapshotName string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	start := time.Now()
+	v, err := d.StorageDriver.VolumeSnapshot(
+		ctx, volumeID, snapshotName, opts)
+	d.observe("VolumeSnapshot", start, err)
+	if err == nil && v != nil {
+		d.publish(ctx, types.EventSnapshotCreated, volumeID, v.ID)
+	}
+	return v, err
+}
+
+func (d *sdm) VolumeRemove(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeRemoveOpts) error {
+
+	start := time.Now()
+	err := d.StorageDriver.VolumeRemove(ctx, volumeID, opts)
+	d.observe("VolumeRemove", start, err)
+	if err == nil {
+		d.publish(ctx, types.EventVolumeRemoved, volumeID, "")
+	}
+	return err
+}
+
+func (d *sdm) VolumeAttach(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeAttachOpts) (*types.Volume, string, error) {
+
+	start := time.Now()
+	v, tok, err := d.StorageDriver.VolumeAttach(ctx, volumeID, opts)
+	d.observe("VolumeAttach", start, err)
+	if err == nil {
+		d.publish(ctx, types.EventVolumeAttached, volumeID, "")
+	}
+	return v, tok, err
+}
+
+func (d *sdm) VolumeDetach(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeDetachOpts) (*types.Volume, error) {
+
+	start := time.Now()
+	v, err := d.StorageDriver.VolumeDetach(ctx, volumeID, opts)
+	d.observe("VolumeDetach", start, err)
+	if err == nil {
+		d.publish(ctx, types.EventVolumeDetached, volumeID, "")
+	}
+	return v, err
+}
+
+// VolumeResize resizes the volume if the wrapped driver is a
+// types.StorageDriverWithVolumeResize and otherwise returns
+// types.ErrNotImplemented.
+func (d *sdm) VolumeResize(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeResizeOpts) (*types.Volume, error) {
+
+	sd, ok := d.StorageDriver.(types.StorageDriverWithVolumeResize)
+	if !ok {
+		return nil, types.ErrNotImplemented
+	}
+
+	start := time.Now()
+	v, err := sd.VolumeResize(ctx, volumeID, opts)
+	d.observe("VolumeResize", start, err)
+	if err == nil {
+		d.publish(ctx, types.EventVolumeResized, volumeID, "")
+	}
+	return v, err
+}
+
+func (d *sdm) Snapshots(
+	ctx types.Context,
+	opts types.Store) ([]*types.Snapshot, error) {
+
+	start := time.Now()
+	v, err := d.StorageDriver.Snapshots(ctx, opts)
+	d.observe("Snapshots", start, err)
+	return v, err
+}
+
+func (d *sdm) SnapshotInspect(
+	ctx types.Context,
+	snapshotID string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	start := time.Now()
+	v, err := d.StorageDriver.SnapshotInspect(ctx, snapshotID, opts)
+	d.observe("SnapshotInspect", start, err)
+	return v, err
+}
+
+func (d *sdm) SnapshotCopy(
+	ctx types.Context,
+	snapshotID, snapshotName, destinationID string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	start := time.Now()
+	v, err := d.StorageDriver.SnapshotCopy(
+		ctx, snapshotID, snapshotName, destinationID, opts)
+	d.observe("SnapshotCopy", start, err)
+	if err == nil && v != nil {
+		d.publish(ctx, types.EventSnapshotCreated, v.VolumeID, v.ID)
+	}
+	return v, err
+}
+
+func (d *sdm) SnapshotRemove(
+	ctx types.Context,
+	snapshotID string,
+	opts types.Store) error {
+
+	start := time.Now()
+	err := d.StorageDriver.SnapshotRemove(ctx, snapshotID, opts)
+	d.observe("SnapshotRemove", start, err)
+	if err == nil {
+		d.publish(ctx, types.EventSnapshotRemoved, "", snapshotID)
+	}
+	return err
+}
@@ -0,0 +1,62 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+type testStorageDriver struct {
+	types.StorageDriver
+}
+
+func (d *testStorageDriver) Name() string {
+	return "test"
+}
+
+type testResizeDriver struct {
+	testStorageDriver
+}
+
+func (d *testResizeDriver) VolumeResize(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeResizeOpts) (*types.Volume, error) {
+
+	return &types.Volume{ID: volumeID, Size: *opts.Size}, nil
+}
+
+func TestStorageDriverManagerVolumeResize(t *testing.T) {
+	var events []*types.Event
+	publish := func(ctx types.Context, e *types.Event) {
+		events = append(events, e)
+	}
+
+	ctx := context.Background()
+	size := int64(20)
+	opts := &types.VolumeResizeOpts{Size: &size}
+
+	// the resize of a driver that can resize volumes is published
+	m := NewStorageDriverManager(&testResizeDriver{}, publish)
+	d, ok := m.(types.StorageDriverWithVolumeResize)
+	if !assert.True(t, ok) {
+		t.FailNow()
+	}
+	v, err := d.VolumeResize(ctx, "vol-000", opts)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20), v.Size)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, types.EventVolumeResized, events[0].Type)
+		assert.Equal(t, "vol-000", events[0].VolumeID)
+	}
+
+	// while a driver that cannot resize volumes is not implemented
+	m = NewStorageDriverManager(&testStorageDriver{}, publish)
+	d = m.(types.StorageDriverWithVolumeResize)
+	_, err = d.VolumeResize(ctx, "vol-000", opts)
+	assert.Equal(t, types.ErrNotImplemented, err)
+	assert.Len(t, events, 1)
+}
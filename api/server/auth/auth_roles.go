@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"strings"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+// readerRoutes are the names of the routes that do not modify any resources.
+var readerRoutes = []string{
+	"root",
+	"version",
+	"services",
+	"serviceInspect",
+	"executors",
+	"executorInspect",
+	"executorHead",
+	"volumes",
+	"volumesForService",
+	"volumeInspect",
+	"snapshots",
+	"snapshotsForService",
+	"snapshotInspect",
+	"tasks",
+	"taskInspect",
+	"quotas",
+	"metrics",
+	"events",
+}
+
+// operatorRoutes are the names of the routes that create, modify, attach,
+// and detach resources.
+var operatorRoutes = []string{
+	"volumeCreate",
+	"volumeCopy",
+	"volumeResize",
+	"volumeSnapshot",
+	"volumeAttach",
+	"volumeDetach",
+	"volumesDetachAll",
+	"volumesDetachForService",
+	"snapshotCreate",
+	"snapshotCopy",
+	"taskCancel",
+}
+
+// builtinRoles maps the names of the built-in roles to the names of the
+// routes they may access.
+var builtinRoles = map[string][]string{
+	types.AuthRoleReader: readerRoutes,
+	types.AuthRoleOperator: append(
+		append([]string{}, readerRoutes...), operatorRoutes...),
+	types.AuthRoleAdmin: {"*"},
+}
+
+// validateAuthTokenRoute returns an error if none of the roles assigned to
+// the token grant access to the route in the context. No error is returned
+// if the context does not have a route.
+func validateAuthTokenRoute(
+	ctx types.Context,
+	config *types.AuthConfig,
+	logFields map[string]interface{},
+	tok *types.AuthToken) error {
+
+	route, ok := context.Route(ctx)
+	if !ok {
+		return nil
+	}
+	routeName := route.GetName()
+
+	roles := GetRoles(config, tok)
+	if len(roles) == 0 {
+		return nil
+	}
+
+	if rolesGrantRoute(config, roles, routeName) {
+		return nil
+	}
+
+	logFields["route"] = routeName
+	logFields["roles"] = roles
+	ctx.WithFields(logFields).Error("access to route not granted")
+	return utils.NewForbiddenError(tok.Subject, routeName)
+}
+
+// GetRoles returns the roles assigned to the token by the auth config and the
+// token's roles claim. The auth config's default roles are returned if the
+// token is not assigned any roles.
+func GetRoles(config *types.AuthConfig, tok *types.AuthToken) []string {
+	roles := getAssignedRoles(config, tok)
+	if len(roles) == 0 {
+		return config.DefaultRoles
+	}
+	return roles
+}
+
+// getAssignedRoles returns the roles assigned to the token by the auth config
+// and the token's roles claim.
+func getAssignedRoles(config *types.AuthConfig, tok *types.AuthToken) []string {
+	roles := append([]string{}, tok.Roles...)
+	for k, v := range config.Subjects {
+		if strings.EqualFold(getSubject(k), tok.Subject) {
+			roles = append(roles, v...)
+		}
+	}
+	return roles
+}
+
+// rolesGrantRoute returns a flag indicating whether or not any of the roles
+// may access the route.
+func rolesGrantRoute(
+	config *types.AuthConfig, roles []string, routeName string) bool {
+
+	for _, role := range roles {
+		for _, v := range getRoleRoutes(config, role) {
+			if v == "*" || strings.EqualFold(v, routeName) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasRole returns a flag indicating whether or not the role is one of the
+// roles.
+func HasRole(roles []string, role string) bool {
+	for _, v := range roles {
+		if strings.EqualFold(v, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// getRoleRoutes returns the names of the routes the role may access.
+func getRoleRoutes(config *types.AuthConfig, role string) []string {
+	for k, v := range config.Roles {
+		if strings.EqualFold(k, role) {
+			return v
+		}
+	}
+	return builtinRoles[strings.ToLower(role)]
+}
+
+// parseRolesClaim parses the value of a JWT's roles claim, which is either
+// an array of role names or a comma-separated list of role names.
+func parseRolesClaim(v interface{}) []string {
+	var roles []string
+	switch tv := v.(type) {
+	case string:
+		for _, r := range strings.Split(tv, ",") {
+			if r = strings.TrimSpace(r); r != "" {
+				roles = append(roles, r)
+			}
+		}
+	case []string:
+		roles = tv
+	case []interface{}:
+		for _, r := range tv {
+			if s, ok := r.(string); ok && s != "" {
+				roles = append(roles, s)
+			}
+		}
+	}
+	return roles
+}
@@ -92,6 +92,15 @@ func validateAuthTokenAllowed(
 	}
 	for _, v := range config.Allow {
 		if strings.EqualFold(getSubject(v), tok.Subject) {
+			return validateAuthTokenRoute(ctx, config, logFields, tok)
+		}
+	}
+
+	// a token whose subject is not allowed may still access the routes
+	// granted by the roles assigned to it, though not by the default roles
+	if route, ok := context.Route(ctx); ok {
+		roles := getAssignedRoles(config, tok)
+		if rolesGrantRoute(config, roles, route.GetName()) {
 			return nil
 		}
 	}
@@ -174,12 +183,16 @@ func ValidateAuthTokenWithJWT(
 		IssuedAt:  iat.UTC().Unix(),
 		Expires:   exp.UTC().Unix(),
 		NotBefore: nbf.UTC().Unix(),
+		Roles:     parseRolesClaim(jwt.Claims().Get("roles")),
 	}
 
 	lf["sub"] = tok.Subject
 	lf["iat"] = tok.IssuedAt
 	lf["exp"] = tok.Expires
 	lf["nbf"] = tok.NotBefore
+	if len(tok.Roles) > 0 {
+		lf["roles"] = tok.Roles
+	}
 
 	if err := validateAuthTokenAllowed(ctx, config, lf, tok); err != nil {
 		return nil, err
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/server/httputils"
 	"github.com/codedellemc/libstorage/api/types"
 )
 
@@ -97,3 +98,140 @@ func TestValidateAuthToken_InvalidKey(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func newRouteContext(name string) types.Context {
+	return context.Background().WithValue(
+		context.RouteKey, httputils.NewGetRoute(name, "/", nil))
+}
+
+func TestValidateAuthToken_ReaderRoleAllowed(t *testing.T) {
+	sc := &types.AuthConfig{
+		Key:      []byte(jwtKey),
+		Alg:      jwtAlg,
+		Allow:    []string{"akutz"},
+		Subjects: map[string][]string{"akutz": {types.AuthRoleReader}},
+	}
+	tok, err := ValidateAuthTokenWithJWT(
+		newRouteContext("volumes"), sc, jwtAkutz)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	if !assert.NotNil(t, tok) {
+		t.FailNow()
+	}
+}
+
+func TestValidateAuthToken_ReaderRoleForbidden(t *testing.T) {
+	sc := &types.AuthConfig{
+		Key:      []byte(jwtKey),
+		Alg:      jwtAlg,
+		Allow:    []string{"akutz"},
+		Subjects: map[string][]string{"akutz": {types.AuthRoleReader}},
+	}
+	tok, err := ValidateAuthTokenWithJWT(
+		newRouteContext("volumeRemove"), sc, jwtAkutz)
+	if !assert.Error(t, err) {
+		t.FailNow()
+	}
+	if !assert.Nil(t, tok) {
+		t.FailNow()
+	}
+	if !assert.IsType(t, &types.ErrForbidden{}, err) {
+		t.FailNow()
+	}
+}
+
+func TestValidateAuthToken_CustomRole(t *testing.T) {
+	sc := &types.AuthConfig{
+		Key:          []byte(jwtKey),
+		Alg:          jwtAlg,
+		Allow:        []string{"akutz"},
+		Roles:        map[string][]string{"remover": {"volumeRemove"}},
+		DefaultRoles: []string{"remover"},
+	}
+	_, err := ValidateAuthTokenWithJWT(
+		newRouteContext("volumeRemove"), sc, jwtAkutz)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	_, err = ValidateAuthTokenWithJWT(
+		newRouteContext("volumeCreate"), sc, jwtAkutz)
+	if !assert.IsType(t, &types.ErrForbidden{}, err) {
+		t.FailNow()
+	}
+}
+
+func TestValidateAuthToken_NoRoles(t *testing.T) {
+	sc := &types.AuthConfig{
+		Key:   []byte(jwtKey),
+		Alg:   jwtAlg,
+		Allow: []string{"akutz"},
+	}
+	_, err := ValidateAuthTokenWithJWT(
+		newRouteContext("volumeRemove"), sc, jwtAkutz)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+}
+
+func TestValidateAuthToken_RoleNotInAllowList(t *testing.T) {
+	sc := &types.AuthConfig{
+		Key:          []byte(jwtKey),
+		Alg:          jwtAlg,
+		Subjects:     map[string][]string{"akutz": {types.AuthRoleReader}},
+		DefaultRoles: []string{types.AuthRoleAdmin},
+	}
+
+	// the roles assigned to a token grant it access to their routes even
+	// though its subject is not allowed
+	tok, err := ValidateAuthTokenWithJWT(
+		newRouteContext("volumes"), sc, jwtAkutz)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	if !assert.NotNil(t, tok) {
+		t.FailNow()
+	}
+
+	// but not to the routes their roles do not grant
+	_, err = ValidateAuthTokenWithJWT(
+		newRouteContext("volumeRemove"), sc, jwtAkutz)
+	if !assert.IsType(t, &types.ErrSecTokInvalid{}, err) {
+		t.FailNow()
+	}
+
+	// and the default roles do not grant access to the tokens that are
+	// not allowed
+	_, err = ValidateAuthTokenWithJWT(
+		newRouteContext("volumes"), sc, jwtCduchesne)
+	if !assert.IsType(t, &types.ErrSecTokInvalid{}, err) {
+		t.FailNow()
+	}
+}
+
+func TestParseRolesClaim(t *testing.T) {
+	assert.Equal(t, []string{"reader", "operator"},
+		parseRolesClaim("reader, operator"))
+	assert.Equal(t, []string{"admin"},
+		parseRolesClaim([]interface{}{"admin", 1}))
+	assert.Nil(t, parseRolesClaim(nil))
+}
+
+func TestGetRolesHasAdmin(t *testing.T) {
+	sc := &types.AuthConfig{
+		Subjects:     map[string][]string{"akutz": {"Admin"}},
+		DefaultRoles: []string{"reader"},
+	}
+	assert.True(t, HasRole(
+		GetRoles(sc, &types.AuthToken{Subject: "akutz"}),
+		types.AuthRoleAdmin))
+	assert.False(t, HasRole(
+		GetRoles(sc, &types.AuthToken{Subject: "cduchesne"}),
+		types.AuthRoleAdmin))
+
+	// a token that may access all routes because it is not assigned any
+	// roles is not an admin
+	assert.False(t, HasRole(
+		GetRoles(&types.AuthConfig{}, &types.AuthToken{Subject: "akutz"}),
+		types.AuthRoleAdmin))
+}
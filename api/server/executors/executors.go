@@ -1,7 +1,13 @@
 package executors
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
 	"regexp"
 
 	// depend upon this tool with a nil import in order to preserve it
@@ -27,15 +33,34 @@ func init() {
 
 		executors[path] = &ExecutorInfoEx{
 			ExecutorInfo: types.ExecutorInfo{
-				Name:         path,
-				MD5Checksum:  bd.info.MD5Checksum(),
-				Size:         bd.info.Size(),
-				LastModified: bd.info.ModTime().Unix(),
+				Name:           path,
+				MD5Checksum:    bd.info.MD5Checksum(),
+				SHA256Checksum: fmt.Sprintf("%x", sha256.Sum256(bd.bytes)),
+				Size:           bd.info.Size(),
+				LastModified:   bd.info.ModTime().Unix(),
 			},
 		}
 	}
 }
 
+// LoadSignatures loads the detached signatures of the executors from the
+// given directory. The signature of an executor is read from a file named
+// after the executor with a ".sig" extension, ex. lsx-linux.sig. Executors
+// without a signature file are served without a signature.
+func LoadSignatures(dir string) error {
+	for _, ei := range executors {
+		buf, err := ioutil.ReadFile(path.Join(dir, ei.Name+".sig"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		ei.Signature = base64.StdEncoding.EncodeToString(buf)
+	}
+	return nil
+}
+
 // ExecutorInfos returns a channel on which all executor information can be
 // received.
 func ExecutorInfos() <-chan *ExecutorInfoEx {
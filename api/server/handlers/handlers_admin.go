@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/server/auth"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+// adminHandler is an HTTP filter that restricts a route to the requests that
+// provide the server's admin token or an auth token to which the server's
+// global auth config assigns the admin role.
+type adminHandler struct {
+	handler types.APIFunc
+}
+
+// NewAdminHandler returns a new adminHandler.
+func NewAdminHandler() types.Middleware {
+	return &adminHandler{}
+}
+
+func (h *adminHandler) Name() string {
+	return "admin-handler"
+}
+
+func (h *adminHandler) Handler(m types.APIFunc) types.APIFunc {
+	return (&adminHandler{m}).Handle
+}
+
+// Handle is the type's Handler function.
+func (h *adminHandler) Handle(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	if store.IsSet("admin") {
+		expectedToken, ok := ctx.Value(context.AdminTokenKey).(string)
+		if !ok {
+			return utils.NewBadAdminTokenError("missing")
+		}
+		actualToken := store.GetString("admin")
+		if expectedToken != actualToken {
+			return utils.NewBadAdminTokenError(actualToken)
+		}
+		ctx.Debug("validated admin token")
+		return h.handler(ctx, w, req, store)
+	}
+
+	// access to a route is not enough since a token that is not assigned
+	// any roles may access all routes
+	if tok, ok := context.AuthToken(ctx); ok {
+		roles, _ := ctx.Value(context.AuthRolesKey).([]string)
+		if !auth.HasRole(roles, types.AuthRoleAdmin) {
+			var name string
+			if route, ok := context.Route(ctx); ok {
+				name = route.GetName()
+			}
+			return utils.NewForbiddenError(tok.Subject, name)
+		}
+		ctx.Debug("validated admin role")
+		return h.handler(ctx, w, req, store)
+	}
+
+	return utils.NewBadAdminTokenError("missing")
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/server/httputils"
+	"github.com/codedellemc/libstorage/api/server/services"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// auditHandler is a global HTTP filter for recording mutating API calls in
+// the audit log.
+type auditHandler struct {
+	handler types.APIFunc
+}
+
+// NewAuditHandler returns a new global HTTP filter for recording mutating API
+// calls in the audit log. The handler must follow the error handler so that
+// the outcome of failed calls may be recorded.
+func NewAuditHandler() types.Middleware {
+	return &auditHandler{}
+}
+
+func (h *auditHandler) Name() string {
+	return "audit-handler"
+}
+
+func (h *auditHandler) Handler(m types.APIFunc) types.APIFunc {
+	return (&auditHandler{m}).Handle
+}
+
+// Handle is the type's Handler function.
+func (h *auditHandler) Handle(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	route, ok := context.Route(ctx)
+	if !ok || !isMutatingMethod(req.Method) {
+		return h.handler(ctx, w, req, store)
+	}
+
+	start := time.Now()
+	rec := &types.AuditRecord{
+		Time:   start.Unix(),
+		Route:  route.GetName(),
+		Method: req.Method,
+	}
+	if tx, ok := context.Transaction(ctx); ok && tx.ID != nil {
+		rec.TransactionID = tx.ID.String()
+	}
+
+	// the handlers that validate auth tokens and instance IDs as well as
+	// those that write task results update the audit record in the context
+	sw := &statusResponseWriter{ResponseWriter: w, status: http.StatusOK}
+	err := h.handler(
+		ctx.WithValue(context.AuditRecordKey, rec), sw, req, store)
+
+	rec.Duration = int64(time.Since(start) / time.Millisecond)
+	if rec.Service == "" {
+		rec.Service = store.GetString("service")
+	}
+	if rec.VolumeID == "" {
+		rec.VolumeID = store.GetString("volumeID")
+	}
+	if rec.SnapshotID == "" {
+		rec.SnapshotID = store.GetString("snapshotID")
+	}
+
+	switch {
+	case err != nil:
+		rec.Outcome = types.AuditFailure
+		rec.Status = getStatus(err)
+		rec.Error = err.Error()
+	case sw.status == http.StatusAccepted:
+		rec.Outcome = types.AuditAccepted
+		rec.Status = sw.status
+	case sw.status >= http.StatusBadRequest:
+		rec.Outcome = types.AuditFailure
+		rec.Status = sw.status
+	default:
+		rec.Outcome = types.AuditSuccess
+		rec.Status = sw.status
+	}
+
+	services.AuditWrite(ctx, rec)
+
+	if rec.Outcome == types.AuditAccepted && rec.TaskID != nil {
+		go auditTaskOutcome(ctx, *rec, start)
+	}
+
+	return err
+}
+
+// auditTaskOutcome waits for the task of an asynchronous call to complete
+// and then records the call's outcome in a follow-up audit record.
+func auditTaskOutcome(
+	ctx types.Context, rec types.AuditRecord, start time.Time) {
+
+	<-services.TaskWaitC(ctx, *rec.TaskID)
+	task := services.TaskInspect(ctx, *rec.TaskID)
+	if task == nil {
+		return
+	}
+
+	rec.Duration = int64(time.Since(start) / time.Millisecond)
+	if task.Error != nil {
+		rec.Outcome = types.AuditFailure
+		rec.Status = getStatus(task.Error)
+		rec.Error = task.Error.Error()
+	} else {
+		rec.Outcome = types.AuditSuccess
+		rec.Status = rec.TaskStatus
+		httputils.AuditTaskResult(&rec, task.Result)
+	}
+
+	services.AuditWrite(ctx, &rec)
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// statusResponseWriter records the status of a response.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Flush sends any buffered data to the client if the wrapped writer is an
+// http.Flusher.
+func (w *statusResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// CloseNotify returns a channel that receives a value when the client's
+// connection closes if the wrapped writer is an http.CloseNotifier. The
+// channel never receives a value otherwise.
+func (w *statusResponseWriter) CloseNotify() <-chan bool {
+	if cn, ok := w.ResponseWriter.(http.CloseNotifier); ok {
+		return cn.CloseNotify()
+	}
+	return make(chan bool)
+}
+
+// setAuditSubject records the subject of a validated auth token in the
+// request's audit record, if any.
+func setAuditSubject(ctx types.Context, tok *types.AuthToken) {
+	if rec, ok := context.AuditRecord(ctx); ok && tok != nil {
+		rec.Subject = tok.Subject
+	}
+}
@@ -8,15 +8,32 @@ import (
 	"github.com/codedellemc/libstorage/api/types"
 )
 
+// probeRoutes are the names of the routes that load balancers and
+// orchestrators request without credentials. They are exempt from the global
+// auth and rate limit handlers.
+var probeRoutes = map[string]bool{
+	"health": true,
+	"ready":  true,
+}
+
+// isProbeRoute returns a flag indicating whether the request's route is one
+// of the probeRoutes.
+func isProbeRoute(ctx types.Context) bool {
+	route, ok := context.Route(ctx)
+	return ok && probeRoutes[route.GetName()]
+}
+
 // authGlobalHandler is an HTTP filter for validating the JWT.
 type authGlobalHandler struct {
 	handler types.APIFunc
-	config  *types.AuthConfig
+	config  func() *types.AuthConfig
 }
 
-// NewAuthGlobalHandler returns a new authGlobalHandler.
+// NewAuthGlobalHandler returns a new authGlobalHandler. The config function
+// is invoked for each request so that the server's auth configuration may
+// be replaced while it is running.
 func NewAuthGlobalHandler(
-	config *types.AuthConfig) types.Middleware {
+	config func() *types.AuthConfig) types.Middleware {
 	return &authGlobalHandler{config: config}
 }
 
@@ -35,17 +52,24 @@ func (h *authGlobalHandler) Handle(
 	req *http.Request,
 	store types.Store) error {
 
-	if h.config == nil {
+	if isProbeRoute(ctx) {
+		ctx.Debug("skipping global auth handler; probe route")
+		return h.handler(ctx, w, req, store)
+	}
+
+	config := h.config()
+
+	if config == nil {
 		ctx.Debug("skipping global auth handler; empty auth config")
 		return h.handler(ctx, w, req, store)
 	}
 
-	if len(h.config.Allow) == 0 && len(h.config.Deny) == 0 {
+	if len(config.Allow) == 0 && len(config.Deny) == 0 {
 		ctx.Debug("skipping global auth handler; empty allow & deny lists")
 		return h.handler(ctx, w, req, store)
 	}
 
-	tok, err := auth.ValidateAuthTokenWithReq(ctx, h.config, req)
+	tok, err := auth.ValidateAuthTokenWithReq(ctx, config, req)
 	if err != nil {
 		return err
 	}
@@ -55,7 +79,9 @@ func (h *authGlobalHandler) Handle(
 	}
 
 	ctx.Debug("validated global security token")
+	setAuditSubject(ctx, tok)
 
-	return h.handler(
-		ctx.WithValue(context.AuthTokenKey, tok), w, req, store)
+	ctx = ctx.WithValue(context.AuthTokenKey, tok)
+	ctx = ctx.WithValue(context.AuthRolesKey, auth.GetRoles(config, tok))
+	return h.handler(ctx, w, req, store)
 }
@@ -58,6 +58,7 @@ func (h *authSvcHandler) Handle(
 	}
 
 	ctx.Debug("validated service security token")
+	setAuditSubject(ctx, tok)
 
 	return h.handler(
 		ctx.WithValue(context.AuthTokenKey, tok), w, req, store)
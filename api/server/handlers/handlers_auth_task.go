@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/server/auth"
+	"github.com/codedellemc/libstorage/api/server/services"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// authTaskHandler is an HTTP filter for validating the JWT against the auth
+// config of the storage service for which the requested task was created.
+type authTaskHandler struct {
+	handler types.APIFunc
+}
+
+// NewAuthTaskHandler returns a new authTaskHandler. The handler must be
+// used with routes that have a taskID path parameter.
+func NewAuthTaskHandler() types.Middleware {
+	return &authTaskHandler{}
+}
+
+func (h *authTaskHandler) Name() string {
+	return "auth-task-handler"
+}
+
+func (h *authTaskHandler) Handler(m types.APIFunc) types.APIFunc {
+	return (&authTaskHandler{m}).Handle
+}
+
+// Handle is the type's Handler function.
+func (h *authTaskHandler) Handle(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	svc := services.TaskStorageService(ctx, store.GetInt("taskID"))
+	if svc == nil {
+		ctx.Debug("skipping task auth handler; task has no service")
+		return h.handler(ctx, w, req, store)
+	}
+
+	if svc.AuthConfig() == nil {
+		ctx.Debug("skipping task auth handler; empty auth config")
+		return h.handler(ctx, w, req, store)
+	}
+
+	if len(svc.AuthConfig().Allow) == 0 && len(svc.AuthConfig().Deny) == 0 {
+		ctx.Debug("skipping task auth handler; empty allow & deny lists")
+		return h.handler(ctx, w, req, store)
+	}
+
+	tok, err := auth.ValidateAuthTokenWithCtxOrReq(ctx, svc.AuthConfig(), req)
+	if err != nil {
+		return err
+	}
+
+	ctx.WithField("service", svc.Name()).Debug(
+		"validated task service security token")
+	setAuditSubject(ctx, tok)
+
+	return h.handler(
+		ctx.WithValue(context.AuthTokenKey, tok), w, req, store)
+}
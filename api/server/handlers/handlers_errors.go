@@ -64,14 +64,34 @@ func getStatus(err error) int {
 	if err == types.ErrMissingStorageService {
 		return http.StatusInternalServerError
 	}
+	if err == types.ErrNotImplemented {
+		return http.StatusNotImplemented
+	}
+	if err == types.ErrTaskCancelled {
+		return http.StatusConflict
+	}
 	switch err.(type) {
 	case *types.ErrBadAdminToken,
 		*types.ErrSecTokInvalid:
 		return http.StatusUnauthorized
 	case *types.ErrNotFound:
 		return http.StatusNotFound
+	case *types.ErrQuotaExceeded,
+		*types.ErrForbidden:
+		return http.StatusForbidden
+	case *types.ErrTaskCompleted,
+		*types.ErrServiceExists,
+		*types.ErrIdempotencyKeyReused:
+		return http.StatusConflict
+	case *types.ErrRateLimited:
+		return http.StatusTooManyRequests
+	case *types.ErrServiceStopped:
+		return http.StatusServiceUnavailable
 	case *types.ErrMissingInstanceID,
-		*types.ErrMissingLocalDevices:
+		*types.ErrMissingLocalDevices,
+		*types.ErrBadPageOpts,
+		*types.ErrBadFilter,
+		*types.ErrQuotaSizeRequired:
 		return http.StatusBadRequest
 	default:
 		return http.StatusInternalServerError
@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/server/httputils"
+	"github.com/codedellemc/libstorage/api/server/services"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+// idempotencyHandler is a global HTTP filter that returns the outcome of a
+// mutating request to the retries of the request instead of executing them.
+type idempotencyHandler struct {
+	handler  types.APIFunc
+	config   gofig.Config
+	requests *idempotentRequests
+}
+
+// NewIdempotencyHandler returns a new global HTTP filter that returns the
+// outcome of a mutating request to the retries of the request instead of
+// executing them. A request is identified by its Idempotency-Key header, or
+// its transaction ID when the header is omitted, and the outcome is kept for
+// the configured window after the request completes. The handler must follow
+// the global auth handler so that the keys of different subjects are kept
+// apart.
+func NewIdempotencyHandler(config gofig.Config) types.Middleware {
+	window, err := time.ParseDuration(
+		config.GetString(types.ConfigServerIdempotencyWindow))
+	if err != nil || window <= 0 {
+		window = time.Duration(10) * time.Minute
+	}
+	return &idempotencyHandler{
+		config: config,
+		requests: &idempotentRequests{
+			window:  window,
+			entries: map[string]*idempotentRequest{},
+		},
+	}
+}
+
+func (h *idempotencyHandler) Name() string {
+	return "idempotency-handler"
+}
+
+func (h *idempotencyHandler) Handler(m types.APIFunc) types.APIFunc {
+	return (&idempotencyHandler{m, h.config, h.requests}).Handle
+}
+
+// Handle is the type's Handler function.
+func (h *idempotencyHandler) Handle(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	// clients retry mutating requests only with servers that advertise
+	// that the retries are safe
+	w.Header().Set(types.IdempotencyHeader, types.IdempotencyTransaction)
+
+	if !isMutatingMethod(req.Method) {
+		return h.handler(ctx, w, req, store)
+	}
+
+	key, explicit := getIdempotencyKey(ctx, req)
+	if key == "" {
+		return h.handler(ctx, w, req, store)
+	}
+
+	fingerprint, err := getRequestFingerprint(req)
+	if err != nil {
+		return err
+	}
+
+	var closed <-chan bool
+	if cn, ok := w.(http.CloseNotifier); ok {
+		closed = cn.CloseNotify()
+	}
+
+	for {
+		r, ok, err := h.requests.begin(key, fingerprint, explicit)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return h.execute(ctx, w, req, store, key, r)
+		}
+
+		ctx.WithField("idempotencyKey", key).Debug(
+			"waiting for outcome of original request")
+
+		select {
+		case <-r.done:
+		case <-closed:
+			return nil
+		}
+
+		// the outcome of a failed request is not kept, so the retry is
+		// executed in its place
+		if r.failed {
+			continue
+		}
+
+		ctx.WithField("idempotencyKey", key).Info(
+			"returning outcome of original request")
+		return h.replay(ctx, w, store, r)
+	}
+}
+
+// execute executes the request and records its outcome.
+func (h *idempotencyHandler) execute(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store,
+	key string,
+	r *idempotentRequest) error {
+
+	// the handler that writes a task's result records the task in the
+	// idempotency record in the context
+	rw := &recordingResponseWriter{
+		statusResponseWriter: &statusResponseWriter{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		},
+	}
+	err := h.handler(
+		ctx.WithValue(context.IdempotencyRecordKey, r.record),
+		rw, req, store)
+
+	h.requests.end(key, r, rw, err)
+	return err
+}
+
+// replay writes the outcome of the original request. The result of the
+// original request's task is written in place of the recorded response so
+// that a retry attaches to a task that is still running.
+func (h *idempotencyHandler) replay(
+	ctx types.Context,
+	w http.ResponseWriter,
+	store types.Store,
+	r *idempotentRequest) error {
+
+	if r.record.TaskID != nil {
+		if task := services.TaskInspect(ctx, *r.record.TaskID); task != nil {
+			return httputils.WriteTask(
+				ctx, h.config, w, store, task, r.record.TaskStatus)
+		}
+	}
+
+	for k, v := range r.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(r.status)
+	w.Write(r.body)
+	return nil
+}
+
+// getIdempotencyKey returns the key that identifies the request and its
+// retries, as well as a flag indicating whether the key was sent explicitly.
+// The key is scoped to the subject of the request's auth token, if any.
+func getIdempotencyKey(
+	ctx types.Context, req *http.Request) (string, bool) {
+
+	var (
+		key      = req.Header.Get(types.IdempotencyKeyHeader)
+		explicit = key != ""
+	)
+
+	// only a transaction sent by the client identifies its retries
+	if !explicit && req.Header.Get(types.TransactionHeader) != "" {
+		if tx, ok := context.Transaction(ctx); ok && tx.ID != nil {
+			key = tx.ID.String()
+		}
+	}
+	if key == "" {
+		return "", false
+	}
+
+	if tok, ok := context.AuthToken(ctx); ok && tok.Subject != "" {
+		key = fmt.Sprintf("%s|%s", tok.Subject, key)
+	}
+	return key, explicit
+}
+
+// getRequestFingerprint returns a hash of the request's method, URI, and body.
+// The request's body is replaced so that it may be read again.
+func getRequestFingerprint(req *http.Request) (string, error) {
+	var body []byte
+	if req.Body != nil {
+		buf, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return "", err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(buf))
+		body = buf
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n", req.Method, req.URL.RequestURI())
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// idempotentRequests are the mutating requests whose outcomes are kept.
+type idempotentRequests struct {
+	sync.Mutex
+	window    time.Duration
+	entries   map[string]*idempotentRequest
+	lastSweep time.Time
+}
+
+// idempotentRequest is a mutating request and, once it completes, its
+// outcome.
+type idempotentRequest struct {
+	fingerprint string
+	record      *types.IdempotencyRecord
+	done        chan struct{}
+	failed      bool
+	expires     time.Time
+	status      int
+	header      http.Header
+	body        []byte
+}
+
+// begin returns the request with the given key. A true value is returned if
+// the request is new and should be executed by the caller.
+//
+// A key sent explicitly may not be reused with a different request until its
+// window expires. A key derived from a transaction, which the client may share
+// among many requests, identifies only the most recent of them, so a
+// different request replaces the one recorded for the key.
+func (rs *idempotentRequests) begin(
+	key, fingerprint string,
+	explicit bool) (*idempotentRequest, bool, error) {
+
+	rs.Lock()
+	defer rs.Unlock()
+
+	now := time.Now()
+	rs.sweep(now)
+
+	if r, ok := rs.entries[key]; ok &&
+		(r.expires.IsZero() || now.Before(r.expires)) {
+		if r.fingerprint == fingerprint {
+			return r, false, nil
+		}
+		if explicit {
+			return nil, false, utils.NewIdempotencyKeyReusedError(key)
+		}
+	}
+
+	r := &idempotentRequest{
+		fingerprint: fingerprint,
+		record:      &types.IdempotencyRecord{Key: key},
+		done:        make(chan struct{}),
+	}
+	rs.entries[key] = r
+	return r, true, nil
+}
+
+// end records the outcome of the request and releases its retries. The
+// outcome of a failed request is discarded.
+func (rs *idempotentRequests) end(
+	key string,
+	r *idempotentRequest,
+	rw *recordingResponseWriter,
+	err error) {
+
+	rs.Lock()
+	defer rs.Unlock()
+
+	if err != nil {
+		r.failed = true
+		if rs.entries[key] == r {
+			delete(rs.entries, key)
+		}
+	} else {
+		r.status = rw.status
+		r.header = rw.header
+		r.body = rw.body.Bytes()
+		r.expires = time.Now().Add(rs.window)
+	}
+	close(r.done)
+}
+
+// sweep removes the requests whose window has expired at most once a minute.
+func (rs *idempotentRequests) sweep(now time.Time) {
+	if now.Sub(rs.lastSweep) < time.Minute {
+		return
+	}
+	rs.lastSweep = now
+	for key, r := range rs.entries {
+		if !r.expires.IsZero() && !now.Before(r.expires) {
+			delete(rs.entries, key)
+		}
+	}
+}
+
+// recordingResponseWriter records the status, headers, and body of a
+// response.
+type recordingResponseWriter struct {
+	*statusResponseWriter
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (w *recordingResponseWriter) WriteHeader(status int) {
+	if w.header == nil {
+		w.header = http.Header{}
+		for k, v := range w.Header() {
+			w.header[k] = append([]string{}, v...)
+		}
+	}
+	w.statusResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingResponseWriter) Write(b []byte) (int, error) {
+	if w.header == nil {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.body.Write(b)
+	return w.statusResponseWriter.Write(b)
+}
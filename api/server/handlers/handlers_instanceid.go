@@ -12,19 +12,15 @@ import (
 // from the headers
 type instanceIDHandler struct {
 	handler types.APIFunc
-	s2d     map[string]string
+	svcs    func() <-chan types.StorageService
 }
 
 // NewInstanceIDHandler returns a new global HTTP filter for grokking the
-// InstanceIDs from the headers
-func NewInstanceIDHandler(svcs <-chan types.StorageService) types.Middleware {
-	iidh := &instanceIDHandler{
-		s2d: map[string]string{},
-	}
-	for s := range svcs {
-		iidh.s2d[strings.ToLower(s.Name())] = strings.ToLower(s.Driver().Name())
-	}
-	return iidh
+// InstanceIDs from the headers. The svcs function is invoked for each request
+// so that services added or removed while the server is running are honored.
+func NewInstanceIDHandler(
+	svcs func() <-chan types.StorageService) types.Middleware {
+	return &instanceIDHandler{svcs: svcs}
 }
 
 func (h *instanceIDHandler) Name() string {
@@ -32,7 +28,7 @@ func (h *instanceIDHandler) Name() string {
 }
 
 func (h *instanceIDHandler) Handler(m types.APIFunc) types.APIFunc {
-	return (&instanceIDHandler{m, h.s2d}).Handle
+	return (&instanceIDHandler{m, h.svcs}).Handle
 }
 
 // Handle is the type's Handler function.
@@ -76,7 +72,12 @@ func (h *instanceIDHandler) Handle(
 		}
 	}
 
-	for s, d := range h.s2d {
+	s2d := map[string]string{}
+	for s := range h.svcs() {
+		s2d[strings.ToLower(s.Name())] = strings.ToLower(s.Driver().Name())
+	}
+
+	for s, d := range s2d {
 		if iid, ok := s2i[s]; ok {
 			valMap[s] = iid
 		} else if iid, ok := d2i[d]; ok {
@@ -84,6 +85,13 @@ func (h *instanceIDHandler) Handle(
 		}
 	}
 
+	if rec, ok := context.AuditRecord(ctx); ok {
+		service := strings.ToLower(store.GetString("service"))
+		if iid, ok := valMap[service]; ok {
+			rec.InstanceID = iid.ID
+		}
+	}
+
 	ctx = ctx.WithValue(context.AllInstanceIDsKey, valMap)
 	return h.handler(ctx, w, req, store)
 }
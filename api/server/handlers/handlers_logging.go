@@ -59,6 +59,12 @@ func (h *loggingHandler) Handle(
 	req *http.Request,
 	store types.Store) error {
 
+	// event streams are not logged since recording the response would
+	// prevent it from being streamed to the client
+	if strings.Contains(req.Header.Get("Accept"), types.EventStreamContentType) {
+		return h.handler(ctx, w, req, store)
+	}
+
 	bw := &bytes.Buffer{}
 	defer func(w io.Writer) {
 		h.writer.Write(bw.Bytes())
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils/metrics"
+)
+
+var (
+	httpRequests = metrics.NewCounterVec(
+		"libstorage_http_requests_total",
+		"The number of HTTP requests handled by the server.",
+		"route", "service", "code")
+
+	httpRequestDuration = metrics.NewHistogramVec(
+		"libstorage_http_request_duration_seconds",
+		"The latency of the HTTP requests handled by the server.",
+		nil, "route", "service")
+)
+
+// metricsHandler is a global HTTP filter for recording the count and
+// latency of requests.
+type metricsHandler struct {
+	handler types.APIFunc
+}
+
+// NewMetricsHandler returns a new global HTTP filter for recording the count
+// and latency of requests. The handler must follow the error handler so that
+// the status of failed requests may be recorded.
+func NewMetricsHandler() types.Middleware {
+	return &metricsHandler{}
+}
+
+func (h *metricsHandler) Name() string {
+	return "metrics-handler"
+}
+
+func (h *metricsHandler) Handler(m types.APIFunc) types.APIFunc {
+	return (&metricsHandler{m}).Handle
+}
+
+// Handle is the type's Handler function.
+func (h *metricsHandler) Handle(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	route, ok := context.Route(ctx)
+	if !ok {
+		return h.handler(ctx, w, req, store)
+	}
+
+	start := time.Now()
+	sw := &statusResponseWriter{ResponseWriter: w, status: http.StatusOK}
+	err := h.handler(ctx, sw, req, store)
+
+	status := sw.status
+	if err != nil {
+		status = getStatus(err)
+	}
+
+	var (
+		routeName = route.GetName()
+		service   = store.GetString("service")
+	)
+	httpRequests.Inc(routeName, service, strconv.Itoa(status))
+	httpRequestDuration.ObserveSince(start, routeName, service)
+
+	return err
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/server/services"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// quotaHandler is an HTTP filter for enforcing the quotas of the auth
+// subjects that create and resize volumes.
+type quotaHandler struct {
+	handler types.APIFunc
+}
+
+// NewQuotaHandler returns a new quotaHandler. The handler must follow the
+// handlers that validate the service and auth token, open the storage
+// session, and parse the request's arguments.
+func NewQuotaHandler() types.Middleware {
+	return &quotaHandler{}
+}
+
+func (h *quotaHandler) Name() string {
+	return "quota-handler"
+}
+
+func (h *quotaHandler) Handler(m types.APIFunc) types.APIFunc {
+	return (&quotaHandler{m}).Handle
+}
+
+// Handle is the type's Handler function.
+func (h *quotaHandler) Handle(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	svc, ok := context.Service(ctx)
+	if !ok {
+		return types.ErrMissingStorageService
+	}
+
+	var subject string
+	if tok, ok := context.AuthToken(ctx); ok {
+		subject = tok.Subject
+	}
+
+	var (
+		res  types.QuotaReservation
+		size int64
+		err  error
+	)
+	if route, ok := context.Route(ctx); ok &&
+		route.GetName() == "volumeResize" {
+		res, size, err = reserveResize(ctx, svc, subject, store)
+	} else {
+		size, err = requestedVolumeSize(ctx, svc, store)
+		if err == nil {
+			res, err = services.QuotaReserve(ctx, svc.Name(), subject, size)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx.WithFields(map[string]interface{}{
+		"subject": subject,
+		"size":    size,
+	}).Debug("reserved volume quota")
+
+	// the reservation is completed by the route's task, but if the request
+	// fails before the task is run then the reservation must be released
+	if err := h.handler(
+		ctx.WithValue(context.QuotaReservationKey, res),
+		w, req, store); err != nil {
+		res.Cancel()
+		return err
+	}
+
+	return nil
+}
+
+// reserveResize reserves the space by which a request grows a volume and
+// returns the reservation and the volume's new size.
+func reserveResize(
+	ctx types.Context,
+	svc types.StorageService,
+	subject string,
+	store types.Store) (types.QuotaReservation, int64, error) {
+
+	volumeID := store.GetString("volumeID")
+	vol, err := svc.Driver().VolumeInspect(
+		ctx, volumeID, &types.VolumeInspectOpts{Opts: store})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	newSize := store.GetInt64("size")
+	res, err := services.QuotaReserveResize(
+		ctx, svc.Name(), subject, volumeID, vol.Size, newSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	return res, newSize, nil
+}
+
+// requestedVolumeSize returns the size of the volume a request will create.
+// The size is the one in the request or, if none is specified, the size of
+// the snapshot or volume from which the new volume is created. Otherwise the
+// size is zero since it is chosen by the driver.
+func requestedVolumeSize(
+	ctx types.Context,
+	svc types.StorageService,
+	store types.Store) (int64, error) {
+
+	if size := store.GetInt64Ptr("size"); size != nil {
+		return *size, nil
+	}
+
+	if store.IsSet("snapshotID") {
+		snap, err := svc.Driver().SnapshotInspect(
+			ctx, store.GetString("snapshotID"), store)
+		if err != nil {
+			return 0, err
+		}
+		return snap.VolumeSize, nil
+	}
+
+	if store.IsSet("volumeID") {
+		vol, err := svc.Driver().VolumeInspect(
+			ctx, store.GetString("volumeID"),
+			&types.VolumeInspectOpts{Opts: store})
+		if err != nil {
+			return 0, err
+		}
+		return vol.Size, nil
+	}
+
+	return 0, nil
+}
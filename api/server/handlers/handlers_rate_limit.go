@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/server/services"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+	"github.com/codedellemc/libstorage/api/utils/ratelimit"
+)
+
+const (
+	rateLimitKeyInstanceID = "instanceID"
+	rateLimitKeyRemoteAddr = "remoteAddr"
+)
+
+// rateLimitHandler is a global HTTP filter that limits the rate at which
+// clients may make requests.
+type rateLimitHandler struct {
+	handler types.APIFunc
+	limiter *ratelimit.Limiter
+	keyType string
+	global  ratelimit.Limit
+	routes  map[string]ratelimit.Limit
+}
+
+// NewRateLimitHandler returns a new global HTTP filter that limits the rate
+// at which clients may make requests. Clients are identified by their auth
+// subject, instance ID, or remote address, and the limits are configured
+// globally, per route, and per service.
+func NewRateLimitHandler(config gofig.Config) types.Middleware {
+	h := &rateLimitHandler{
+		limiter: ratelimit.New(),
+		keyType: config.GetString(types.ConfigServerRateLimitKey),
+		global:  getRateLimit(config, types.ConfigServerRateLimit),
+		routes:  map[string]ratelimit.Limit{},
+	}
+	if m, ok := config.Get(
+		types.ConfigServerRateLimitRoutes).(map[string]interface{}); ok {
+		for name := range m {
+			h.routes[strings.ToLower(name)] = getRateLimit(
+				config,
+				fmt.Sprintf("%s.%s", types.ConfigServerRateLimitRoutes, name))
+		}
+	}
+	return h
+}
+
+// getRateLimit returns the limit configured by the rate and burst properties
+// of the given key. The burst defaults to the rate rounded up.
+func getRateLimit(config gofig.Config, key string) ratelimit.Limit {
+	rate, _ := strconv.ParseFloat(config.GetString(key+".rate"), 64)
+	burst := config.GetInt(key + ".burst")
+	if rate > 0 && burst <= 0 {
+		burst = int(math.Ceil(rate))
+	}
+	return ratelimit.Limit{Rate: rate, Burst: burst}
+}
+
+func (h *rateLimitHandler) Name() string {
+	return "rate-limit-handler"
+}
+
+func (h *rateLimitHandler) Handler(m types.APIFunc) types.APIFunc {
+	return (&rateLimitHandler{
+		m, h.limiter, h.keyType, h.global, h.routes}).Handle
+}
+
+// Handle is the type's Handler function.
+func (h *rateLimitHandler) Handle(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	if isProbeRoute(ctx) {
+		return h.handler(ctx, w, req, store)
+	}
+
+	key := h.clientKey(ctx, req)
+
+	var reqs []ratelimit.Request
+
+	// a route's limit takes the place of the global limit
+	if route, ok := context.Route(ctx); ok {
+		routeName := strings.ToLower(route.GetName())
+		if limit, ok := h.routes[routeName]; ok {
+			reqs = append(reqs, ratelimit.Request{
+				Key:   fmt.Sprintf("%s|route|%s", key, routeName),
+				Limit: limit,
+			})
+		}
+	}
+	if len(reqs) == 0 {
+		reqs = append(reqs, ratelimit.Request{
+			Key:   fmt.Sprintf("%s|global", key),
+			Limit: h.global,
+		})
+	}
+
+	// a service's limit applies in addition to the route or global limit
+	if store.IsSet("service") {
+		svc := services.GetStorageService(ctx, store.GetString("service"))
+		if svc != nil {
+			reqs = append(reqs, ratelimit.Request{
+				Key:   fmt.Sprintf("%s|service|%s", key, svc.Name()),
+				Limit: getRateLimit(svc.Config(), "rateLimit"),
+			})
+		}
+	}
+
+	if wait := h.limiter.Take(reqs...); wait > 0 {
+		retryAfter := int(math.Ceil(wait.Seconds()))
+		w.Header().Set(types.RetryAfterHeader, strconv.Itoa(retryAfter))
+		ctx.WithField("retryAfter", retryAfter).Warn("rate limit exceeded")
+		return utils.NewRateLimitedError(key, retryAfter)
+	}
+
+	return h.handler(ctx, w, req, store)
+}
+
+// clientKey returns the key that identifies the client making the request.
+// The configured key falls back to the client's instance IDs and then its
+// remote address when the request does not include it.
+func (h *rateLimitHandler) clientKey(
+	ctx types.Context, req *http.Request) string {
+
+	if strings.EqualFold(h.keyType, rateLimitKeyRemoteAddr) {
+		return getRemoteAddrKey(req)
+	}
+
+	if !strings.EqualFold(h.keyType, rateLimitKeyInstanceID) {
+		if tok, ok := context.AuthToken(ctx); ok && tok.Subject != "" {
+			return fmt.Sprintf("subject=%s", tok.Subject)
+		}
+	}
+
+	if iids := req.Header[types.InstanceIDHeader]; len(iids) > 0 {
+		iids = append([]string{}, iids...)
+		sort.Strings(iids)
+		return fmt.Sprintf("instanceID=%s", strings.Join(iids, ","))
+	}
+
+	return getRemoteAddrKey(req)
+}
+
+func getRemoteAddrKey(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	return fmt.Sprintf("remoteAddr=%s", host)
+}
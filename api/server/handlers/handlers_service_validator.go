@@ -47,5 +47,8 @@ func (h *serviceValidator) Handle(
 	}
 
 	ctx = context.WithStorageService(ctx, service)
+	if store.IsSet("volumeID") {
+		ctx = ctx.WithValue(context.VolumeIDKey, store.GetString("volumeID"))
+	}
 	return h.handler(ctx, w, req, store)
 }
@@ -8,6 +8,7 @@ import (
 
 	gofig "github.com/akutz/gofig/types"
 
+	"github.com/codedellemc/libstorage/api/context"
 	"github.com/codedellemc/libstorage/api/server/services"
 	"github.com/codedellemc/libstorage/api/types"
 )
@@ -56,6 +57,17 @@ func WriteTask(
 	task *types.Task,
 	okStatus int) error {
 
+	if rec, ok := context.AuditRecord(ctx); ok {
+		taskID := task.ID
+		rec.TaskID = &taskID
+		rec.TaskStatus = okStatus
+	}
+	if rec, ok := context.IdempotencyRecord(ctx); ok {
+		taskID := task.ID
+		rec.TaskID = &taskID
+		rec.TaskStatus = okStatus
+	}
+
 	if store.GetBool("async") {
 		WriteJSON(w, http.StatusAccepted, task)
 		return nil
@@ -73,6 +85,10 @@ func WriteTask(
 		if task.Error != nil {
 			return task.Error
 		}
+		auditTaskResult(ctx, task.Result)
+		if p, ok := task.Result.(types.Page); ok && p.NextMarker() != "" {
+			w.Header().Set(types.NextMarkerHeader, p.NextMarker())
+		}
 		WriteJSON(w, okStatus, task.Result)
 	case <-exeTimeout.C:
 		WriteJSON(w, http.StatusRequestTimeout, task)
@@ -80,3 +96,23 @@ func WriteTask(
 
 	return nil
 }
+
+// auditTaskResult records the IDs of the volume or snapshot returned by a
+// task in the request's audit record, if any.
+func auditTaskResult(ctx types.Context, result interface{}) {
+	if rec, ok := context.AuditRecord(ctx); ok {
+		AuditTaskResult(rec, result)
+	}
+}
+
+// AuditTaskResult records the IDs of the volume or snapshot returned by a
+// task in the audit record.
+func AuditTaskResult(rec *types.AuditRecord, result interface{}) {
+	switch tr := result.(type) {
+	case *types.Volume:
+		rec.VolumeID = tr.ID
+	case *types.Snapshot:
+		rec.SnapshotID = tr.ID
+		rec.VolumeID = tr.VolumeID
+	}
+}
@@ -0,0 +1,27 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func (r *router) reload(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	reload, ok := ctx.Value(context.ServerReloadKey).(func() error)
+	if !ok {
+		return types.ErrNotImplemented
+	}
+
+	if err := reload(); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
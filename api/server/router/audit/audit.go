@@ -0,0 +1,46 @@
+package audit
+
+import (
+	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/server/handlers"
+	"github.com/codedellemc/libstorage/api/server/httputils"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils/schema"
+)
+
+func init() {
+	registry.RegisterRouter(&router{})
+}
+
+type router struct {
+	routes []types.Route
+}
+
+func (r *router) Name() string {
+	return "audit-router"
+}
+
+func (r *router) Init(config gofig.Config) {
+	r.initRoutes()
+}
+
+// Routes returns the available routes.
+func (r *router) Routes() []types.Route {
+	return r.routes
+}
+
+func (r *router) initRoutes() {
+
+	r.routes = []types.Route{
+
+		// GET
+		httputils.NewGetRoute(
+			"audit",
+			"/audit",
+			r.audit,
+			handlers.NewAuthAllSvcsHandler(),
+			handlers.NewSchemaValidator(nil, schema.AuditRecordsSchema, nil)),
+	}
+}
@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/codedellemc/libstorage/api/server/httputils"
+	"github.com/codedellemc/libstorage/api/server/services"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+func (r *router) audit(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	q := &types.AuditQuery{
+		Route:      store.GetString("route"),
+		Service:    store.GetString("service"),
+		Subject:    store.GetString("subject"),
+		VolumeID:   store.GetString("volumeID"),
+		SnapshotID: store.GetString("snapshotID"),
+		Outcome:    types.AuditOutcome(store.GetString("outcome")),
+		Limit:      store.GetInt("limit"),
+	}
+
+	if store.IsSet("since") {
+		since, err := parseSince(store.GetString("since"))
+		if err != nil {
+			return err
+		}
+		q.Since = since
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, services.AuditQuery(ctx, q))
+	return nil
+}
+
+// parseSince parses the value of the since query parameter, either an epoch
+// in seconds or a duration relative to the current time, such as 1h.
+func parseSince(s string) (int64, error) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, utils.NewBadFilterErr("since="+s, err)
+	}
+	return time.Now().Add(-d).Unix(), nil
+}
@@ -0,0 +1,44 @@
+package events
+
+import (
+	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/server/handlers"
+	"github.com/codedellemc/libstorage/api/server/httputils"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func init() {
+	registry.RegisterRouter(&router{})
+}
+
+type router struct {
+	routes []types.Route
+}
+
+func (r *router) Name() string {
+	return "events-router"
+}
+
+func (r *router) Init(config gofig.Config) {
+	r.initRoutes()
+}
+
+// Routes returns the available routes.
+func (r *router) Routes() []types.Route {
+	return r.routes
+}
+
+func (r *router) initRoutes() {
+
+	r.routes = []types.Route{
+
+		// GET
+		httputils.NewGetRoute(
+			"events",
+			"/events",
+			r.events,
+			handlers.NewAuthAllSvcsHandler()),
+	}
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/server/services"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// keepAliveInterval is the interval at which a comment is written to an
+// idle event stream so that proxies do not close the connection.
+const keepAliveInterval = 30 * time.Second
+
+func (r *router) events(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return goof.New("streaming unsupported")
+	}
+
+	filter := &types.EventFilter{
+		Service:    store.GetString("service"),
+		VolumeID:   store.GetString("volumeID"),
+		SnapshotID: store.GetString("snapshotID"),
+	}
+	for _, v := range req.URL.Query()["type"] {
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				filter.Types = append(filter.Types, types.EventType(t))
+			}
+		}
+	}
+
+	events, cancel := services.EventSubscribe(ctx, filter)
+	defer cancel()
+
+	var closed <-chan bool
+	if cn, ok := w.(http.CloseNotifier); ok {
+		closed = cn.CloseNotify()
+	}
+
+	w.Header().Set("Content-Type", types.EventStreamContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx.Debug("streaming events")
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := writeEvent(w, ev); err != nil {
+				ctx.WithError(err).Debug("error writing event")
+				return nil
+			}
+			flusher.Flush()
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+			flusher.Flush()
+		case <-closed:
+			ctx.Debug("event stream closed by client")
+			return nil
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, ev *types.Event) error {
+	buf, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(
+		w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, buf)
+	return err
+}
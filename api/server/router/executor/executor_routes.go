@@ -70,6 +70,11 @@ func writeFile(w http.ResponseWriter, ei *executors.ExecutorInfoEx) error {
 	b64str := base64.StdEncoding.EncodeToString(hexBuf)
 	w.Header().Add("Content-MD5", b64str)
 
+	if hexBuf, err := hex.DecodeString(ei.SHA256Checksum); err == nil {
+		w.Header().Add(types.DigestHeader, fmt.Sprintf("SHA-256=%s",
+			base64.StdEncoding.EncodeToString(hexBuf)))
+	}
+
 	if len(ei.Data) > 0 {
 		if _, err := io.Copy(w, bytes.NewReader(ei.Data)); err != nil {
 			return err
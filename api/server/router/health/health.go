@@ -0,0 +1,45 @@
+package health
+
+import (
+	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/server/httputils"
+	"github.com/codedellemc/libstorage/api/server/services"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func init() {
+	registry.RegisterRouter(&router{})
+}
+
+type router struct {
+	routes []types.Route
+
+	// readiness probes the storage services
+	readiness func(ctx types.Context) *types.Readiness
+}
+
+func (r *router) Name() string {
+	return "health-router"
+}
+
+func (r *router) Init(config gofig.Config) {
+	r.readiness = services.Ready
+	r.initRoutes()
+}
+
+// Routes returns the available routes.
+func (r *router) Routes() []types.Route {
+	return r.routes
+}
+
+func (r *router) initRoutes() {
+
+	r.routes = []types.Route{
+
+		// GET
+		httputils.NewGetRoute("health", "/health", r.health),
+		httputils.NewGetRoute("ready", "/ready", r.ready),
+	}
+}
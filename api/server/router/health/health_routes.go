@@ -0,0 +1,34 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/codedellemc/libstorage/api/server/httputils"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func (r *router) health(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	return httputils.WriteJSON(
+		w, http.StatusOK, &types.Health{Status: types.HealthStatusUp})
+}
+
+func (r *router) ready(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	reply := r.readiness(ctx)
+
+	status := http.StatusOK
+	if reply.Status == types.HealthStatusDown {
+		status = http.StatusServiceUnavailable
+	}
+
+	return httputils.WriteJSON(w, status, reply)
+}
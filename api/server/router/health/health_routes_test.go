@@ -0,0 +1,77 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+func getTestReady(
+	t *testing.T, res *types.Readiness) (int, *types.Readiness) {
+
+	r := &router{readiness: func(ctx types.Context) *types.Readiness {
+		return res
+	}}
+
+	req, err := http.NewRequest(http.MethodGet, "/ready", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	err = r.ready(context.Background(), w, req, utils.NewStore())
+	assert.NoError(t, err)
+
+	reply := &types.Readiness{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), reply))
+	return w.Code, reply
+}
+
+func TestReadyCriticalServiceDown(t *testing.T) {
+	code, reply := getTestReady(t, &types.Readiness{
+		Status: types.HealthStatusDown,
+		Services: map[string]*types.ServiceHealth{
+			"ebs-00": {Status: types.HealthStatusDown, Critical: true},
+			"vfs":    {Status: types.HealthStatusUp},
+		},
+	})
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, types.HealthStatusDown, reply.Status)
+	assert.Len(t, reply.Services, 2)
+}
+
+func TestReadyTimeout(t *testing.T) {
+	code, reply := getTestReady(t, &types.Readiness{
+		Status: types.HealthStatusDown,
+		Services: map[string]*types.ServiceHealth{
+			"ebs-00": {
+				Status:    types.HealthStatusDown,
+				Critical:  true,
+				Latency:   10000,
+				LastError: types.ErrTimedOut.Error(),
+			},
+		},
+	})
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	if assert.Contains(t, reply.Services, "ebs-00") {
+		assert.Equal(t,
+			types.ErrTimedOut.Error(), reply.Services["ebs-00"].LastError)
+	}
+}
+
+func TestReadyServiceDown(t *testing.T) {
+	code, reply := getTestReady(t, &types.Readiness{
+		Status: types.HealthStatusUp,
+		Services: map[string]*types.ServiceHealth{
+			"vfs": {Status: types.HealthStatusDown},
+		},
+	})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, types.HealthStatusUp, reply.Status)
+}
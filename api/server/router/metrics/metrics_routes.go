@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils/metrics"
+)
+
+func (r *router) metrics(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	w.Header().Set("Content-Type", metrics.ContentType)
+	w.WriteHeader(http.StatusOK)
+	return metrics.WriteText(w)
+}
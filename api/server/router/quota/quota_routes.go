@@ -0,0 +1,28 @@
+package quota
+
+import (
+	"net/http"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/server/httputils"
+	"github.com/codedellemc/libstorage/api/server/services"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func (r *router) quotas(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	// an authenticated subject may only view its own quotas
+	var reply types.ServiceQuotaMap
+	if tok, ok := context.AuthToken(ctx); ok {
+		reply = services.Quotas(ctx, tok.Subject)
+	} else {
+		reply = services.Quotas(ctx)
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, reply)
+	return nil
+}
@@ -15,6 +15,7 @@ func init() {
 }
 
 type router struct {
+	config gofig.Config
 	routes []types.Route
 }
 
@@ -23,6 +24,7 @@ func (r *router) Name() string {
 }
 
 func (r *router) Init(config gofig.Config) {
+	r.config = config
 	r.initRoutes()
 }
 
@@ -50,5 +52,39 @@ func (r *router) initRoutes() {
 			handlers.NewServiceValidator(),
 			handlers.NewAuthSvcHandler(),
 			handlers.NewSchemaValidator(nil, schema.ServiceInfoSchema, nil)),
+
+		// POST
+		httputils.NewPostRoute(
+			"serviceCreate",
+			"/services",
+			r.serviceCreate,
+			handlers.NewAdminHandler(),
+			handlers.NewSchemaValidator(
+				schema.ServiceCreateRequestSchema,
+				schema.ServiceInfoSchema,
+				func() interface{} { return &types.ServiceCreateRequest{} }),
+			handlers.NewPostArgsHandler(r.config)),
+
+		// PATCH
+		httputils.NewRoute(
+			"serviceUpdate",
+			"PATCH",
+			"/services/{service}",
+			r.serviceUpdate,
+			handlers.NewAdminHandler(),
+			handlers.NewServiceValidator(),
+			handlers.NewSchemaValidator(
+				schema.ServiceUpdateRequestSchema,
+				schema.ServiceInfoSchema,
+				func() interface{} { return &types.ServiceUpdateRequest{} }),
+			handlers.NewPostArgsHandler(r.config)),
+
+		// DELETE
+		httputils.NewDeleteRoute(
+			"serviceRemove",
+			"/services/{service}",
+			r.serviceRemove,
+			handlers.NewAdminHandler(),
+			handlers.NewServiceValidator()),
 	}
 }
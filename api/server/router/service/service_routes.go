@@ -45,6 +45,78 @@ func (r *router) serviceInspect(
 	return nil
 }
 
+func (r *router) serviceCreate(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	service, err := services.AddStorageService(
+		ctx, store.GetString("name"), getServiceSettings(store))
+	if err != nil {
+		return err
+	}
+
+	ctx = context.WithStorageService(ctx, service)
+	si, err := toServiceInfo(ctx, service, store)
+	if err != nil {
+		return err
+	}
+	httputils.WriteJSON(w, http.StatusCreated, si)
+	return nil
+}
+
+func (r *router) serviceUpdate(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	service, err := services.UpdateStorageService(
+		ctx, context.MustService(ctx).Name(), getServiceSettings(store))
+	if err != nil {
+		return err
+	}
+
+	ctx = context.WithStorageService(ctx, service)
+	si, err := toServiceInfo(ctx, service, store)
+	if err != nil {
+		return err
+	}
+	httputils.WriteJSON(w, http.StatusOK, si)
+	return nil
+}
+
+func (r *router) serviceRemove(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	service := context.MustService(ctx)
+
+	run := func(ctx types.Context) (interface{}, error) {
+		return nil, services.RemoveStorageService(ctx, service.Name())
+	}
+
+	return httputils.WriteTask(
+		ctx,
+		r.config,
+		w,
+		store,
+		services.TaskEnqueue(ctx, run, nil),
+		http.StatusResetContent)
+}
+
+// getServiceSettings returns the service configuration from the body of a
+// create or update request.
+func getServiceSettings(store types.Store) map[string]interface{} {
+	if cfg, ok := store.Get("config").(types.Store); ok {
+		return cfg.Map()
+	}
+	return map[string]interface{}{}
+}
+
 func toServiceInfo(
 	ctx types.Context,
 	service types.StorageService,
@@ -86,6 +86,7 @@ func (r *router) initRoutes() {
 				schema.VolumeSchema,
 				func() interface{} { return &types.VolumeCreateRequest{} }),
 			handlers.NewPostArgsHandler(r.config),
+			handlers.NewQuotaHandler(),
 		).Queries("create"),
 
 		// copy snapshot
@@ -5,11 +5,14 @@ import (
 
 	"github.com/akutz/goof"
 	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/registry"
 	"github.com/codedellemc/libstorage/api/server/httputils"
 	"github.com/codedellemc/libstorage/api/server/router/volume"
 	"github.com/codedellemc/libstorage/api/server/services"
 	"github.com/codedellemc/libstorage/api/types"
 	"github.com/codedellemc/libstorage/api/utils"
+	"github.com/codedellemc/libstorage/api/utils/filters"
+	"github.com/codedellemc/libstorage/api/utils/paging"
 	"github.com/codedellemc/libstorage/api/utils/schema"
 )
 
@@ -19,6 +22,19 @@ func (r *router) snapshots(
 	req *http.Request,
 	store types.Store) error {
 
+	filter, err := volume.ParseFilter(store)
+	if err != nil {
+		return err
+	}
+	if filter != nil {
+		store.Set("filter", filter)
+	}
+
+	page, err := paging.ParsePageOpts(store)
+	if err != nil {
+		return err
+	}
+
 	var (
 		tasks   = map[string]*types.Task{}
 		taskIDs []int
@@ -44,6 +60,9 @@ func (r *router) snapshots(
 
 			objMap := map[string]*types.Snapshot{}
 			for _, obj := range objs {
+				if filter != nil && !filters.MatchSnapshot(filter, obj) {
+					continue
+				}
 				objMap[obj.ID] = obj
 			}
 			return objMap, nil
@@ -71,15 +90,24 @@ func (r *router) snapshots(
 			reply[k] = objMap
 		}
 
+		if page != nil {
+			return paging.ServiceSnapshotMap(reply, page)
+		}
+
 		return reply, nil
 	}
 
+	replySchema := schema.ServiceSnapshotMapSchema
+	if page != nil {
+		replySchema = schema.ServiceSnapshotListSchema
+	}
+
 	return httputils.WriteTask(
 		ctx,
 		r.config,
 		w,
 		store,
-		services.TaskEnqueue(ctx, run, schema.ServiceSnapshotMapSchema),
+		services.TaskEnqueue(ctx, run, replySchema),
 		http.StatusOK)
 }
 
@@ -89,34 +117,116 @@ func (r *router) snapshotsForService(
 	req *http.Request,
 	store types.Store) error {
 
+	filter, err := volume.ParseFilter(store)
+	if err != nil {
+		return err
+	}
+	if filter != nil {
+		store.Set("filter", filter)
+	}
+
+	page, err := paging.ParsePageOpts(store)
+	if err != nil {
+		return err
+	}
+
 	service := context.MustService(ctx)
 
 	run := func(
 		ctx types.Context,
 		svc types.StorageService) (interface{}, error) {
 
-		var reply types.SnapshotMap = map[string]*types.Snapshot{}
+		d, native := registry.UnwrapStorageDriver(
+			svc.Driver()).(types.StorageDriverWithSnapshotsPage)
+		if page != nil && native {
+			return getSnapshotsPage(ctx, store, d, filter, page)
+		}
 
 		objs, err := svc.Driver().Snapshots(ctx, store)
 		if err != nil {
 			return nil, err
 		}
 
+		var reply types.SnapshotMap = map[string]*types.Snapshot{}
 		for _, obj := range objs {
+			if filter != nil && !filters.MatchSnapshot(filter, obj) {
+				continue
+			}
 			reply[obj.ID] = obj
 		}
+
+		if page != nil {
+			return paging.SnapshotMap(reply, page)
+		}
 		return reply, nil
 	}
 
+	replySchema := schema.SnapshotMapSchema
+	if page != nil {
+		replySchema = schema.SnapshotListSchema
+	}
+
 	return httputils.WriteTask(
 		ctx,
 		r.config,
 		w,
 		store,
-		service.TaskEnqueue(ctx, run, schema.SnapshotMapSchema),
+		service.TaskEnqueue(ctx, run, replySchema),
 		http.StatusOK)
 }
 
+// getSnapshotsPage returns the page of snapshots specified by the page
+// options from a driver that is able to page snapshots natively. The driver
+// is asked for as many of its pages as it takes to fill the requested page
+// with the snapshots that match the filter, and the driver's continuation
+// token for the last of them is returned to the client as-is.
+func getSnapshotsPage(
+	ctx types.Context,
+	store types.Store,
+	d types.StorageDriverWithSnapshotsPage,
+	filter *types.Filter,
+	page *types.PageOpts) (*paging.Result, error) {
+
+	// the offset counts the snapshots that match the filter, so it is
+	// applied here rather than by the driver
+	var (
+		reply = []*types.Snapshot{}
+		skip  = page.Offset
+		p     = *page
+	)
+	p.Offset = 0
+
+	for {
+		// the driver is asked for no more snapshots than are needed to fill
+		// the page so that its continuation token follows the page's last
+		// snapshot
+		if page.Limit > 0 {
+			p.Limit = page.Limit - len(reply) + skip
+		}
+
+		objs, next, err := d.SnapshotsPage(ctx, store, &p)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range objs {
+			if filter != nil && !filters.MatchSnapshot(filter, obj) {
+				continue
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+			reply = append(reply, obj)
+		}
+
+		if next == "" || (page.Limit > 0 && len(reply) == page.Limit) {
+			return &paging.Result{Items: reply, Next: next}, nil
+		}
+		p.Marker = next
+	}
+}
+
 func (r *router) snapshotInspect(
 	ctx types.Context,
 	w http.ResponseWriter,
@@ -194,6 +304,7 @@ func (r *router) volumeCreate(
 				Type:             store.GetStringPtr("type"),
 				Opts:             store,
 			})
+		services.QuotaComplete(ctx, v, err)
 
 		if err != nil {
 			return nil, err
@@ -0,0 +1,94 @@
+package snapshot
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils/filters"
+)
+
+// testPageDriver pages its snapshots natively. Its continuation token is the
+// index of the next snapshot.
+type testPageDriver struct {
+	types.StorageDriver
+	snaps []*types.Snapshot
+	calls int
+}
+
+func (d *testPageDriver) SnapshotsPage(
+	ctx types.Context,
+	opts types.Store,
+	page *types.PageOpts) ([]*types.Snapshot, string, error) {
+
+	d.calls++
+	start := 0
+	if page.Marker != "" {
+		start, _ = strconv.Atoi(page.Marker)
+	}
+	end := len(d.snaps)
+	if page.Limit > 0 && start+page.Limit < end {
+		end = start + page.Limit
+	}
+	if end == len(d.snaps) {
+		return d.snaps[start:end], "", nil
+	}
+	return d.snaps[start:end], strconv.Itoa(end), nil
+}
+
+func newTestPageDriver() *testPageDriver {
+	d := &testPageDriver{}
+	for i, name := range []string{
+		"keep", "drop", "drop", "keep", "keep", "drop", "keep", "drop",
+	} {
+		d.snaps = append(d.snaps, &types.Snapshot{
+			ID:   "snap-00" + strconv.Itoa(i),
+			Name: name,
+		})
+	}
+	return d
+}
+
+func snapshotIDs(r interface{}) []string {
+	ids := []string{}
+	for _, s := range r.([]*types.Snapshot) {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+func TestGetSnapshotsPageFiltersBeforePaging(t *testing.T) {
+	ctx := context.Background()
+	filter, err := filters.CompileFilter("(name=keep)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := newTestPageDriver()
+
+	// the page is filled from as many of the driver's pages as it takes
+	r, err := getSnapshotsPage(
+		ctx, nil, d, filter, &types.PageOpts{Limit: 3})
+	assert.NoError(t, err)
+	assert.Equal(t,
+		[]string{"snap-000", "snap-003", "snap-004"}, snapshotIDs(r.Items))
+	assert.Equal(t, "5", r.NextMarker())
+	assert.Equal(t, 2, d.calls)
+
+	// and the next page begins after the last snapshot of the previous one
+	r, err = getSnapshotsPage(
+		ctx, nil, d, filter,
+		&types.PageOpts{Limit: 3, Marker: r.NextMarker()})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"snap-006"}, snapshotIDs(r.Items))
+	assert.Empty(t, r.NextMarker())
+
+	// the offset counts the snapshots that match the filter
+	r, err = getSnapshotsPage(
+		ctx, nil, d, filter, &types.PageOpts{Limit: 2, Offset: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"snap-004", "snap-006"}, snapshotIDs(r.Items))
+	assert.Equal(t, "7", r.NextMarker())
+}
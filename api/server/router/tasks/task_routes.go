@@ -38,3 +38,24 @@ func (r *router) taskInspect(
 	httputils.WriteJSON(w, http.StatusOK, task)
 	return nil
 }
+
+func (r *router) taskCancel(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	task, err := services.TaskCancel(ctx, store.GetInt("taskID"))
+	if err != nil {
+		return err
+	}
+
+	// a running task is cancelled asynchronously
+	if task.State == types.TaskStateRunning {
+		httputils.WriteJSON(w, http.StatusAccepted, task)
+		return nil
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, task)
+	return nil
+}
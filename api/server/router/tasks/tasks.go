@@ -4,6 +4,7 @@ import (
 	gofig "github.com/akutz/gofig/types"
 
 	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/server/handlers"
 	"github.com/codedellemc/libstorage/api/server/httputils"
 	"github.com/codedellemc/libstorage/api/types"
 )
@@ -44,5 +45,12 @@ func (r *router) initRoutes() {
 			"taskInspect",
 			"/tasks/{taskID}",
 			r.taskInspect),
+
+		// DELETE
+		httputils.NewDeleteRoute(
+			"taskCancel",
+			"/tasks/{taskID}",
+			r.taskCancel,
+			handlers.NewAuthTaskHandler()),
 	}
 }
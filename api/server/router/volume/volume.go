@@ -112,6 +112,7 @@ func (r *router) initRoutes() {
 				schema.VolumeSchema,
 				func() interface{} { return &types.VolumeCreateRequest{} }),
 			handlers.NewPostArgsHandler(r.config),
+			handlers.NewQuotaHandler(),
 		),
 
 		// create a new volume using an existing volume as the baseline
@@ -127,8 +128,25 @@ func (r *router) initRoutes() {
 				schema.VolumeSchema,
 				func() interface{} { return &types.VolumeCopyRequest{} }),
 			handlers.NewPostArgsHandler(r.config),
+			handlers.NewQuotaHandler(),
 		).Queries("copy"),
 
+		// resize an existing volume
+		httputils.NewPostRoute(
+			"volumeResize",
+			"/volumes/{service}/{volumeID}",
+			r.volumeResize,
+			handlers.NewServiceValidator(),
+			handlers.NewAuthSvcHandler(),
+			handlers.NewStorageSessionHandler(),
+			handlers.NewSchemaValidator(
+				schema.VolumeResizeRequestSchema,
+				schema.VolumeSchema,
+				func() interface{} { return &types.VolumeResizeRequest{} }),
+			handlers.NewPostArgsHandler(r.config),
+			handlers.NewQuotaHandler(),
+		).Queries("resize"),
+
 		// snapshot an existing volume
 		httputils.NewPostRoute(
 			"volumeSnapshot",
@@ -9,11 +9,13 @@ import (
 	"github.com/akutz/goof"
 
 	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/registry"
 	"github.com/codedellemc/libstorage/api/server/httputils"
 	"github.com/codedellemc/libstorage/api/server/services"
 	"github.com/codedellemc/libstorage/api/types"
 	"github.com/codedellemc/libstorage/api/utils"
 	"github.com/codedellemc/libstorage/api/utils/filters"
+	"github.com/codedellemc/libstorage/api/utils/paging"
 	"github.com/codedellemc/libstorage/api/utils/schema"
 )
 
@@ -23,7 +25,7 @@ func (r *router) volumes(
 	req *http.Request,
 	store types.Store) error {
 
-	filter, err := parseFilter(store)
+	filter, err := parseVolumeFilter(store)
 	if err != nil {
 		return err
 	}
@@ -31,6 +33,11 @@ func (r *router) volumes(
 		store.Set("filter", filter)
 	}
 
+	page, err := paging.ParsePageOpts(store)
+	if err != nil {
+		return err
+	}
+
 	var (
 		tasks   = map[string]*types.Task{}
 		taskIDs []int
@@ -79,15 +86,24 @@ func (r *router) volumes(
 			reply[k] = objMap
 		}
 
+		if page != nil {
+			return paging.ServiceVolumeMap(reply, page)
+		}
+
 		return reply, nil
 	}
 
+	replySchema := schema.ServiceVolumeMapSchema
+	if page != nil {
+		replySchema = schema.ServiceVolumeListSchema
+	}
+
 	return httputils.WriteTask(
 		ctx,
 		r.config,
 		w,
 		store,
-		services.TaskEnqueue(ctx, run, schema.ServiceVolumeMapSchema),
+		services.TaskEnqueue(ctx, run, replySchema),
 		http.StatusOK)
 }
 
@@ -97,7 +113,7 @@ func (r *router) volumesForService(
 	req *http.Request,
 	store types.Store) error {
 
-	filter, err := parseFilter(store)
+	filter, err := parseVolumeFilter(store)
 	if err != nil {
 		return err
 	}
@@ -105,6 +121,11 @@ func (r *router) volumesForService(
 		store.Set("filter", filter)
 	}
 
+	page, err := paging.ParsePageOpts(store)
+	if err != nil {
+		return err
+	}
+
 	service := context.MustService(ctx)
 
 	opts := &types.VolumesOpts{
@@ -116,15 +137,23 @@ func (r *router) volumesForService(
 		ctx types.Context,
 		svc types.StorageService) (interface{}, error) {
 
+		if page != nil {
+			return getVolumesPage(ctx, req, store, svc, opts, filter, page)
+		}
 		return getFilteredVolumes(ctx, req, store, svc, opts, filter)
 	}
 
+	replySchema := schema.VolumeMapSchema
+	if page != nil {
+		replySchema = schema.VolumeListSchema
+	}
+
 	return httputils.WriteTask(
 		ctx,
 		r.config,
 		w,
 		store,
-		service.TaskEnqueue(ctx, run, schema.VolumeMapSchema),
+		service.TaskEnqueue(ctx, run, replySchema),
 		http.StatusOK)
 }
 
@@ -216,13 +245,6 @@ func getFilteredVolumes(
 	opts *types.VolumesOpts,
 	filter *types.Filter) (types.VolumeMap, error) {
 
-	var (
-		filterOp    types.FilterOperator
-		filterLeft  string
-		filterRight string
-		objMap      = types.VolumeMap{}
-	)
-
 	iid, iidOK := context.InstanceID(ctx)
 	if opts.Attachments.RequiresInstanceID() && !iidOK {
 		return nil, utils.NewMissingInstanceIDError(storSvc.Name())
@@ -235,12 +257,98 @@ func getFilteredVolumes(
 		return nil, err
 	}
 
-	if filter != nil {
-		filterOp = filter.Op
-		filterLeft = strings.ToLower(filter.Left)
-		filterRight = strings.ToLower(filter.Right)
+	return filterVolumes(ctx, req, store, iid, opts, filter, objs)
+}
+
+// getVolumesPage returns the page of volumes specified by the page options.
+// Drivers that are able to page volumes natively are asked for as many of
+// their pages as it takes to fill the requested page with the volumes that
+// are not filtered out, and the driver's continuation token for the last of
+// them is returned to the client as-is.
+func getVolumesPage(
+	ctx types.Context,
+	req *http.Request,
+	store types.Store,
+	storSvc types.StorageService,
+	opts *types.VolumesOpts,
+	filter *types.Filter,
+	page *types.PageOpts) (*paging.Result, error) {
+
+	d, ok := registry.UnwrapStorageDriver(
+		storSvc.Driver()).(types.StorageDriverWithVolumesPage)
+	if !ok {
+		objMap, err := getFilteredVolumes(
+			ctx, req, store, storSvc, opts, filter)
+		if err != nil {
+			return nil, err
+		}
+		return paging.VolumeMap(objMap, page)
+	}
+
+	iid, iidOK := context.InstanceID(ctx)
+	if opts.Attachments.RequiresInstanceID() && !iidOK {
+		return nil, utils.NewMissingInstanceIDError(storSvc.Name())
 	}
 
+	ctx.WithField("attachments", opts.Attachments).Debug(
+		"querying volumes page")
+
+	// the offset counts the volumes that are not filtered out, so it is
+	// applied here rather than by the driver
+	var (
+		reply = []*types.Volume{}
+		skip  = page.Offset
+		p     = *page
+	)
+	p.Offset = 0
+
+	for {
+		// the driver is asked for no more volumes than are needed to fill
+		// the page so that its continuation token follows the page's last
+		// volume
+		if page.Limit > 0 {
+			p.Limit = page.Limit - len(reply) + skip
+		}
+
+		objs, next, err := d.VolumesPage(ctx, opts, &p)
+		if err != nil {
+			return nil, err
+		}
+
+		objMap, err := filterVolumes(ctx, req, store, iid, opts, filter, objs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range objs {
+			if _, ok := objMap[obj.ID]; !ok {
+				continue
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+			reply = append(reply, obj)
+		}
+
+		if next == "" || (page.Limit > 0 && len(reply) == page.Limit) {
+			return &paging.Result{Items: reply, Next: next}, nil
+		}
+		p.Marker = next
+	}
+}
+
+func filterVolumes(
+	ctx types.Context,
+	req *http.Request,
+	store types.Store,
+	iid *types.InstanceID,
+	opts *types.VolumesOpts,
+	filter *types.Filter,
+	objs []*types.Volume) (types.VolumeMap, error) {
+
+	objMap := types.VolumeMap{}
+
 	for _, obj := range objs {
 
 		lf := log.Fields{
@@ -249,12 +357,9 @@ func getFilteredVolumes(
 			"volumeName":  obj.Name,
 		}
 
-		if filterOp == types.FilterEqualityMatch && filterLeft == "name" {
-			ctx.WithFields(lf).Debug("checking name filter")
-			if !strings.EqualFold(obj.Name, filterRight) {
-				ctx.WithFields(lf).Debug("omitted volume due to name filter")
-				continue
-			}
+		if filter != nil && !filters.MatchVolume(filter, obj) {
+			ctx.WithFields(lf).Debug("omitted volume due to filter")
+			continue
 		}
 
 		if !handleVolAttachments(ctx, lf, iid, obj, opts.Attachments) {
@@ -309,7 +414,9 @@ func (r *router) volumeInspect(
 			)
 			volID := store.GetString("volumeID")
 
-			if sd, ok := svc.Driver().(types.StorageDriverVolInspectByName); ok {
+			sd, ok := registry.UnwrapStorageDriver(
+				svc.Driver()).(types.StorageDriverVolInspectByName)
+			if ok {
 				ctx.Debug("driver is StorageDriverVolInspectByName")
 				vol, err = sd.VolumeInspectByName(
 					ctx, volID, opts)
@@ -419,6 +526,9 @@ func (r *router) volumeCreate(
 			EncryptionKey:    store.GetStringPtr("encryptionKey"),
 			Opts:             store,
 		}
+		if labels, ok := store.Get("labels").(map[string]string); ok {
+			opts.Labels = labels
+		}
 		fields := map[string]interface{}{
 			"volumeName": store.GetString("name"),
 		}
@@ -440,9 +550,13 @@ func (r *router) volumeCreate(
 		if opts.Type != nil {
 			fields["type"] = &opts.Type
 		}
+		if len(opts.Labels) > 0 {
+			fields["labels"] = opts.Labels
+		}
 		ctx.WithFields(fields).Debug("creating volume")
 
 		v, err := svc.Driver().VolumeCreate(ctx, volumeName, opts)
+		services.QuotaComplete(ctx, v, err)
 		if err != nil {
 			ctx.WithFields(fields).WithError(err).Error("error creating volume")
 			return nil, err
@@ -494,6 +608,7 @@ func (r *router) volumeCopy(
 			store.GetString("volumeID"),
 			store.GetString("volumeName"),
 			store)
+		services.QuotaComplete(ctx, v, err)
 
 		if err != nil {
 			return nil, err
@@ -524,6 +639,63 @@ func (r *router) volumeCopy(
 		http.StatusCreated)
 }
 
+func (r *router) volumeResize(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	service := context.MustService(ctx)
+
+	run := func(
+		ctx types.Context,
+		svc types.StorageService) (interface{}, error) {
+
+		// the driver manager records the call's metrics and publishes its
+		// event, and returns ErrNotImplemented if the driver cannot resize
+		sd, ok := svc.Driver().(types.StorageDriverWithVolumeResize)
+		if !ok {
+			ctx.Debug("driver is not StorageDriverWithVolumeResize")
+			services.QuotaComplete(ctx, nil, types.ErrNotImplemented)
+			return nil, types.ErrNotImplemented
+		}
+
+		v, err := sd.VolumeResize(
+			ctx,
+			store.GetString("volumeID"),
+			&types.VolumeResizeOpts{
+				Size:  store.GetInt64Ptr("size"),
+				Force: store.GetBool("force"),
+				Opts:  store,
+			})
+		services.QuotaComplete(ctx, v, err)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if OnVolume != nil {
+			ok, err := OnVolume(ctx, req, store, v)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, utils.NewNotFoundError(v.ID)
+			}
+		}
+
+		return v, nil
+	}
+
+	return httputils.WriteTask(
+		ctx,
+		r.config,
+		w,
+		store,
+		service.TaskEnqueue(ctx, run, schema.VolumeSchema),
+		http.StatusOK)
+}
+
 func (r *router) volumeSnapshot(
 	ctx types.Context,
 	w http.ResponseWriter,
@@ -863,13 +1035,20 @@ func (r *router) volumeRemove(
 		ctx types.Context,
 		svc types.StorageService) (interface{}, error) {
 
-		return nil, svc.Driver().VolumeRemove(
+		volumeID := store.GetString("volumeID")
+		err := svc.Driver().VolumeRemove(
 			ctx,
-			store.GetString("volumeID"),
+			volumeID,
 			&types.VolumeRemoveOpts{
 				Force: store.GetBool("force"),
 				Opts:  store,
 			})
+		if err != nil {
+			return nil, err
+		}
+
+		services.QuotaRelease(ctx, svc.Name(), volumeID)
+		return nil, nil
 	}
 
 	return httputils.WriteTask(
@@ -881,7 +1060,9 @@ func (r *router) volumeRemove(
 		http.StatusNoContent)
 }
 
-func parseFilter(store types.Store) (*types.Filter, error) {
+// ParseFilter compiles the filter specified by the store's "filter" key. A
+// nil value is returned if no filter is specified.
+func ParseFilter(store types.Store) (*types.Filter, error) {
 	if !store.IsSet("filter") {
 		return nil, nil
 	}
@@ -892,3 +1073,22 @@ func parseFilter(store types.Store) (*types.Filter, error) {
 	}
 	return filter, nil
 }
+
+// parseVolumeFilter compiles the filter specified by the store's "filter" key
+// and the label selector specified by the store's "labelSelector" key into a
+// single filter. A nil value is returned if neither is specified.
+func parseVolumeFilter(store types.Store) (*types.Filter, error) {
+	filter, err := ParseFilter(store)
+	if err != nil {
+		return nil, err
+	}
+	if !store.IsSet("labelSelector") {
+		return filter, nil
+	}
+	sel := store.GetString("labelSelector")
+	labels, err := filters.CompileLabelSelector(sel)
+	if err != nil {
+		return nil, utils.NewBadFilterErr(sel, err)
+	}
+	return filters.And(filter, labels), nil
+}
@@ -7,6 +7,7 @@ import (
 	"os/signal"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 
 	"github.com/codedellemc/libstorage/api/context"
 	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/server/executors"
 	"github.com/codedellemc/libstorage/api/server/services"
 	"github.com/codedellemc/libstorage/api/types"
 	"github.com/codedellemc/libstorage/api/utils"
@@ -42,11 +44,14 @@ type server struct {
 	ctx          types.Context
 	addrs        []string
 	config       gofig.Config
-	authConfig   *types.AuthConfig
+	newConfig    func() (gofig.Config, error)
+	authConfig   atomic.Value
 	servers      []*HTTPServer
 	closeSignal  chan int
 	closedSignal chan int
 	closeOnce    *sync.Once
+	reloadLock   sync.Mutex
+	hangupSignal chan int
 
 	routers        []types.Router
 	routeHandlers  map[string][]types.Middleware
@@ -109,12 +114,23 @@ func newServer(goCtx gocontext.Context, config gofig.Config) (*server, error) {
 				logger.Out, logger.Level, logger.Formatter))
 	}
 
-	if config == nil {
-		var err error
-		if config, err = apicnfg.NewConfig(ctx); err != nil {
-			return nil, err
+	// the server's configuration is re-read from the config files when it is
+	// reloaded, unless the caller provided the configuration, in which case
+	// that object is applied again
+	newConfig := func() (gofig.Config, error) {
+		return apicnfg.NewConfig(ctx)
+	}
+	if config != nil {
+		userConfig := config
+		newConfig = func() (gofig.Config, error) {
+			return userConfig, nil
 		}
 	}
+
+	config, err = newConfig()
+	if err != nil {
+		return nil, err
+	}
 	config = config.Scope(types.ConfigServer)
 
 	s := &server{
@@ -122,10 +138,13 @@ func newServer(goCtx gocontext.Context, config gofig.Config) (*server, error) {
 		name:         serverName,
 		adminToken:   adminToken,
 		config:       config,
+		newConfig:    newConfig,
 		closeSignal:  make(chan int),
 		closedSignal: make(chan int),
 		closeOnce:    &sync.Once{},
+		hangupSignal: make(chan int),
 	}
+	s.ctx = s.ctx.WithValue(context.ServerReloadKey, s.Reload)
 
 	if logger, ok := s.ctx.Value(context.LoggerKey).(*log.Logger); ok {
 		s.PrintServerStartupHeader(logger.Out)
@@ -155,8 +174,8 @@ func newServer(goCtx gocontext.Context, config gofig.Config) (*server, error) {
 	if err != nil {
 		return nil, err
 	}
-	s.authConfig = authConfig
-	if s.authConfig != nil {
+	s.authConfig.Store(authConfig)
+	if authConfig != nil {
 		s.ctx.WithFields(authFields).Info("configured global auth")
 	}
 
@@ -172,6 +191,14 @@ func newServer(goCtx gocontext.Context, config gofig.Config) (*server, error) {
 	}
 	s.ctx.Info("initialized services")
 
+	if dir := s.config.GetString(
+		types.ConfigServerExecutorsSignatures); dir != "" {
+		if err := executors.LoadSignatures(dir); err != nil {
+			return nil, err
+		}
+		s.ctx.WithField("path", dir).Info("loaded executor signatures")
+	}
+
 	if logConfig.HTTPRequests || logConfig.HTTPResponses {
 		s.logHTTPEnabled = true
 		s.logHTTPRequests = logConfig.HTTPRequests
@@ -232,6 +259,8 @@ func Serve(
 		}(srv)
 	}
 
+	go s.reloadOnHangup()
+
 	go func() {
 		s.ctx.Info("waiting for err or close signal")
 		select {
@@ -287,6 +316,8 @@ func (s *server) Close() (err error) {
 func (s *server) close() error {
 	s.ctx.Info("shutting down server")
 
+	close(s.hangupSignal)
+
 	for _, srv := range s.servers {
 		srv.ctx.Info("shutting down endpoint")
 		if err := srv.Close(); err != nil {
@@ -319,13 +350,13 @@ func (s *server) close() error {
 }
 
 // CloseOnAbort is a helper function that can be called by programs, such as
-// tests or a command line or service application.
+// tests or a command line or service application. SIGHUP does not close the
+// servers since it reloads their configuration.
 func CloseOnAbort() {
 	// make sure all servers get closed even if the test is abrubptly aborted
 	sigc := make(chan os.Signal, 1)
 	signal.Notify(sigc,
 		syscall.SIGKILL,
-		syscall.SIGHUP,
 		syscall.SIGINT,
 		syscall.SIGTERM,
 		syscall.SIGQUIT)
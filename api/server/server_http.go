@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"sync"
+	"sync/atomic"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/akutz/goof"
@@ -34,8 +35,11 @@ func (s *server) initDefaultEndpoint() error {
 
 	var endpointConfig string
 
-	if host := s.config.GetString(types.ConfigHost); host != "" {
+	// a client may be configured with many hosts, of which an embedded
+	// server listens on the first
+	if hosts := utils.GetHosts(s.config); len(hosts) > 0 {
 
+		host := hosts[0]
 		s.ctx.WithField("host", host).Info("initializing default endpoint")
 		endpointConfig = fmt.Sprintf(defaultEndpointConfig, host)
 
@@ -230,12 +234,20 @@ func (s *server) newHTTPServer(
 	proto, laddr string, tlsConfig *types.TLSConfig) (*HTTPServer, error) {
 
 	var (
-		l   net.Listener
-		err error
+		l    net.Listener
+		err  error
+		tlsV *atomic.Value
 	)
 
 	if tlsConfig != nil {
-		l, err = tls.Listen(proto, laddr, &tlsConfig.Config)
+		if len(tlsConfig.Certificates) == 0 {
+			return nil, goof.New("tls enabled without a certificate")
+		}
+		if l, err = net.Listen(proto, laddr); err == nil {
+			tlsV = &atomic.Value{}
+			tlsV.Store(&tlsConfig.Config)
+			l = &tlsListener{Listener: l, config: tlsV}
+		}
 	} else {
 		l, err = net.Listen(proto, laddr)
 	}
@@ -255,12 +267,31 @@ func (s *server) newHTTPServer(
 	srv.ErrorLog = golog.New(errLogger, "", 0)
 
 	return &HTTPServer{
-		srv: srv,
-		l:   l,
-		ctx: ctx,
+		srv:   srv,
+		l:     l,
+		ctx:   ctx,
+		proto: proto,
+		tls:   tlsV,
 	}, nil
 }
 
+// tlsListener is a TLS listener that uses the endpoint's current TLS
+// configuration for each accepted connection so that reloaded certificates
+// are presented to new connections.
+type tlsListener struct {
+	net.Listener
+	config *atomic.Value
+}
+
+// Accept waits for and returns the next connection to the listener.
+func (l *tlsListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return tls.Server(c, l.config.Load().(*tls.Config)), nil
+}
+
 // HTTPServer contains an instance of http server and the listener.
 //
 // srv *http.Server, contains configuration to create a http server and a mux
@@ -268,10 +299,15 @@ func (s *server) newHTTPServer(
 //
 // l   net.Listener, is a TCP or Socket listener that dispatches incoming
 // request to the router.
+//
+// tls *atomic.Value, holds the endpoint's current *tls.Config and is nil
+// when TLS is disabled for the endpoint.
 type HTTPServer struct {
-	srv *http.Server
-	l   net.Listener
-	ctx types.Context
+	srv   *http.Server
+	l     net.Listener
+	ctx   types.Context
+	proto string
+	tls   *atomic.Value
 }
 
 // Serve starts listening for inbound requests.
@@ -17,9 +17,19 @@ func (s *server) initGlobalMiddleware() {
 	}
 	s.addGlobalMiddleware(handlers.NewTransactionHandler())
 	s.addGlobalMiddleware(handlers.NewErrorHandler())
-	s.addGlobalMiddleware(handlers.NewAuthGlobalHandler(s.authConfig))
-	s.addGlobalMiddleware(
-		handlers.NewInstanceIDHandler(services.StorageServices(s.ctx)))
+	s.addGlobalMiddleware(handlers.NewMetricsHandler())
+	if !s.config.GetBool(types.ConfigServerAuditDisabled) {
+		s.addGlobalMiddleware(handlers.NewAuditHandler())
+	}
+	s.addGlobalMiddleware(handlers.NewAuthGlobalHandler(s.getAuthConfig))
+	s.addGlobalMiddleware(handlers.NewInstanceIDHandler(
+		func() <-chan types.StorageService {
+			return services.StorageServices(s.ctx)
+		}))
+	s.addGlobalMiddleware(handlers.NewRateLimitHandler(s.config))
+	if !s.config.GetBool(types.ConfigServerIdempotencyDisabled) {
+		s.addGlobalMiddleware(handlers.NewIdempotencyHandler(s.config))
+	}
 	s.addGlobalMiddleware(handlers.NewLocalDevicesHandler())
 	s.addGlobalMiddleware(handlers.NewOnRequestHandler())
 }
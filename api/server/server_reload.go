@@ -0,0 +1,143 @@
+package server
+
+import (
+	"crypto/tls"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+
+	log "github.com/Sirupsen/logrus"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/server/services"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+// Reload re-reads the server's configuration and applies it to the running
+// server. The storage services whose configuration changed are initialized
+// again, the global auth configuration is replaced, and the endpoints'
+// certificates are reloaded for new connections. The new configuration is
+// validated in its entirety before any of it is applied, so an invalid
+// configuration is rejected without disrupting the server.
+//
+// A server's endpoints cannot be added, removed, or moved, nor can TLS be
+// enabled or disabled for an endpoint, without restarting the server. Nor can
+// the configuration that is only read when the server starts be changed; see
+// reloadRestartKeys.
+func (s *server) Reload() error {
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+
+	s.ctx.Info("reloading server config")
+
+	config, err := s.newConfig()
+	if err != nil {
+		return goof.WithError("error reading server config", err)
+	}
+	config = config.Scope(types.ConfigServer)
+
+	if err := checkRestartKeys(s.config, config); err != nil {
+		return err
+	}
+
+	authFields := log.Fields{}
+	authConfig, err := utils.ParseAuthConfig(
+		s.ctx, config, authFields, types.ConfigServer)
+	if err != nil {
+		return goof.WithError("error parsing auth config", err)
+	}
+
+	tlsConfigs := make([]*tls.Config, len(s.servers))
+	for i, srv := range s.servers {
+		logFields := map[string]interface{}{
+			"address": srv.l.Addr().String(),
+		}
+		tlsConfig, err := utils.ParseTLSConfig(
+			s.ctx, config, srv.proto, logFields, types.ConfigServer)
+		if err != nil {
+			return goof.WithError("error parsing tls config", err)
+		}
+		if (tlsConfig != nil) != (srv.tls != nil) {
+			return goof.WithFields(logFields,
+				"enabling or disabling tls requires a restart")
+		}
+		if tlsConfig == nil {
+			continue
+		}
+		if len(tlsConfig.Certificates) == 0 {
+			return goof.WithFields(logFields,
+				"tls enabled without a certificate")
+		}
+		tlsConfigs[i] = &tlsConfig.Config
+	}
+
+	if err := services.Reload(s.ctx, config); err != nil {
+		return goof.WithError("error reloading services", err)
+	}
+
+	s.authConfig.Store(authConfig)
+	if authConfig != nil {
+		s.ctx.WithFields(authFields).Info("reloaded global auth")
+	}
+
+	for i, srv := range s.servers {
+		if tlsConfigs[i] != nil {
+			srv.tls.Store(tlsConfigs[i])
+			srv.ctx.Info("reloaded tls config")
+		}
+	}
+
+	s.ctx.Info("reloaded server config")
+	return nil
+}
+
+// reloadRestartKeys are the config keys that are only read when the server
+// starts. A reload that changes any of them is rejected.
+var reloadRestartKeys = []string{
+	types.ConfigServerQuotas,
+	types.ConfigServerAudit,
+	types.ConfigServerRateLimit,
+	types.ConfigServerWebhooks,
+}
+
+// checkRestartKeys returns an error if the new configuration changes any of
+// the config keys that require a restart.
+func checkRestartKeys(oldConfig, newConfig gofig.Config) error {
+	for _, k := range reloadRestartKeys {
+		if !reflect.DeepEqual(oldConfig.Get(k), newConfig.Get(k)) {
+			return goof.WithField(
+				"key", k, "changing config key requires a restart")
+		}
+	}
+	return nil
+}
+
+// getAuthConfig returns the server's current global auth configuration.
+func (s *server) getAuthConfig() *types.AuthConfig {
+	authConfig, _ := s.authConfig.Load().(*types.AuthConfig)
+	return authConfig
+}
+
+// reloadOnHangup reloads the server's configuration each time the process
+// receives a SIGHUP until the server is closed.
+func (s *server) reloadOnHangup() {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGHUP)
+	defer signal.Stop(sigc)
+
+	for {
+		select {
+		case <-sigc:
+			s.ctx.Info("received hangup signal")
+			if err := s.Reload(); err != nil {
+				s.ctx.WithError(err).Error(
+					"error reloading server config")
+			}
+		case <-s.hangupSignal:
+			return
+		}
+	}
+}
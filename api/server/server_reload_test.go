@@ -0,0 +1,111 @@
+package server
+
+import (
+	"bytes"
+	"testing"
+
+	gofigCore "github.com/akutz/gofig"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+const reloadTestConfig = `
+libstorage:
+  server:
+    tasks:
+      logTimeout: 1m
+    audit:
+      recent: 100
+    rateLimit:
+      rate: 10
+      burst: 20
+`
+
+func newReloadTestConfig(t *testing.T, yml string) gofig.Config {
+	config := gofigCore.New()
+	if err := config.ReadConfig(bytes.NewReader([]byte(yml))); err != nil {
+		t.Fatal(err)
+	}
+	return config.Scope(types.ConfigServer)
+}
+
+func TestCheckRestartKeys(t *testing.T) {
+	oldConfig := newReloadTestConfig(t, reloadTestConfig)
+
+	newConfig := newReloadTestConfig(t, reloadTestConfig)
+	assert.NoError(t, checkRestartKeys(oldConfig, newConfig))
+
+	// the keys that are read whenever they are used may be changed
+	newConfig = newReloadTestConfig(t, `
+libstorage:
+  server:
+    tasks:
+      logTimeout: 5m
+    audit:
+      recent: 100
+    rateLimit:
+      rate: 10
+      burst: 20
+`)
+	assert.NoError(t, checkRestartKeys(oldConfig, newConfig))
+
+	// while those that are read when the server starts may not
+	newConfig = newReloadTestConfig(t, `
+libstorage:
+  server:
+    tasks:
+      logTimeout: 1m
+    audit:
+      recent: 100
+    rateLimit:
+      rate: 5
+      burst: 20
+`)
+	err := checkRestartKeys(oldConfig, newConfig)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "requires a restart")
+	}
+
+	newConfig = newReloadTestConfig(t, `
+libstorage:
+  server:
+    tasks:
+      logTimeout: 1m
+    audit:
+      recent: 100
+    rateLimit:
+      rate: 10
+      burst: 20
+    webhooks:
+      targets:
+      - url: http://localhost:8080/events
+`)
+	assert.Error(t, checkRestartKeys(oldConfig, newConfig))
+}
+
+func TestReloadRejectsRestartKeys(t *testing.T) {
+	s := &server{
+		ctx:    context.Background(),
+		config: newReloadTestConfig(t, reloadTestConfig),
+		newConfig: func() (gofig.Config, error) {
+			config := gofigCore.New()
+			err := config.ReadConfig(bytes.NewReader([]byte(`
+libstorage:
+  server:
+    audit:
+      disabled: true
+`)))
+			return config, err
+		},
+	}
+
+	// the reload is rejected before any of the configuration is applied
+	err := s.Reload()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "requires a restart")
+	}
+	assert.Nil(t, s.getAuthConfig())
+}
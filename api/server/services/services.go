@@ -21,6 +21,10 @@ type serviceContainer struct {
 	config          gofig.Config
 	storageServices map[string]types.StorageService
 	taskService     *globalTaskService
+	quotaLedger     *quotaLedger
+	auditLog        *auditLog
+	eventBus        *eventBus
+	webhooks        *webhooks
 }
 
 // Init initializes the types.
@@ -36,6 +40,10 @@ func Init(ctx types.Context, config gofig.Config) error {
 	sc := &serviceContainer{
 		taskService:     &globalTaskService{name: "global-task-service"},
 		storageServices: map[string]types.StorageService{},
+		quotaLedger:     &quotaLedger{},
+		auditLog:        &auditLog{},
+		eventBus:        newEventBus(),
+		webhooks:        &webhooks{},
 	}
 
 	if err := sc.Init(ctx, config); err != nil {
@@ -60,6 +68,18 @@ func (sc *serviceContainer) Init(ctx types.Context, config gofig.Config) error {
 		return err
 	}
 
+	if err := sc.quotaLedger.Init(ctx, config); err != nil {
+		return err
+	}
+
+	if err := sc.auditLog.Init(ctx, config); err != nil {
+		return err
+	}
+
+	if err := sc.webhooks.Init(ctx, config); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -88,9 +108,13 @@ func GetStorageService(
 // StorageServices returns a channel on which all the storage services are
 // received.
 func StorageServices(ctx types.Context) <-chan types.StorageService {
+	servicesByServerRWL.RLock()
+	storServices := getStorageServices(ctx)
+	servicesByServerRWL.RUnlock()
+
 	c := make(chan types.StorageService)
 	go func() {
-		for _, v := range getStorageServices(ctx) {
+		for _, v := range storServices {
 			c <- v
 		}
 		close(c)
@@ -105,16 +129,38 @@ func (sc *serviceContainer) initStorageServices(ctx types.Context) error {
 	if sc.config == nil {
 		panic("sc.config is nil")
 	}
-	cfgSvcs := sc.config.Get(types.ConfigServices)
+	cfgSvcsMap, err := getServicesConfig(sc.config)
+	if err != nil {
+		return err
+	}
+	ctx.WithField("count", len(cfgSvcsMap)).Debug("got services map")
+
+	for serviceName := range cfgSvcsMap {
+		serviceName = strings.ToLower(serviceName)
+		storSvc, err := newStorageService(ctx, sc.config, serviceName)
+		if err != nil {
+			return err
+		}
+		sc.storageServices[serviceName] = storSvc
+	}
+
+	return nil
+}
+
+// getServicesConfig returns the map of the services defined by the given
+// configuration. If no services are defined then a single service is
+// returned for the configured libStorage driver.
+func getServicesConfig(config gofig.Config) (map[string]interface{}, error) {
+	cfgSvcs := config.Get(types.ConfigServices)
 	cfgSvcsMap, ok := cfgSvcs.(map[string]interface{})
 	if !ok {
-		driverName := sc.config.GetString("libstorage.driver")
+		driverName := config.GetString("libstorage.driver")
 		if driverName == "" {
 			err := goof.WithFields(goof.Fields{
 				"configKey": types.ConfigServices,
 				"obj":       cfgSvcs,
 			}, "invalid format")
-			return err
+			return nil, err
 		}
 
 		cfgSvcsMap = map[string]interface{}{
@@ -123,30 +169,30 @@ func (sc *serviceContainer) initStorageServices(ctx types.Context) error {
 			},
 		}
 	}
-	ctx.WithField("count", len(cfgSvcsMap)).Debug("got services map")
-
-	for serviceName := range cfgSvcsMap {
-		serviceName = strings.ToLower(serviceName)
+	return cfgSvcsMap, nil
+}
 
-		storSvc := &storageService{name: serviceName}
+func newStorageService(
+	ctx types.Context,
+	config gofig.Config,
+	serviceName string) (*storageService, error) {
 
-		ctx := ctx.WithValue(context.StorageServiceKey, storSvc)
-		ctx.Debug("processing service config")
+	storSvc := &storageService{name: serviceName}
 
-		scope := fmt.Sprintf("libstorage.server.services.%s", serviceName)
-		ctx.WithField("scope", scope).Debug(
-			"getting scoped config for service")
-		config := sc.config.Scope(scope)
+	ctx = ctx.WithValue(context.StorageServiceKey, storSvc)
+	ctx.Debug("processing service config")
 
-		if err := storSvc.Init(ctx, config); err != nil {
-			return err
-		}
+	scope := fmt.Sprintf("libstorage.server.services.%s", serviceName)
+	ctx.WithField("scope", scope).Debug(
+		"getting scoped config for service")
 
-		ctx.Info("created new service")
-		sc.storageServices[serviceName] = storSvc
+	if err := storSvc.Init(ctx, config.Scope(scope)); err != nil {
+		return nil, err
 	}
+	storSvc.settings, _ = config.Get(scope).(map[string]interface{})
 
-	return nil
+	ctx.Info("created new service")
+	return storSvc, nil
 }
 
 func getTaskService(ctx types.Context) *globalTaskService {
@@ -186,6 +232,18 @@ func TaskInspect(ctx types.Context, taskID int) *types.Task {
 	return getTaskService(ctx).TaskInspect(taskID)
 }
 
+// TaskCancel cancels the task with the specified ID.
+func TaskCancel(ctx types.Context, taskID int) (*types.Task, error) {
+	return getTaskService(ctx).TaskCancel(taskID)
+}
+
+// TaskStorageService returns the storage service for which the task with the
+// specified ID was created. A nil value is returned if the task was not
+// created for a service or is no longer tracked.
+func TaskStorageService(ctx types.Context, taskID int) types.StorageService {
+	return getTaskService(ctx).taskStorageService(taskID)
+}
+
 // TaskWait blocks until the specified task is completed.
 func TaskWait(ctx types.Context, taskID int) {
 	getTaskService(ctx).TaskWait(taskID)
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"sync"
+
+	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// auditLog writes the server's audit records to the configured sink and
+// retains the most recent records so they may be queried.
+type auditLog struct {
+	sync.RWMutex
+	sink   types.AuditSink
+	recent []*types.AuditRecord
+	max    int
+}
+
+func (a *auditLog) Init(ctx types.Context, config gofig.Config) error {
+	if config.GetBool(types.ConfigServerAuditDisabled) {
+		ctx.Info("audit log disabled")
+		return nil
+	}
+
+	a.max = config.GetInt(types.ConfigServerAuditRecent)
+
+	sinkType := config.GetString(types.ConfigServerAuditSinkType)
+	sink, err := registry.NewAuditSink(sinkType)
+	if err != nil {
+		return err
+	}
+	if err := sink.Init(ctx, config); err != nil {
+		return err
+	}
+	a.sink = sink
+
+	if r, ok := sink.(types.AuditReader); ok && a.max > 0 {
+		if a.recent, err = r.Recent(a.max); err != nil {
+			return err
+		}
+	}
+
+	ctx.WithField("sink", sink.Name()).Info("initialized audit log")
+	return nil
+}
+
+func (a *auditLog) write(ctx types.Context, rec *types.AuditRecord) {
+	if a.sink == nil {
+		return
+	}
+
+	func() {
+		a.Lock()
+		defer a.Unlock()
+		if a.max <= 0 {
+			return
+		}
+		a.recent = append(a.recent, rec)
+		if n := len(a.recent) - a.max; n > 0 {
+			a.recent = append(a.recent[:0], a.recent[n:]...)
+		}
+	}()
+
+	if err := a.sink.Write(ctx, rec); err != nil {
+		ctx.WithError(err).Error("error writing audit record")
+	}
+}
+
+func (a *auditLog) query(q *types.AuditQuery) []*types.AuditRecord {
+	a.RLock()
+	defer a.RUnlock()
+
+	records := []*types.AuditRecord{}
+	for i := len(a.recent) - 1; i >= 0; i-- {
+		if q.Limit > 0 && len(records) >= q.Limit {
+			break
+		}
+		if q.Match(a.recent[i]) {
+			records = append(records, a.recent[i])
+		}
+	}
+	return records
+}
+
+func getAuditLog(ctx types.Context) *auditLog {
+
+	serverName, ok := context.Server(ctx)
+	if !ok {
+		panic("ctx is missing ServerName")
+	}
+
+	servicesByServerRWL.RLock()
+	defer servicesByServerRWL.RUnlock()
+
+	return servicesByServer[serverName].auditLog
+}
+
+// AuditWrite writes the record to the audit log.
+func AuditWrite(ctx types.Context, rec *types.AuditRecord) {
+	getAuditLog(ctx).write(ctx, rec)
+}
+
+// AuditQuery returns the recent audit records that match the query, newest
+// first.
+func AuditQuery(ctx types.Context, q *types.AuditQuery) []*types.AuditRecord {
+	return getAuditLog(ctx).query(q)
+}
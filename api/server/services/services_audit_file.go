@@ -0,0 +1,229 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+const fileAuditSinkName = "file"
+
+func init() {
+	registry.RegisterAuditSink(fileAuditSinkName, newFileAuditSink)
+}
+
+// fileAuditSink is an audit sink that appends each record as a line of JSON
+// to a file. The file is rotated once it reaches its maximum size.
+type fileAuditSink struct {
+	sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	f          *os.File
+	size       int64
+}
+
+func newFileAuditSink() types.AuditSink {
+	return &fileAuditSink{}
+}
+
+func (s *fileAuditSink) Name() string {
+	return fileAuditSinkName
+}
+
+func (s *fileAuditSink) Init(ctx types.Context, config gofig.Config) error {
+	s.path = config.GetString(types.ConfigServerAuditSinkPath)
+	if s.path == "" {
+		return goof.New("audit sink path is required")
+	}
+	s.maxSize = int64(config.GetInt(types.ConfigServerAuditSinkMaxSize)) *
+		1024 * 1024
+	s.maxBackups = config.GetInt(types.ConfigServerAuditSinkMaxBackups)
+
+	if err := os.MkdirAll(path.Dir(s.path), 0755); err != nil {
+		return goof.WithFieldE(
+			"path", s.path, "error creating audit log dir", err)
+	}
+	if err := s.open(); err != nil {
+		return err
+	}
+
+	ctx.WithFields(log.Fields{
+		"path":       s.path,
+		"maxSize":    s.maxSize,
+		"maxBackups": s.maxBackups,
+	}).Debug("initialized file audit sink")
+
+	return nil
+}
+
+// open opens the audit log for appending. The caller must hold the sink's
+// lock.
+func (s *fileAuditSink) open() error {
+	f, err := os.OpenFile(
+		s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return goof.WithFieldE("path", s.path, "error opening audit log", err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return goof.WithFieldE("path", s.path, "error opening audit log", err)
+	}
+	s.f = f
+	s.size = fi.Size()
+	return nil
+}
+
+// rotate renames the audit log to the first backup, shifting the existing
+// backups and discarding the oldest, and opens a new audit log. If the audit
+// log cannot be rotated it is reopened so that records continue to be
+// appended to it. The caller must hold the sink's lock.
+func (s *fileAuditSink) rotate() error {
+	f := s.f
+	s.f = nil
+	if err := f.Close(); err != nil {
+		if oerr := s.open(); oerr != nil {
+			return oerr
+		}
+		return goof.WithFieldE("path", s.path, "error closing audit log", err)
+	}
+	if err := s.shiftBackups(); err != nil {
+		if oerr := s.open(); oerr != nil {
+			return oerr
+		}
+		return err
+	}
+	return s.open()
+}
+
+// shiftBackups renames the closed audit log to the first backup, shifting
+// the existing backups and discarding the oldest. The audit log is removed
+// if no backups are kept.
+func (s *fileAuditSink) shiftBackups() error {
+	if s.maxBackups <= 0 {
+		if err := os.Remove(s.path); err != nil {
+			return goof.WithFieldE(
+				"path", s.path, "error rotating audit log", err)
+		}
+		return nil
+	}
+	oldest := s.backupPath(s.maxBackups)
+	if err := os.Remove(oldest); err != nil && !os.IsNotExist(err) {
+		return goof.WithFieldE(
+			"path", oldest, "error removing audit log backup", err)
+	}
+	for i := s.maxBackups - 1; i > 0; i-- {
+		err := os.Rename(s.backupPath(i), s.backupPath(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return goof.WithFieldE(
+				"path", s.backupPath(i), "error shifting audit log backup", err)
+		}
+	}
+	if err := os.Rename(s.path, s.backupPath(1)); err != nil {
+		return goof.WithFieldE("path", s.path, "error rotating audit log", err)
+	}
+	return nil
+}
+
+func (s *fileAuditSink) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", s.path, i)
+}
+
+func (s *fileAuditSink) Write(
+	ctx types.Context, record *types.AuditRecord) error {
+
+	buf, err := json.Marshal(record)
+	if err != nil {
+		return goof.WithError("error marshaling audit record", err)
+	}
+	buf = append(buf, '\n')
+
+	s.Lock()
+	defer s.Unlock()
+
+	// the audit log is reopened if it could not be reopened after a failed
+	// rotation
+	if s.f == nil {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+
+	// a record is appended to the existing audit log rather than lost when
+	// the log cannot be rotated
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(buf)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			ctx.WithError(err).Error("error rotating audit log")
+			if s.f == nil {
+				return err
+			}
+		}
+	}
+
+	n, err := s.f.Write(buf)
+	s.size += int64(n)
+	if err != nil {
+		return goof.WithFieldE("path", s.path, "error writing audit log", err)
+	}
+	return nil
+}
+
+// Recent reads the most recent records from the audit log and, if there
+// are not enough, its backups.
+func (s *fileAuditSink) Recent(n int) ([]*types.AuditRecord, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	records := []*types.AuditRecord{}
+	for i := 0; i <= s.maxBackups && len(records) < n; i++ {
+		p := s.path
+		if i > 0 {
+			p = s.backupPath(i)
+		}
+		fileRecords, err := readAuditFile(p)
+		if err != nil {
+			if os.IsNotExist(err) {
+				break
+			}
+			return nil, goof.WithFieldE(
+				"path", p, "error reading audit log", err)
+		}
+		records = append(fileRecords, records...)
+	}
+
+	if len(records) > n {
+		records = records[len(records)-n:]
+	}
+	return records, nil
+}
+
+func readAuditFile(filePath string) ([]*types.AuditRecord, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records := []*types.AuditRecord{}
+	scn := bufio.NewScanner(f)
+	for scn.Scan() {
+		rec := &types.AuditRecord{}
+		// a partially written record is skipped
+		if err := json.Unmarshal(scn.Bytes(), rec); err != nil {
+			continue
+		}
+		records = append(records, rec)
+	}
+	return records, scn.Err()
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func newTestFileAuditSink(
+	t *testing.T, maxSize int64, maxBackups int) (*fileAuditSink, string) {
+
+	dir, err := ioutil.TempDir("", "libstorage-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fileAuditSink{
+		path:       path.Join(dir, "audit.log"),
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := s.open(); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return s, dir
+}
+
+func writeTestAuditRecords(t *testing.T, s *fileAuditSink, routes ...string) {
+	ctx := context.Background()
+	for _, r := range routes {
+		assert.NoError(t, s.Write(ctx, &types.AuditRecord{Route: r}))
+	}
+}
+
+func auditRoutes(records []*types.AuditRecord) []string {
+	routes := []string{}
+	for _, r := range records {
+		routes = append(routes, r.Route)
+	}
+	return routes
+}
+
+func TestFileAuditSinkRotate(t *testing.T) {
+	// each record is larger than half of the maximum size so that each
+	// record is written to a new log
+	s, dir := newTestFileAuditSink(t, 100, 2)
+	defer os.RemoveAll(dir)
+
+	writeTestAuditRecords(t, s, "a", "b", "c", "d")
+
+	records, err := s.Recent(10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "c", "d"}, auditRoutes(records))
+
+	records, err = s.Recent(2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c", "d"}, auditRoutes(records))
+
+	_, err = os.Stat(s.backupPath(3))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFileAuditSinkRotateError(t *testing.T) {
+	s, dir := newTestFileAuditSink(t, 100, 1)
+	defer os.RemoveAll(dir)
+
+	// the oldest backup cannot be removed to make room for the audit log
+	oldest := path.Join(s.backupPath(1), "audit.log")
+	if err := os.MkdirAll(oldest, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// so the records continue to be appended to the audit log
+	writeTestAuditRecords(t, s, "a", "b", "c")
+
+	records, err := readAuditFile(s.path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, auditRoutes(records))
+
+	// and the audit log is rotated once the backup can be removed
+	assert.NoError(t, os.RemoveAll(s.backupPath(1)))
+	writeTestAuditRecords(t, s, "d")
+
+	records, err = s.Recent(10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, auditRoutes(records))
+	records, err = readAuditFile(s.path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"d"}, auditRoutes(records))
+}
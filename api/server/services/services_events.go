@@ -0,0 +1,132 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// eventSubBufferSize is the number of events buffered for a subscriber.
+// Events published to a subscriber with a full buffer are dropped.
+const eventSubBufferSize = 100
+
+// eventBus publishes the server's events to its subscribers.
+type eventBus struct {
+	sync.RWMutex
+	nextEventID int64
+	nextSubID   int
+	subs        map[int]*eventSub
+}
+
+type eventSub struct {
+	filter *types.EventFilter
+	c      chan *types.Event
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subs: map[int]*eventSub{}}
+}
+
+func (b *eventBus) publish(ctx types.Context, ev *types.Event) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.nextEventID++
+	ev.ID = b.nextEventID
+
+	for _, s := range b.subs {
+		if s.filter != nil && !s.filter.Match(ev) {
+			continue
+		}
+		select {
+		case s.c <- ev:
+		default:
+			ctx.WithField("eventID", ev.ID).Warn(
+				"dropped event for slow subscriber")
+		}
+	}
+}
+
+func (b *eventBus) subscribe(
+	filter *types.EventFilter) (<-chan *types.Event, func()) {
+
+	b.Lock()
+	defer b.Unlock()
+
+	b.nextSubID++
+	id := b.nextSubID
+	s := &eventSub{
+		filter: filter,
+		c:      make(chan *types.Event, eventSubBufferSize),
+	}
+	b.subs[id] = s
+
+	once := &sync.Once{}
+	return s.c, func() {
+		once.Do(func() {
+			b.Lock()
+			defer b.Unlock()
+			delete(b.subs, id)
+			close(s.c)
+		})
+	}
+}
+
+// getEventServices returns the service container of the server in the
+// context or nil if the server's services have not been initialized.
+func getEventServices(ctx types.Context) *serviceContainer {
+
+	serverName, ok := context.Server(ctx)
+	if !ok {
+		return nil
+	}
+
+	servicesByServerRWL.RLock()
+	defer servicesByServerRWL.RUnlock()
+
+	return servicesByServer[serverName]
+}
+
+// EventPublish publishes the event to the subscribers and webhooks of the
+// server in the context. The event's time, service, subject, and
+// transaction ID are set from the context if they are empty.
+func EventPublish(ctx types.Context, ev *types.Event) {
+	sc := getEventServices(ctx)
+	if sc == nil {
+		return
+	}
+
+	if ev.Time == 0 {
+		ev.Time = time.Now().Unix()
+	}
+	if ev.Service == "" {
+		if svc, ok := context.Service(ctx); ok {
+			ev.Service = svc.Name()
+		}
+	}
+	if ev.Subject == "" {
+		if tok, ok := context.AuthToken(ctx); ok {
+			ev.Subject = tok.Subject
+		}
+	}
+	if ev.TransactionID == "" {
+		if tx, ok := context.Transaction(ctx); ok && tx.ID != nil {
+			ev.TransactionID = tx.ID.String()
+		}
+	}
+
+	sc.eventBus.publish(ctx, ev)
+	sc.webhooks.notify(ctx, ev)
+}
+
+// EventSubscribe subscribes to the events of the server in the context that
+// match the filter. The returned function ends the subscription and closes
+// the channel.
+func EventSubscribe(
+	ctx types.Context,
+	filter *types.EventFilter) (<-chan *types.Event, func()) {
+
+	return getEventServices(ctx).eventBus.subscribe(filter)
+}
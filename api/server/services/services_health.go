@@ -0,0 +1,163 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+// serviceHealth retains the result of the most recent probe of a storage
+// service and the most recent error returned by probing it.
+type serviceHealth struct {
+	sync.Mutex
+	lastError     string
+	lastErrorTime int64
+
+	// probeLk serializes the probes of the service so that concurrent
+	// requests share the result of a single probe
+	probeLk   sync.Mutex
+	last      *types.ServiceHealth
+	lastProbe time.Time
+}
+
+// isCritical returns a flag indicating whether the server is not ready when
+// the service is down.
+func (s *storageService) isCritical() bool {
+	if s.config.IsSet("health.critical") {
+		return s.config.GetBool("health.critical")
+	}
+	return s.config.GetBool(types.ConfigServerHealthCritical)
+}
+
+// probe returns the result of probing the service. The service is probed
+// with its driver's HealthCheck function if the driver implements
+// types.StorageDriverWithHealthCheck; otherwise the service's volumes are
+// listed without their attachments. The result is reused by the probes
+// that occur within the timeout of the probe that produced it.
+func (s *storageService) probe(
+	ctx types.Context, timeout time.Duration) *types.ServiceHealth {
+
+	s.health.probeLk.Lock()
+	defer s.health.probeLk.Unlock()
+
+	if s.health.last != nil && time.Since(s.health.lastProbe) < timeout {
+		health := *s.health.last
+		return &health
+	}
+
+	// the driver is given a context that is cancelled once the probe times
+	// out so that a driver that honors it does not outlive the probe
+	start := time.Now()
+	pctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	errC := make(chan error, 1)
+	go func() {
+		errC <- s.probeDriver(pctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errC:
+	case <-pctx.Done():
+		err = types.ErrTimedOut
+	}
+
+	health := &types.ServiceHealth{
+		Status:   types.HealthStatusUp,
+		Critical: s.isCritical(),
+		Latency:  int64(time.Since(start) / time.Millisecond),
+	}
+
+	s.health.Lock()
+	defer s.health.Unlock()
+
+	if err != nil {
+		health.Status = types.HealthStatusDown
+		s.health.lastError = err.Error()
+		s.health.lastErrorTime = start.Unix()
+		ctx.WithError(err).WithField(
+			"service", s.name).Warn("storage service probe failed")
+	}
+	health.LastError = s.health.lastError
+	health.LastErrorTime = s.health.lastErrorTime
+
+	last := *health
+	s.health.last = &last
+	s.health.lastProbe = time.Now()
+
+	return health
+}
+
+func (s *storageService) probeDriver(ctx types.Context) error {
+	ctx = context.WithStorageService(ctx, s)
+
+	var err error
+	if ctx, err = context.WithStorageSession(ctx); err != nil {
+		return err
+	}
+
+	d := registry.UnwrapStorageDriver(s.driver)
+	if hc, ok := d.(types.StorageDriverWithHealthCheck); ok {
+		return hc.HealthCheck(ctx)
+	}
+
+	_, err = s.driver.Volumes(ctx, &types.VolumesOpts{Opts: utils.NewStore()})
+	return err
+}
+
+// Ready probes all of the storage services concurrently and returns the
+// results. The status of the result is down if any of the critical services
+// are down.
+func Ready(ctx types.Context) *types.Readiness {
+
+	serverName, ok := context.Server(ctx)
+	if !ok {
+		panic("ctx is missing ServerName")
+	}
+
+	servicesByServerRWL.RLock()
+	config := servicesByServer[serverName].config
+	storServices := getStorageServices(ctx)
+	servicesByServerRWL.RUnlock()
+
+	timeout, err := time.ParseDuration(
+		config.GetString(types.ConfigServerHealthTimeout))
+	if err != nil {
+		timeout = time.Duration(10) * time.Second
+	}
+
+	var (
+		wg    = &sync.WaitGroup{}
+		resLk = &sync.Mutex{}
+		res   = &types.Readiness{
+			Status:   types.HealthStatusUp,
+			Services: map[string]*types.ServiceHealth{},
+		}
+	)
+
+	for name, svc := range storServices {
+		s, ok := svc.(*storageService)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(name string, s *storageService) {
+			defer wg.Done()
+			health := s.probe(ctx, timeout)
+			resLk.Lock()
+			defer resLk.Unlock()
+			res.Services[name] = health
+			if health.Critical && health.Status == types.HealthStatusDown {
+				res.Status = types.HealthStatusDown
+			}
+		}(name, s)
+	}
+
+	wg.Wait()
+	return res
+}
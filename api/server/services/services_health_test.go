@@ -0,0 +1,132 @@
+package services
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gofigCore "github.com/akutz/gofig"
+	"github.com/akutz/goof"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+type testHealthDriver struct {
+	types.StorageDriver
+	check func(ctx types.Context) error
+	calls int32
+}
+
+func (d *testHealthDriver) Name() string {
+	return "test"
+}
+
+func (d *testHealthDriver) HealthCheck(ctx types.Context) error {
+	atomic.AddInt32(&d.calls, 1)
+	return d.check(ctx)
+}
+
+func newTestHealthService(
+	name string, critical bool, d *testHealthDriver) *storageService {
+
+	config := gofigCore.New()
+	config.Set("health.critical", critical)
+	return &storageService{name: name, driver: d, config: config}
+}
+
+// withTestHealthServices returns a context for a server with the given
+// services and the given probe timeout. The returned function removes the
+// server's services.
+func withTestHealthServices(
+	serverName, timeout string,
+	svcs ...*storageService) (types.Context, func()) {
+
+	config := gofigCore.New()
+	config.Set(types.ConfigServerHealthTimeout, timeout)
+	sc := &serviceContainer{
+		config:          config,
+		storageServices: map[string]types.StorageService{},
+	}
+	for _, s := range svcs {
+		sc.storageServices[s.name] = s
+	}
+
+	servicesByServerRWL.Lock()
+	servicesByServer[serverName] = sc
+	servicesByServerRWL.Unlock()
+
+	ctx := context.Background().WithValue(context.ServerKey, serverName)
+	return ctx, func() {
+		servicesByServerRWL.Lock()
+		delete(servicesByServer, serverName)
+		servicesByServerRWL.Unlock()
+	}
+}
+
+func TestReadyCriticalServiceDown(t *testing.T) {
+	up := func(ctx types.Context) error { return nil }
+	down := func(ctx types.Context) error { return goof.New("offline") }
+
+	ctx, remove := withTestHealthServices(
+		"test-ready-critical", "10s",
+		newTestHealthService("up", true, &testHealthDriver{check: up}),
+		newTestHealthService("down", true, &testHealthDriver{check: down}))
+	defer remove()
+
+	res := Ready(ctx)
+	assert.Equal(t, types.HealthStatusDown, res.Status)
+	if assert.Len(t, res.Services, 2) {
+		assert.Equal(t, types.HealthStatusUp, res.Services["up"].Status)
+		assert.Equal(t, types.HealthStatusDown, res.Services["down"].Status)
+		assert.Equal(t, "offline", res.Services["down"].LastError)
+		assert.True(t, res.Services["down"].Critical)
+	}
+
+	// the server is ready while only services that are not critical are down
+	ctx, remove = withTestHealthServices(
+		"test-ready-not-critical", "10s",
+		newTestHealthService("down", false, &testHealthDriver{check: down}))
+	defer remove()
+
+	res = Ready(ctx)
+	assert.Equal(t, types.HealthStatusUp, res.Status)
+	assert.Equal(t, types.HealthStatusDown, res.Services["down"].Status)
+}
+
+func TestReadyTimeout(t *testing.T) {
+	cancelled := make(chan bool)
+	d := &testHealthDriver{check: func(ctx types.Context) error {
+		<-ctx.Done()
+		cancelled <- true
+		return ctx.Err()
+	}}
+
+	ctx, remove := withTestHealthServices(
+		"test-ready-timeout", "100ms", newTestHealthService("hung", true, d))
+	defer remove()
+
+	// the probe of a service that does not reply is failed once it times out
+	res := Ready(ctx)
+	assert.Equal(t, types.HealthStatusDown, res.Status)
+	assert.Equal(t, types.ErrTimedOut.Error(), res.Services["hung"].LastError)
+
+	// and the driver's context is cancelled so its check does not leak
+	select {
+	case <-cancelled:
+	case <-time.After(time.Duration(5) * time.Second):
+		t.Fatal("timed out waiting for probe to be cancelled")
+	}
+
+	// the result is reused by the requests within the probe's timeout
+	res = Ready(ctx)
+	assert.Equal(t, types.HealthStatusDown, res.Status)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&d.calls))
+
+	// and the service is probed again after that
+	time.Sleep(time.Duration(100) * time.Millisecond)
+	go func() { <-cancelled }()
+	Ready(ctx)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&d.calls))
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+// servicesManageLock serializes the changes made to a server's storage
+// services at runtime, whether by a reload or the services API.
+var servicesManageLock = &sync.Mutex{}
+
+// AddStorageService initializes a new storage service with the given
+// configuration and adds it to the server's storage services. The
+// configuration is the service's section of the server configuration, ex.
+// {"driver": "vfs"}.
+func AddStorageService(
+	ctx types.Context,
+	name string,
+	settings map[string]interface{}) (types.StorageService, error) {
+
+	servicesManageLock.Lock()
+	defer servicesManageLock.Unlock()
+
+	name = strings.ToLower(name)
+	if GetStorageService(ctx, name) != nil {
+		return nil, utils.NewServiceExistsError(name)
+	}
+	return setStorageService(ctx, name, settings)
+}
+
+// UpdateStorageService merges the given configuration into that of the
+// named storage service and initializes the service again. The running
+// service is replaced only if the reconfigured service is initialized.
+func UpdateStorageService(
+	ctx types.Context,
+	name string,
+	settings map[string]interface{}) (types.StorageService, error) {
+
+	servicesManageLock.Lock()
+	defer servicesManageLock.Unlock()
+
+	name = strings.ToLower(name)
+	svc, ok := GetStorageService(ctx, name).(*storageService)
+	if !ok {
+		return nil, utils.NewNotFoundError(name)
+	}
+
+	merged := map[string]interface{}{}
+	for k, v := range svc.settings {
+		merged[k] = v
+	}
+	for k, v := range settings {
+		merged[k] = v
+	}
+	return setStorageService(ctx, name, merged)
+}
+
+// RemoveStorageService removes the named storage service from the server's
+// storage services and drains it. The service stops accepting requests and
+// tasks when it is removed, and the function returns once the tasks that
+// were already queued or running for it are completed.
+func RemoveStorageService(ctx types.Context, name string) error {
+
+	servicesManageLock.Lock()
+
+	name = strings.ToLower(name)
+	svc, ok := GetStorageService(ctx, name).(*storageService)
+	if !ok {
+		servicesManageLock.Unlock()
+		return utils.NewNotFoundError(name)
+	}
+
+	serverName, _ := context.Server(ctx)
+
+	servicesByServerRWL.Lock()
+	sc := servicesByServer[serverName]
+	storServices := map[string]types.StorageService{}
+	for k, v := range sc.storageServices {
+		if k != name {
+			storServices[k] = v
+		}
+	}
+	sc.storageServices = storServices
+	servicesByServerRWL.Unlock()
+
+	svc.stopTaskWorkers()
+	servicesManageLock.Unlock()
+
+	ctx.Info("draining service")
+	svc.waitTaskWorkers()
+	ctx.Info("removed service")
+	return nil
+}
+
+// setStorageService initializes a storage service with the given
+// configuration and adds it to the server's storage services, replacing
+// the service with the same name if one exists.
+func setStorageService(
+	ctx types.Context,
+	name string,
+	settings map[string]interface{}) (types.StorageService, error) {
+
+	serverName, ok := context.Server(ctx)
+	if !ok {
+		panic("ctx is missing ServerName")
+	}
+
+	servicesByServerRWL.RLock()
+	sc := servicesByServer[serverName]
+	oldConfig := sc.config
+	servicesByServerRWL.RUnlock()
+
+	config, err := oldConfig.Copy()
+	if err != nil {
+		return nil, err
+	}
+	setConfigMap(
+		config, fmt.Sprintf("libstorage.server.services.%s", name), settings)
+
+	storSvc, err := newStorageService(ctx, config, name)
+	if err != nil {
+		return nil, err
+	}
+	storSvc.settings = settings
+
+	servicesByServerRWL.Lock()
+	defer servicesByServerRWL.Unlock()
+
+	// the tasks enqueued for the new service are executed once those that
+	// were already enqueued for the service it replaces are completed
+	if old, ok := sc.storageServices[name].(*storageService); ok {
+		storSvc.replaceTaskWorkers(old)
+	}
+
+	storServices := map[string]types.StorageService{name: storSvc}
+	for k, v := range sc.storageServices {
+		if k != name {
+			storServices[k] = v
+		}
+	}
+	sc.config = config
+	sc.storageServices = storServices
+
+	return storSvc, nil
+}
+
+// setConfigMap sets each of the leaf values of the map m as a key of the
+// config prefixed with the given key.
+func setConfigMap(
+	config gofig.Config, key string, m map[string]interface{}) {
+
+	for k, v := range m {
+		k = fmt.Sprintf("%s.%s", key, k)
+		if vm, ok := v.(map[string]interface{}); ok {
+			setConfigMap(config, k, vm)
+			continue
+		}
+		config.Set(k, v)
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils/metrics"
+)
+
+func init() {
+	metrics.NewGaugeFunc(
+		"libstorage_tasks",
+		"The number of tasks tracked by the task service by state.",
+		collectTaskStates, "state")
+
+	metrics.NewGaugeFunc(
+		"libstorage_task_queue_depth",
+		"The number of tasks waiting to be executed by service.",
+		collectTaskQueueDepths, "service")
+}
+
+// taskStates are the states reported by the libstorage_tasks gauge. They are
+// reported even when there are no tasks in the state.
+var taskStates = []types.TaskState{
+	types.TaskStateQueued,
+	types.TaskStateRunning,
+	types.TaskStateSuccess,
+	types.TaskStateError,
+	types.TaskStateCancelled,
+}
+
+func collectTaskStates(emit func(float64, ...string)) {
+	for _, state := range taskStates {
+		emit(0, string(state))
+	}
+	forEachTask(func(t *task) {
+		emit(1, string(t.State))
+	})
+}
+
+func collectTaskQueueDepths(emit func(float64, ...string)) {
+	servicesByServerRWL.RLock()
+	for _, sc := range servicesByServer {
+		for name := range sc.storageServices {
+			emit(0, name)
+		}
+	}
+	servicesByServerRWL.RUnlock()
+
+	forEachTask(func(t *task) {
+		if t.State == types.TaskStateQueued && t.storService != nil {
+			emit(1, t.storService.Name())
+		}
+	})
+}
+
+// forEachTask invokes f with each of the tasks tracked by the task services
+// of all the servers. Each task is locked while f is invoked.
+func forEachTask(f func(t *task)) {
+	servicesByServerRWL.RLock()
+	taskServices := make([]*globalTaskService, 0, len(servicesByServer))
+	for _, sc := range servicesByServer {
+		taskServices = append(taskServices, sc.taskService)
+	}
+	servicesByServerRWL.RUnlock()
+
+	for _, s := range taskServices {
+		s.RLock()
+		tasks := make([]*task, 0, len(s.tasks))
+		for _, t := range s.tasks {
+			tasks = append(tasks, t)
+		}
+		s.RUnlock()
+
+		for _, t := range tasks {
+			t.Lock()
+			f(t)
+			t.Unlock()
+		}
+	}
+}
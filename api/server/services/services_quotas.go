@@ -0,0 +1,422 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+// quotaLedger records the volumes created by each auth subject so that the
+// subjects' usage can be measured against their quotas. The ledger is
+// persisted to disk when a path is configured.
+type quotaLedger struct {
+	sync.Mutex
+	config gofig.Config
+	path   string
+
+	// records maps service names to the volumes, keyed by volume ID, that
+	// were created with the service.
+	records map[string]map[string]*quotaRecord
+
+	// pending are the reservations for the volumes that are being created.
+	pending map[*quotaReservation]bool
+}
+
+// quotaRecord is a volume in the quota ledger.
+type quotaRecord struct {
+	Subject string `json:"subject"`
+	Size    int64  `json:"size"`
+}
+
+// quotaReservation is the space reserved against a subject's quota while a
+// volume is created or resized.
+type quotaReservation struct {
+	ledger  *quotaLedger
+	service string
+	subject string
+	size    int64
+
+	// volumeID is the ID of the volume that is resized. It is empty when a
+	// new volume is created.
+	volumeID string
+}
+
+func (l *quotaLedger) Init(ctx types.Context, config gofig.Config) error {
+	l.config = config
+	l.path = config.GetString(types.ConfigServerQuotasPath)
+	l.records = map[string]map[string]*quotaRecord{}
+	l.pending = map[*quotaReservation]bool{}
+
+	if l.path == "" {
+		return nil
+	}
+
+	buf, err := ioutil.ReadFile(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return goof.WithFieldE(
+			"path", l.path, "error reading quota ledger", err)
+	}
+	if err := json.Unmarshal(buf, &l.records); err != nil {
+		return goof.WithFieldE(
+			"path", l.path, "error unmarshaling quota ledger", err)
+	}
+
+	ctx.WithField("path", l.path).Info("loaded quota ledger")
+	return nil
+}
+
+// save persists the ledger. The caller must hold the ledger's lock.
+func (l *quotaLedger) save() error {
+	if l.path == "" {
+		return nil
+	}
+	buf, err := json.Marshal(l.records)
+	if err != nil {
+		return goof.WithError("error marshaling quota ledger", err)
+	}
+	if err := os.MkdirAll(path.Dir(l.path), 0755); err != nil {
+		return goof.WithFieldE(
+			"path", l.path, "error creating quota ledger dir", err)
+	}
+	return utils.WriteFileAtomic(l.path, buf)
+}
+
+// usage returns the subject's usage of the service, including the volumes
+// that are being created. The caller must hold the ledger's lock.
+func (l *quotaLedger) usage(service, subject string) *types.QuotaUsage {
+	u := &types.QuotaUsage{}
+	for _, r := range l.records[service] {
+		if r.Subject == subject {
+			u.Volumes++
+			u.Size += r.Size
+		}
+	}
+	for r := range l.pending {
+		if r.service == service && r.subject == subject {
+			if r.volumeID == "" {
+				u.Volumes++
+			}
+			u.Size += r.size
+		}
+	}
+	return u
+}
+
+// limits returns the subject's quota limits for the service. A service's
+// limits take precedence over the server's, and a subject's limits take
+// precedence over the default limits at the same level.
+func (l *quotaLedger) limits(service, subject string) *types.QuotaLimits {
+	return &types.QuotaLimits{
+		Volumes:       l.limit(service, subject, "volumes"),
+		Size:          l.limit(service, subject, "size"),
+		MaxVolumeSize: l.limit(service, subject, "maxVolumeSize"),
+	}
+}
+
+func (l *quotaLedger) limit(service, subject, name string) int64 {
+	svcQuotas := fmt.Sprintf("libstorage.server.services.%s.quotas", service)
+	keys := []string{}
+	if subject != "" {
+		keys = append(keys, fmt.Sprintf(
+			"%s.subjects.%s.%s", svcQuotas, subject, name))
+	}
+	keys = append(keys, fmt.Sprintf("%s.%s", svcQuotas, name))
+	if subject != "" {
+		keys = append(keys, fmt.Sprintf(
+			"%s.%s.%s", types.ConfigServerQuotasSubjects, subject, name))
+	}
+	keys = append(keys, fmt.Sprintf("%s.%s", types.ConfigServerQuotas, name))
+
+	for _, k := range keys {
+		if l.config.IsSet(k) {
+			return int64(l.config.GetInt(k))
+		}
+	}
+	return 0
+}
+
+// subjects returns the names of the subjects that have volumes in the ledger
+// or that have limits configured for the service. The caller must hold the
+// ledger's lock.
+func (l *quotaLedger) subjects(service string) []string {
+	names := map[string]bool{}
+	for _, r := range l.records[service] {
+		names[r.Subject] = true
+	}
+	for r := range l.pending {
+		if r.service == service {
+			names[r.subject] = true
+		}
+	}
+	for _, k := range []string{
+		fmt.Sprintf("libstorage.server.services.%s.quotas.subjects", service),
+		types.ConfigServerQuotasSubjects,
+	} {
+		if m, ok := l.config.Get(k).(map[string]interface{}); ok {
+			for name := range m {
+				names[name] = true
+			}
+		}
+	}
+	subjects := []string{}
+	for name := range names {
+		subjects = append(subjects, name)
+	}
+	return subjects
+}
+
+func (r *quotaReservation) Commit(ctx types.Context, v *types.Volume) error {
+	l := r.ledger
+	l.Lock()
+	defer l.Unlock()
+
+	if !l.pending[r] {
+		return nil
+	}
+	delete(l.pending, r)
+
+	if r.volumeID != "" {
+		return r.commitResize(ctx, v)
+	}
+
+	size := v.Size
+	if size <= 0 {
+		size = r.size
+	}
+	if _, ok := l.records[r.service]; !ok {
+		l.records[r.service] = map[string]*quotaRecord{}
+	}
+	l.records[r.service][v.ID] = &quotaRecord{Subject: r.subject, Size: size}
+
+	ctx.WithFields(map[string]interface{}{
+		"service":  r.service,
+		"subject":  r.subject,
+		"volumeID": v.ID,
+		"size":     size,
+	}).Debug("recorded volume in quota ledger")
+
+	return l.save()
+}
+
+// commitResize records the new size of a resized volume. The caller must hold
+// the ledger's lock.
+func (r *quotaReservation) commitResize(
+	ctx types.Context, v *types.Volume) error {
+
+	l := r.ledger
+	rec, ok := l.records[r.service][r.volumeID]
+	if !ok || v.Size <= 0 {
+		return nil
+	}
+	rec.Size = v.Size
+
+	ctx.WithFields(map[string]interface{}{
+		"service":  r.service,
+		"subject":  rec.Subject,
+		"volumeID": r.volumeID,
+		"size":     rec.Size,
+	}).Debug("recorded resized volume in quota ledger")
+
+	return l.save()
+}
+
+func (r *quotaReservation) Cancel() {
+	r.ledger.Lock()
+	defer r.ledger.Unlock()
+	delete(r.ledger.pending, r)
+}
+
+func getQuotaLedger(ctx types.Context) *quotaLedger {
+
+	serverName, ok := context.Server(ctx)
+	if !ok {
+		panic("ctx is missing ServerName")
+	}
+
+	servicesByServerRWL.RLock()
+	defer servicesByServerRWL.RUnlock()
+
+	return servicesByServer[serverName].quotaLedger
+}
+
+// QuotaReserve reserves the space for a new volume of the specified size
+// against the subject's quota for the service. An ErrQuotaExceeded error is
+// returned if the volume would cause the subject to exceed the quota.
+func QuotaReserve(
+	ctx types.Context,
+	service, subject string,
+	size int64) (types.QuotaReservation, error) {
+
+	l := getQuotaLedger(ctx)
+	service = strings.ToLower(service)
+	limits := l.limits(service, subject)
+
+	l.Lock()
+	defer l.Unlock()
+
+	u := l.usage(service, subject)
+
+	// the size of a volume created without one is chosen by the driver and
+	// is unknown until the volume exists, so it cannot be checked against
+	// the size limits
+	if size <= 0 {
+		if limits.MaxVolumeSize > 0 {
+			return nil, utils.NewQuotaSizeRequiredError(
+				service, subject, "maxVolumeSize")
+		}
+		if limits.Size > 0 {
+			return nil, utils.NewQuotaSizeRequiredError(
+				service, subject, "size")
+		}
+	}
+
+	if limits.MaxVolumeSize > 0 && size > limits.MaxVolumeSize {
+		return nil, utils.NewQuotaExceededError(
+			service, subject, "maxVolumeSize", limits.MaxVolumeSize, size)
+	}
+	if limits.Volumes > 0 && u.Volumes+1 > limits.Volumes {
+		return nil, utils.NewQuotaExceededError(
+			service, subject, "volumes", limits.Volumes, u.Volumes+1)
+	}
+	if limits.Size > 0 && u.Size+size > limits.Size {
+		return nil, utils.NewQuotaExceededError(
+			service, subject, "size", limits.Size, u.Size+size)
+	}
+
+	r := &quotaReservation{
+		ledger:  l,
+		service: service,
+		subject: subject,
+		size:    size,
+	}
+	l.pending[r] = true
+	return r, nil
+}
+
+// QuotaReserveResize reserves the space by which a volume of the specified
+// size grows when it is resized to the new size. The space is reserved
+// against the quota of the subject that created the volume or, if the volume
+// is not in the ledger, the specified subject. An ErrQuotaExceeded error is
+// returned if the new size would cause the subject to exceed the quota.
+func QuotaReserveResize(
+	ctx types.Context,
+	service, subject, volumeID string,
+	size, newSize int64) (types.QuotaReservation, error) {
+
+	l := getQuotaLedger(ctx)
+	service = strings.ToLower(service)
+
+	l.Lock()
+	defer l.Unlock()
+
+	if rec, ok := l.records[service][volumeID]; ok {
+		subject = rec.Subject
+		size = rec.Size
+	}
+	limits := l.limits(service, subject)
+	u := l.usage(service, subject)
+
+	growth := newSize - size
+	if growth < 0 {
+		growth = 0
+	}
+
+	if limits.MaxVolumeSize > 0 && newSize > limits.MaxVolumeSize {
+		return nil, utils.NewQuotaExceededError(
+			service, subject, "maxVolumeSize", limits.MaxVolumeSize, newSize)
+	}
+	if limits.Size > 0 && growth > 0 && u.Size+growth > limits.Size {
+		return nil, utils.NewQuotaExceededError(
+			service, subject, "size", limits.Size, u.Size+growth)
+	}
+
+	r := &quotaReservation{
+		ledger:   l,
+		service:  service,
+		subject:  subject,
+		size:     growth,
+		volumeID: volumeID,
+	}
+	l.pending[r] = true
+	return r, nil
+}
+
+// QuotaComplete completes the quota reservation in the context, if any. The
+// reservation is committed for the volume if err is nil and cancelled
+// otherwise.
+func QuotaComplete(ctx types.Context, v *types.Volume, err error) {
+	r, ok := context.QuotaReservation(ctx)
+	if !ok {
+		return
+	}
+	if err != nil || v == nil {
+		r.Cancel()
+		return
+	}
+	if err := r.Commit(ctx, v); err != nil {
+		ctx.WithError(err).Error("error recording volume in quota ledger")
+	}
+}
+
+// QuotaRelease removes the volume from the quota ledger.
+func QuotaRelease(ctx types.Context, service, volumeID string) {
+	l := getQuotaLedger(ctx)
+	service = strings.ToLower(service)
+
+	l.Lock()
+	defer l.Unlock()
+
+	if _, ok := l.records[service][volumeID]; !ok {
+		return
+	}
+	delete(l.records[service], volumeID)
+
+	if err := l.save(); err != nil {
+		ctx.WithError(err).Error("error removing volume from quota ledger")
+	}
+}
+
+// Quotas returns the quotas and usage of the subjects for all of the storage
+// services. If no subjects are specified then the quotas of all the subjects
+// that have volumes or configured limits are returned.
+func Quotas(ctx types.Context, subjects ...string) types.ServiceQuotaMap {
+	l := getQuotaLedger(ctx)
+	reply := types.ServiceQuotaMap{}
+
+	for svc := range StorageServices(ctx) {
+		service := strings.ToLower(svc.Name())
+
+		l.Lock()
+		names := subjects
+		if len(names) == 0 {
+			names = l.subjects(service)
+		}
+		quotas := types.QuotaMap{}
+		for _, subject := range names {
+			quotas[subject] = &types.Quota{
+				Subject: subject,
+				Limits:  l.limits(service, subject),
+				Usage:   l.usage(service, subject),
+			}
+		}
+		l.Unlock()
+
+		reply[svc.Name()] = quotas
+	}
+
+	return reply
+}
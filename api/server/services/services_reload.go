@@ -0,0 +1,117 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// Reload applies the given configuration to the server's storage services.
+// Services whose configuration is unchanged are left running, services that
+// are new or changed are initialized, and services that are no longer
+// configured are removed, including those added at runtime with
+// AddStorageService. The running services are replaced only once all of
+// the new and changed services are initialized, so an error leaves the
+// server's services unmodified.
+func Reload(ctx types.Context, config gofig.Config) error {
+
+	serverName, ok := context.Server(ctx)
+	if !ok {
+		panic("ctx is missing ServerName")
+	}
+
+	servicesManageLock.Lock()
+	defer servicesManageLock.Unlock()
+
+	servicesByServerRWL.RLock()
+	sc := servicesByServer[serverName]
+	oldConfig := sc.config
+	oldServices := sc.storageServices
+	servicesByServerRWL.RUnlock()
+
+	cfgSvcsMap, err := getServicesConfig(config)
+	if err != nil {
+		return err
+	}
+
+	var (
+		newServices = map[string]types.StorageService{}
+		initialized []*storageService
+	)
+
+	for serviceName := range cfgSvcsMap {
+		serviceName = strings.ToLower(serviceName)
+
+		if svc, ok := oldServices[serviceName]; ok &&
+			!serviceConfigChanged(oldConfig, config, svc) {
+			ctx.WithField("service", serviceName).Debug(
+				"service config unchanged")
+			newServices[serviceName] = svc
+			continue
+		}
+
+		storSvc, err := newStorageService(ctx, config, serviceName)
+		if err != nil {
+			for _, s := range initialized {
+				s.stopTaskWorkers()
+			}
+			return err
+		}
+		initialized = append(initialized, storSvc)
+		newServices[serviceName] = storSvc
+	}
+
+	servicesByServerRWL.Lock()
+	defer servicesByServerRWL.Unlock()
+
+	for serviceName, svc := range oldServices {
+		if newServices[serviceName] == svc {
+			continue
+		}
+		s, ok := svc.(*storageService)
+		if !ok {
+			continue
+		}
+		if storSvc, ok := newServices[serviceName].(*storageService); ok {
+			storSvc.replaceTaskWorkers(s)
+		} else {
+			s.stopTaskWorkers()
+		}
+		ctx.WithField("service", serviceName).Info("retired service")
+	}
+
+	sc.config = config
+	sc.storageServices = newServices
+
+	return nil
+}
+
+// serviceConfigChanged returns a flag indicating whether the configuration
+// of the service differs between the old and new configurations. The
+// service's own section is compared along with the top-level section of its
+// driver and the default driver, since a service's scoped configuration
+// falls back to those keys.
+func serviceConfigChanged(
+	oldConfig, newConfig gofig.Config, svc types.StorageService) bool {
+
+	keys := []string{
+		fmt.Sprintf("libstorage.server.services.%s", svc.Name()),
+		"libstorage.driver",
+		types.ConfigServerTasksWorkers,
+	}
+	if d := svc.Driver(); d != nil {
+		keys = append(keys, d.Name())
+	}
+
+	for _, k := range keys {
+		if !reflect.DeepEqual(oldConfig.Get(k), newConfig.Get(k)) {
+			return true
+		}
+	}
+	return false
+}
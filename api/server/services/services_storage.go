@@ -2,6 +2,8 @@ package services
 
 import (
 	"fmt"
+	"hash/fnv"
+	"sync"
 
 	gofig "github.com/akutz/gofig/types"
 	"github.com/akutz/goof"
@@ -13,11 +15,145 @@ import (
 )
 
 type storageService struct {
-	name          string
-	driver        types.StorageDriver
-	config        gofig.Config
-	authConfig    *types.AuthConfig
-	taskExecQueue chan *task
+	name        string
+	driver      types.StorageDriver
+	config      gofig.Config
+	authConfig  *types.AuthConfig
+	taskWorkers []*taskWorker
+	health      serviceHealth
+
+	// barrierLk serializes the enqueueing of service-wide tasks so that
+	// their barriers are in the same order in every worker's queue
+	barrierLk sync.Mutex
+
+	// settings is the service's section of the server configuration
+	settings map[string]interface{}
+}
+
+// taskWorker executes the tasks in its queue in the order they were
+// enqueued.
+type taskWorker struct {
+	sync.Mutex
+	queue   []*task
+	signal  chan int
+	stopped bool
+	exited  chan int
+
+	// after, if set, is closed once the worker may execute its tasks
+	after <-chan int
+}
+
+func newTaskWorker() *taskWorker {
+	return &taskWorker{signal: make(chan int, 1), exited: make(chan int)}
+}
+
+// enqueue appends the task to the worker's queue and returns whether it was
+// accepted.
+func (w *taskWorker) enqueue(t *task) bool {
+	w.Lock()
+	if w.stopped {
+		w.Unlock()
+		if b := t.barrier; b != nil && b.task != t {
+			// a stopped worker executes no tasks after those already in
+			// its queue, so it reaches the barrier once it exits
+			go func() {
+				w.wait()
+				b.arrived.Done()
+			}()
+			return false
+		}
+		// the worker's service was replaced or removed, so the task is
+		// rejected rather than executed out of order with the tasks that
+		// remain in the worker's queue
+		t.rejectTask(utils.NewServiceStoppedError(t.storService.Name()))
+		return false
+	}
+	w.queue = append(w.queue, t)
+	w.Unlock()
+	select {
+	case w.signal <- 1:
+	default:
+	}
+	return true
+}
+
+// dequeue returns the next task in the queue and whether the worker has
+// been stopped.
+func (w *taskWorker) dequeue() (*task, bool) {
+	w.Lock()
+	defer w.Unlock()
+	if len(w.queue) == 0 {
+		return nil, w.stopped
+	}
+	t := w.queue[0]
+	w.queue[0] = nil
+	w.queue = w.queue[1:]
+	return t, w.stopped
+}
+
+func (w *taskWorker) run() {
+	defer close(w.exited)
+	for range w.signal {
+		w.Lock()
+		after := w.after
+		w.Unlock()
+		if after != nil {
+			<-after
+		}
+		for {
+			t, stopped := w.dequeue()
+			if t == nil {
+				if stopped {
+					return
+				}
+				break
+			}
+			if t.barrier != nil {
+				t.barrier.pass(t)
+				continue
+			}
+			execTask(t)
+		}
+	}
+}
+
+// taskBarrier executes a task once all of a service's workers have executed
+// the tasks enqueued before it, and holds the workers until it completes.
+type taskBarrier struct {
+	task    *task
+	arrived sync.WaitGroup
+	done    chan int
+}
+
+// pass is called by a worker when it dequeues one of the barrier's entries.
+// The worker that dequeues the barrier's task executes it once the other
+// workers have arrived, while the other workers wait for it to complete.
+func (b *taskBarrier) pass(t *task) {
+	if t != b.task {
+		b.arrived.Done()
+		<-b.done
+		return
+	}
+	b.arrived.Wait()
+	execTask(t)
+	close(b.done)
+}
+
+// stop causes the worker to reject new tasks and to exit once the tasks
+// already in its queue have been executed.
+func (w *taskWorker) stop() {
+	w.Lock()
+	w.stopped = true
+	w.Unlock()
+	select {
+	case w.signal <- 1:
+	default:
+	}
+}
+
+// wait blocks until the worker exits.
+func (w *taskWorker) wait() {
+	<-w.exited
 }
 
 func (s *storageService) Init(ctx types.Context, config gofig.Config) error {
@@ -27,12 +163,7 @@ func (s *storageService) Init(ctx types.Context, config gofig.Config) error {
 		return err
 	}
 
-	s.taskExecQueue = make(chan *task)
-	go func() {
-		for t := range s.taskExecQueue {
-			execTask(t)
-		}
-	}()
+	s.initTaskWorkers(ctx)
 
 	authFields := map[string]interface{}{}
 	authConfig, err := utils.ParseAuthConfig(
@@ -49,6 +180,113 @@ func (s *storageService) Init(ctx types.Context, config gofig.Config) error {
 	return nil
 }
 
+func (s *storageService) initTaskWorkers(ctx types.Context) {
+	workers := s.config.GetInt("tasks.workers")
+	if workers <= 0 {
+		workers = s.config.GetInt(types.ConfigServerTasksWorkers)
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+
+	s.taskWorkers = make([]*taskWorker, workers)
+	for i := range s.taskWorkers {
+		w := newTaskWorker()
+		s.taskWorkers[i] = w
+		go w.run()
+	}
+
+	ctx.WithField("workers", workers).Info("configured service task workers")
+}
+
+// stopTaskWorkers stops the service's task workers once their queues are
+// drained. The workers reject the tasks that are enqueued after they are
+// stopped.
+func (s *storageService) stopTaskWorkers() {
+	for _, w := range s.taskWorkers {
+		w.stop()
+	}
+}
+
+// replaceTaskWorkers stops the task workers of the service that the service
+// replaces. The service's task workers do not execute their tasks until the
+// replaced service's workers have executed the tasks already in their queues,
+// so the tasks for each volume are executed in the order in which they were
+// enqueued even though the services may assign them to different workers.
+func (s *storageService) replaceTaskWorkers(old *storageService) {
+	drained := make(chan int)
+	for _, w := range s.taskWorkers {
+		w.Lock()
+		w.after = drained
+		w.Unlock()
+	}
+	old.stopTaskWorkers()
+	go func() {
+		old.waitTaskWorkers()
+		close(drained)
+	}()
+}
+
+// waitTaskWorkers blocks until the service's stopped task workers have
+// executed the tasks in their queues and exited.
+func (s *storageService) waitTaskWorkers() {
+	for _, w := range s.taskWorkers {
+		w.wait()
+	}
+}
+
+// serviceWideRoutes are the names of the routes whose tasks may operate on
+// any of the service's volumes.
+var serviceWideRoutes = map[string]bool{
+	"volumesDetachAll":        true,
+	"volumesDetachForService": true,
+}
+
+func isServiceWideTask(t *task) bool {
+	route, ok := context.Route(t.ctx)
+	return ok && serviceWideRoutes[route.GetName()]
+}
+
+// enqueueTask assigns the task to its worker. A task that may operate on any
+// of the service's volumes is executed as a barrier across all of the
+// service's workers so that it is ordered with the tasks for each volume.
+func (s *storageService) enqueueTask(t *task) {
+	if len(s.taskWorkers) == 1 || !isServiceWideTask(t) {
+		s.taskWorkerFor(t).enqueue(t)
+		return
+	}
+
+	s.barrierLk.Lock()
+	defer s.barrierLk.Unlock()
+
+	b := &taskBarrier{task: t, done: make(chan int)}
+	t.barrier = b
+	b.arrived.Add(len(s.taskWorkers) - 1)
+	if !s.taskWorkers[0].enqueue(t) {
+		return
+	}
+	for _, w := range s.taskWorkers[1:] {
+		w.enqueue(&task{barrier: b})
+	}
+}
+
+// taskWorkerFor returns the worker that executes the task. Tasks that target
+// the same volume are always assigned to the same worker so that they are
+// executed in order, while all other tasks are distributed across the
+// workers by their IDs.
+func (s *storageService) taskWorkerFor(t *task) *taskWorker {
+	n := len(s.taskWorkers)
+	if n == 1 {
+		return s.taskWorkers[0]
+	}
+	if volumeID, ok := context.VolumeID(t.ctx); ok && volumeID != "" {
+		h := fnv.New32a()
+		h.Write([]byte(volumeID))
+		return s.taskWorkers[h.Sum32()%uint32(n)]
+	}
+	return s.taskWorkers[t.ID%n]
+}
+
 func (s *storageService) initStorageDriver(ctx types.Context) error {
 	driverName := s.config.GetString("driver")
 	if driverName == "" {
@@ -74,7 +312,7 @@ func (s *storageService) initStorageDriver(ctx types.Context) error {
 		return err
 	}
 
-	s.driver = driver
+	s.driver = registry.NewStorageDriverManager(driver, EventPublish)
 	return nil
 }
 
@@ -96,7 +334,7 @@ func (s *storageService) TaskEnqueue(
 	schema []byte) *types.Task {
 
 	t := newStorageServiceTask(ctx, run, s, schema)
-	go func() { s.taskExecQueue <- t }()
+	s.enqueueTask(t)
 	return &t.Task
 }
 
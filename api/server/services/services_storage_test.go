@@ -0,0 +1,392 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func newTestStorageService(name string) *storageService {
+	w := newTaskWorker()
+	go w.run()
+	return &storageService{name: name, taskWorkers: []*taskWorker{w}}
+}
+
+// newTestStorageServiceWorkers returns a service with the given number of
+// task workers.
+func newTestStorageServiceWorkers(name string, n int) *storageService {
+	svc := &storageService{name: name, taskWorkers: make([]*taskWorker, n)}
+	for i := range svc.taskWorkers {
+		w := newTaskWorker()
+		svc.taskWorkers[i] = w
+		go w.run()
+	}
+	return svc
+}
+
+type testRoute struct {
+	types.Route
+	name string
+}
+
+func (r *testRoute) GetName() string {
+	return r.name
+}
+
+func withTestVolumeID(t *task, volumeID string) *task {
+	t.ctx = t.ctx.WithValue(context.VolumeIDKey, volumeID)
+	return t
+}
+
+func withTestRoute(t *task, name string) *task {
+	t.ctx = t.ctx.WithValue(context.RouteKey, &testRoute{name: name})
+	return t
+}
+
+// getTestVolumeIDs returns the IDs of two volumes whose tasks are assigned to
+// different workers.
+func getTestVolumeIDs(t *testing.T, svc *storageService) (string, string) {
+	a := withTestVolumeID(newTestStorageTask(svc, nil), "vol-000")
+	for i := 1; i < 100; i++ {
+		id := fmt.Sprintf("vol-%03d", i)
+		b := withTestVolumeID(newTestStorageTask(svc, nil), id)
+		if svc.taskWorkerFor(a) != svc.taskWorkerFor(b) {
+			return "vol-000", id
+		}
+	}
+	t.Fatal("no volumes assigned to different workers")
+	return "", ""
+}
+
+func newTestStorageTask(
+	svc *storageService, run types.StorageTaskRunFunc) *task {
+
+	t := newTestTask(context.Background(), nil)
+	t.storService = svc
+	t.storRunFunc = run
+	return t
+}
+
+// newBlockedTask returns a task that blocks until the returned channel is
+// closed.
+func newBlockedTask(svc *storageService) (*task, chan bool, chan bool) {
+	running := make(chan bool)
+	release := make(chan bool)
+	t := newTestStorageTask(
+		svc,
+		func(ctx types.Context, svc types.StorageService) (interface{}, error) {
+			close(running)
+			<-release
+			return nil, nil
+		})
+	return t, running, release
+}
+
+func TestTaskWorkerOrder(t *testing.T) {
+	svc := newTestStorageService("vfs")
+	w := svc.taskWorkers[0]
+
+	tk, running, release := newBlockedTask(svc)
+	w.enqueue(tk)
+	<-running
+
+	// the tasks that are queued behind a running task are executed in the
+	// order in which they were enqueued
+	var (
+		order []int
+		tasks []*task
+	)
+	for i := 0; i < 5; i++ {
+		i := i
+		qt := newTestStorageTask(
+			svc,
+			func(ctx types.Context, svc types.StorageService) (
+				interface{}, error) {
+				order = append(order, i)
+				return nil, nil
+			})
+		tasks = append(tasks, qt)
+		w.enqueue(qt)
+	}
+
+	close(release)
+	for _, qt := range tasks {
+		waitTask(t, qt)
+	}
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, order)
+
+	w.stop()
+	w.wait()
+}
+
+func TestTaskWorkerStop(t *testing.T) {
+	svc := newTestStorageService("vfs")
+	w := svc.taskWorkers[0]
+
+	tk, running, release := newBlockedTask(svc)
+	w.enqueue(tk)
+	<-running
+
+	ran := false
+	queued := newTestStorageTask(
+		svc,
+		func(ctx types.Context, svc types.StorageService) (interface{}, error) {
+			ran = true
+			return nil, nil
+		})
+	w.enqueue(queued)
+	w.stop()
+
+	// a task that is enqueued after the worker is stopped is rejected
+	res := &testReservation{}
+	rejected := newTestStorageTask(svc, nil)
+	rejected.ctx = rejected.ctx.WithValue(context.QuotaReservationKey, res)
+	w.enqueue(rejected)
+	waitTask(t, rejected)
+	assert.Equal(t, types.TaskStateError, rejected.State)
+	assert.IsType(t, &types.ErrServiceStopped{}, rejected.Error)
+	assert.True(t, res.cancelled)
+
+	// while the tasks already in its queue are executed before it exits
+	exited := make(chan bool)
+	go func() {
+		w.wait()
+		close(exited)
+	}()
+	select {
+	case <-exited:
+		t.Fatal("worker exited before its queue was drained")
+	case <-time.After(time.Duration(50) * time.Millisecond):
+	}
+
+	close(release)
+	waitTask(t, queued)
+	assert.True(t, ran)
+	select {
+	case <-exited:
+	case <-time.After(time.Duration(5) * time.Second):
+		t.Fatal("timed out waiting for worker to exit")
+	}
+}
+
+func TestRemoveStorageService(t *testing.T) {
+	const serverName = "test-remove-storage-service"
+	ctx := context.Background().WithValue(context.ServerKey, serverName)
+
+	svc := newTestStorageService("vfs")
+	servicesByServerRWL.Lock()
+	servicesByServer[serverName] = &serviceContainer{
+		storageServices: map[string]types.StorageService{"vfs": svc},
+	}
+	servicesByServerRWL.Unlock()
+	defer func() {
+		servicesByServerRWL.Lock()
+		delete(servicesByServer, serverName)
+		servicesByServerRWL.Unlock()
+	}()
+
+	tk, running, release := newBlockedTask(svc)
+	svc.taskWorkers[0].enqueue(tk)
+	<-running
+
+	removed := make(chan error)
+	go func() {
+		removed <- RemoveStorageService(ctx, "VFS")
+	}()
+
+	// the service is removed and stopped before it is drained
+	stopped := func() bool {
+		w := svc.taskWorkers[0]
+		w.Lock()
+		defer w.Unlock()
+		return w.stopped
+	}
+	for i := 0; GetStorageService(ctx, "vfs") != nil || !stopped(); i++ {
+		if i == 500 {
+			t.Fatal("timed out waiting for service to be removed")
+		}
+		time.Sleep(time.Duration(10) * time.Millisecond)
+	}
+
+	// so a task enqueued by a request that found the service before it was
+	// removed is rejected
+	rejected := newTestStorageTask(svc, nil)
+	svc.taskWorkers[0].enqueue(rejected)
+	waitTask(t, rejected)
+	assert.IsType(t, &types.ErrServiceStopped{}, rejected.Error)
+
+	// and the removal completes once the running task is completed
+	select {
+	case <-removed:
+		t.Fatal("service removed before it was drained")
+	default:
+	}
+	close(release)
+	select {
+	case err := <-removed:
+		assert.NoError(t, err)
+	case <-time.After(time.Duration(5) * time.Second):
+		t.Fatal("timed out waiting for service to be removed")
+	}
+
+	assert.Error(t, RemoveStorageService(ctx, "vfs"))
+}
+
+func TestReplaceTaskWorkers(t *testing.T) {
+	old := newTestStorageService("vfs")
+	tk, running, release := newBlockedTask(old)
+	old.taskWorkers[0].enqueue(tk)
+	<-running
+
+	var order []string
+	queued := newTestStorageTask(
+		old,
+		func(ctx types.Context, svc types.StorageService) (interface{}, error) {
+			order = append(order, "old")
+			return nil, nil
+		})
+	old.taskWorkers[0].enqueue(queued)
+
+	svc := newTestStorageService("vfs")
+	svc.replaceTaskWorkers(old)
+
+	// the tasks of the new service wait for those of the service it
+	// replaced
+	next := newTestStorageTask(
+		svc,
+		func(ctx types.Context, svc types.StorageService) (interface{}, error) {
+			order = append(order, "new")
+			return nil, nil
+		})
+	svc.taskWorkers[0].enqueue(next)
+
+	select {
+	case <-next.done:
+		t.Fatal("task executed before the replaced service was drained")
+	case <-time.After(time.Duration(50) * time.Millisecond):
+	}
+
+	close(release)
+	waitTask(t, next)
+	assert.Equal(t, []string{"old", "new"}, order)
+
+	svc.stopTaskWorkers()
+	svc.waitTaskWorkers()
+}
+
+func TestTaskWorkersVolumeOrder(t *testing.T) {
+	svc := newTestStorageServiceWorkers("vfs", 4)
+
+	tk, running, release := newBlockedTask(svc)
+	svc.enqueueTask(withTestVolumeID(tk, "vol-000"))
+	<-running
+
+	// the tasks for a volume are executed in the order in which they were
+	// enqueued regardless of the number of workers
+	var (
+		order []int
+		tasks []*task
+	)
+	for i := 0; i < 5; i++ {
+		i := i
+		qt := newTestStorageTask(
+			svc,
+			func(ctx types.Context, svc types.StorageService) (
+				interface{}, error) {
+				order = append(order, i)
+				return nil, nil
+			})
+		tasks = append(tasks, qt)
+		svc.enqueueTask(withTestVolumeID(qt, "vol-000"))
+	}
+
+	close(release)
+	for _, qt := range tasks {
+		waitTask(t, qt)
+	}
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, order)
+
+	svc.stopTaskWorkers()
+	svc.waitTaskWorkers()
+}
+
+func TestTaskWorkersParallel(t *testing.T) {
+	svc := newTestStorageServiceWorkers("vfs", 4)
+	volA, volB := getTestVolumeIDs(t, svc)
+
+	tk, running, release := newBlockedTask(svc)
+	svc.enqueueTask(withTestVolumeID(tk, volA))
+	<-running
+
+	// a task for another volume is not queued behind the running task
+	other := withTestVolumeID(newTestStorageTask(
+		svc,
+		func(ctx types.Context, svc types.StorageService) (interface{}, error) {
+			return nil, nil
+		}), volB)
+	svc.enqueueTask(other)
+	waitTask(t, other)
+	assert.Equal(t, types.TaskStateSuccess, other.State)
+
+	close(release)
+	waitTask(t, tk)
+
+	svc.stopTaskWorkers()
+	svc.waitTaskWorkers()
+}
+
+func TestTaskWorkersServiceWide(t *testing.T) {
+	svc := newTestStorageServiceWorkers("vfs", 4)
+	volA, volB := getTestVolumeIDs(t, svc)
+
+	var (
+		orderLk sync.Mutex
+		order   []string
+	)
+	record := func(name string) types.StorageTaskRunFunc {
+		return func(
+			ctx types.Context, svc types.StorageService) (interface{}, error) {
+			orderLk.Lock()
+			order = append(order, name)
+			orderLk.Unlock()
+			return nil, nil
+		}
+	}
+
+	tk, running, release := newBlockedTask(svc)
+	svc.enqueueTask(withTestVolumeID(tk, volA))
+	<-running
+
+	// a task that detaches all of the service's volumes waits for the
+	// tasks enqueued before it on every worker
+	detach := withTestRoute(
+		newTestStorageTask(svc, record("detach")), "volumesDetachAll")
+	svc.enqueueTask(detach)
+
+	// and the tasks enqueued after it wait for it, even those for volumes
+	// assigned to other workers
+	next := withTestVolumeID(newTestStorageTask(svc, record(volB)), volB)
+	svc.enqueueTask(next)
+
+	select {
+	case <-detach.done:
+		t.Fatal("service-wide task executed before the running task")
+	case <-next.done:
+		t.Fatal("task executed before the service-wide task")
+	case <-time.After(time.Duration(50) * time.Millisecond):
+	}
+
+	close(release)
+	waitTask(t, detach)
+	waitTask(t, next)
+	assert.Equal(t, []string{"detach", volB}, order)
+
+	svc.stopTaskWorkers()
+	svc.waitTaskWorkers()
+}
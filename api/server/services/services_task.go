@@ -12,25 +12,71 @@ import (
 	"github.com/akutz/goof"
 
 	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/registry"
 	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
 	"github.com/codedellemc/libstorage/api/utils/schema"
 )
 
 type task struct {
 	types.Task
+	sync.Mutex
 	ctx                           types.Context
+	cancel                        func()
+	cancelled                     bool
 	runFunc                       types.TaskRunFunc
 	storRunFunc                   types.StorageTaskRunFunc
 	storService                   types.StorageService
 	resultSchema                  []byte
 	resultSchemaValidationEnabled bool
+	store                         types.TaskStore
 	done                          chan int
+
+	// barrier, if set, is the barrier of a task executed across all of a
+	// service's workers
+	barrier *taskBarrier
+}
+
+// save persists the task's current state to the task store. Errors are
+// logged but not returned since the task itself is not affected by them.
+func (t *task) save() {
+	if t.store == nil {
+		return
+	}
+	if err := t.store.Save(&t.Task); err != nil {
+		t.ctx.WithError(err).Error("error saving task")
+	}
+}
+
+// publish publishes the task's current state.
+func (t *task) publish() {
+	t.Lock()
+	taskID := t.ID
+	ev := &types.Event{
+		Type:      types.EventTaskState,
+		TaskID:    &taskID,
+		TaskState: t.State,
+	}
+	t.Unlock()
+	if t.storService != nil {
+		ev.Service = t.storService.Name()
+	}
+	EventPublish(t.ctx, ev)
 }
 
 func newTask(ctx types.Context, schema []byte) *task {
-	t := getTaskService(ctx).taskTrack(ctx)
+	s := getTaskService(ctx)
+	t := s.taskTrack(ctx)
 	t.resultSchema = schema
 	t.done = make(chan int)
+
+	// the task is removed from the store once it has been completed for
+	// longer than the retention
+	go func() {
+		<-t.done
+		s.storeRemoveAfter(t.ctx, t.ID, s.retention)
+	}()
+
 	return t
 }
 
@@ -56,60 +102,156 @@ func newStorageServiceTask(
 	return t
 }
 
+// cancelTask cancels the task. A queued task is marked as cancelled and
+// completed immediately, whereas a running task has its context cancelled
+// and is marked as cancelled once its run function returns. The returned
+// task is a copy of the task's state when it was cancelled.
+func (t *task) cancelTask() (*types.Task, error) {
+	t.Lock()
+	switch t.State {
+	case types.TaskStateCancelled:
+		task := t.Task
+		t.Unlock()
+		return &task, nil
+	case types.TaskStateSuccess, types.TaskStateError:
+		taskID, state := t.ID, t.State
+		t.Unlock()
+		return nil, utils.NewTaskCompletedError(taskID, state)
+	case types.TaskStateRunning:
+		t.cancelled = true
+		task := t.Task
+		t.Unlock()
+		t.ctx.Info("cancelling running task")
+		t.cancel()
+		return &task, nil
+	}
+
+	t.cancelled = true
+	task := t.completeQueued(types.TaskStateCancelled, types.ErrTaskCancelled)
+	t.ctx.Info("cancelled queued task")
+
+	return &task, nil
+}
+
+// rejectTask completes a task that was not queued with the given error. The
+// task is not completed if it is no longer queued.
+func (t *task) rejectTask(err error) {
+	t.Lock()
+	if t.State != types.TaskStateQueued {
+		t.Unlock()
+		return
+	}
+	t.completeQueued(types.TaskStateError, err)
+	t.ctx.WithError(err).Error("rejected task")
+}
+
+// completeQueued completes a queued task whose run function is never run
+// with the given state and error. The task's lock must be held, and it is
+// released before the task is saved and published. The returned task is a
+// copy of the completed task's state.
+func (t *task) completeQueued(state types.TaskState, err error) types.Task {
+	t.State = state
+	t.CompleteTime = time.Now().Unix()
+	t.Error = err
+	task := t.Task
+	t.Unlock()
+
+	// the quota reserved for the volume the task would have created is
+	// released here since the task's run function is never run
+	QuotaComplete(t.ctx, nil, err)
+
+	t.cancel()
+	t.save()
+	t.publish()
+	close(t.done)
+
+	return task
+}
+
 func execTask(t *task) {
+	t.Lock()
+	if state := t.State; state != types.TaskStateQueued {
+		t.Unlock()
+		t.ctx.WithField("state", state).Debug("skipping task execution")
+		return
+	}
+	t.State = types.TaskStateRunning
+	t.StartTime = time.Now().Unix()
+	t.Unlock()
+	t.save()
+	t.publish()
+
 	defer func() {
+		t.Lock()
 		t.CompleteTime = time.Now().Unix()
-		if t.Error != nil {
+		if t.Error != nil && t.cancelled {
+			t.Error = types.ErrTaskCancelled
+			t.State = types.TaskStateCancelled
+		} else if t.Error != nil {
 			t.ctx.Error(t.Error)
 			t.State = types.TaskStateError
 		} else {
 			t.State = types.TaskStateSuccess
 		}
+		t.Unlock()
+		t.cancel()
+		t.save()
+		t.publish()
 		close(t.done)
 		t.ctx.Debug("task completed")
 	}()
 
-	t.State = types.TaskStateRunning
-	t.StartTime = time.Now().Unix()
-
 	t.ctx.Info("executing task")
 
+	// the result is recorded under the task's lock since the task may be
+	// read, such as when it is cancelled, while it is running
+	result, err := t.run()
+	t.Lock()
+	t.Result, t.Error = result, err
+	t.Unlock()
+}
+
+// run runs the task's run function and validates its result.
+func (t *task) run() (interface{}, error) {
+
+	var (
+		result interface{}
+		err    error
+	)
+
 	if t.storRunFunc != nil && t.storService != nil {
-		t.Result, t.Error = t.storRunFunc(t.ctx, t.storService)
+		result, err = t.storRunFunc(t.ctx, t.storService)
 	} else if t.runFunc != nil {
-		t.Result, t.Error = t.runFunc(t.ctx)
+		result, err = t.runFunc(t.ctx)
 	} else {
-		t.Error = goof.New("invalid task")
+		err = goof.New("invalid task")
 	}
 
-	if t.Error != nil {
-		return
+	if err != nil {
+		return result, err
 	}
 
-	if t.Result == nil {
+	if result == nil {
 		t.ctx.Debug("skipping response schema validation; result == nil")
-		return
+		return result, nil
 	}
 
 	if t.resultSchema == nil {
 		t.ctx.Debug("skipping response schema validation; schema == nil")
-		return
+		return result, nil
 	}
 
 	if !t.resultSchemaValidationEnabled {
 		t.ctx.Debug("skipping response schema validation; disabled")
-		return
+		return result, nil
 	}
 
-	var buf []byte
-	if buf, t.Error = json.Marshal(t.Result); t.Error != nil {
-		return
+	buf, err := json.Marshal(result)
+	if err != nil {
+		return result, err
 	}
 
-	t.Error = schema.Validate(t.ctx, t.resultSchema, buf)
-	if t.Error != nil {
-		return
-	}
+	return result, schema.Validate(t.ctx, t.resultSchema, buf)
 }
 
 type globalTaskService struct {
@@ -117,9 +259,15 @@ type globalTaskService struct {
 	name                          string
 	config                        gofig.Config
 	tasks                         map[int]*task
+	store                         types.TaskStore
+	retention                     time.Duration
 	resultSchemaValidationEnabled bool
 }
 
+// defaultTaskRetention is how long completed tasks are kept in the task
+// store when the configured retention is invalid.
+const defaultTaskRetention = time.Duration(24) * time.Hour
+
 // Init initializes the service.
 func (s *globalTaskService) Init(ctx types.Context, config gofig.Config) error {
 	s.tasks = map[int]*task{}
@@ -130,17 +278,130 @@ func (s *globalTaskService) Init(ctx types.Context, config gofig.Config) error {
 	ctx.WithField("enabled", s.resultSchemaValidationEnabled).Debug(
 		"configured result schema validation")
 
+	storeType := config.GetString(types.ConfigServerTasksStoreType)
+	if storeType == "" {
+		storeType = fileTaskStoreName
+	}
+	store, err := registry.NewTaskStore(storeType)
+	if err != nil {
+		return err
+	}
+	if err := store.Init(ctx, config); err != nil {
+		return goof.WithFieldE(
+			"store", storeType, "error initializing task store", err)
+	}
+	s.store = store
+
+	szRetention := config.GetString(types.ConfigServerTasksStoreRetention)
+	if s.retention, err = time.ParseDuration(szRetention); err != nil {
+		ctx.WithField("retention", szRetention).Warn(
+			"invalid task retention; using default")
+		s.retention = defaultTaskRetention
+	}
+
+	ctx.WithFields(log.Fields{
+		"store":     storeType,
+		"retention": s.retention,
+	}).Info("configured task store")
+
+	if err := s.reconcileStoredTasks(ctx); err != nil {
+		return err
+	}
+	return s.gcStoredTasks(ctx)
+}
+
+// reconcileStoredTasks marks any tasks that were queued or running when the
+// server last stopped as failed since they will never complete.
+func (s *globalTaskService) reconcileStoredTasks(ctx types.Context) error {
+	tasks, err := s.store.List()
+	if err != nil {
+		return err
+	}
+	now := time.Now().Unix()
+	for _, t := range tasks {
+		if t.State != types.TaskStateQueued &&
+			t.State != types.TaskStateRunning {
+			continue
+		}
+		t.State = types.TaskStateError
+		t.CompleteTime = now
+		t.Error = goof.WithField(
+			"taskID", t.ID, "task interrupted by server restart")
+		if err := s.store.Save(t); err != nil {
+			return err
+		}
+		ctx.WithField("taskID", t.ID).Warn(
+			"marked interrupted task as failed")
+	}
+	return nil
+}
+
+// gcStoredTasks removes the completed tasks whose retention has elapsed
+// from the task store, and schedules the removal of the others for when
+// their retention elapses.
+func (s *globalTaskService) gcStoredTasks(ctx types.Context) error {
+	if s.retention <= 0 {
+		return nil
+	}
+	tasks, err := s.store.List()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, t := range tasks {
+		if t.CompleteTime == 0 {
+			continue
+		}
+		completed := time.Unix(t.CompleteTime, 0)
+		s.storeRemoveAfter(ctx, t.ID, completed.Add(s.retention).Sub(now))
+	}
 	return nil
 }
 
+// storeRemoveAfter removes the task with the specified ID from the task
+// store once the duration elapses. A retention of zero keeps tasks in the
+// store indefinitely.
+func (s *globalTaskService) storeRemoveAfter(
+	ctx types.Context, taskID int, d time.Duration) {
+
+	if s.retention <= 0 {
+		return
+	}
+
+	remove := func() {
+		if err := s.store.Remove(taskID); err != nil {
+			ctx.WithField("taskID", taskID).WithError(err).Error(
+				"error removing stored task")
+			return
+		}
+		ctx.WithField("taskID", taskID).Debug("removed stored task")
+	}
+
+	if d <= 0 {
+		remove()
+		return
+	}
+	time.AfterFunc(d, remove)
+}
+
 func (s *globalTaskService) Name() string {
 	return s.name
 }
 
 // Tasks returns a channel on which all tasks are received.
 func (s *globalTaskService) Tasks() <-chan *types.Task {
+	stored, err := s.store.List()
+	if err != nil {
+		log.WithError(err).Error("error listing stored tasks")
+	}
+
 	tasks := []*types.Task{}
 	s.RLock()
+	for _, v := range stored {
+		if _, ok := s.tasks[v.ID]; !ok {
+			tasks = append(tasks, v)
+		}
+	}
 	for _, v := range s.tasks {
 		tasks = append(tasks, &v.Task)
 	}
@@ -163,23 +424,30 @@ func (s *globalTaskService) TaskTrack(ctx types.Context) *types.Task {
 func (s *globalTaskService) taskTrack(ctx types.Context) *task {
 
 	now := time.Now().Unix()
-	s.RLock()
-	taskID := len(s.tasks)
-	s.RUnlock()
+	taskID, err := s.store.NextID()
+	if err != nil {
+		ctx.WithError(err).Error("error persisting next task id")
+	}
 
 	t := &task{
 		Task: types.Task{
 			ID:        taskID,
 			QueueTime: now,
+			State:     types.TaskStateQueued,
 		},
 		resultSchemaValidationEnabled: s.resultSchemaValidationEnabled,
-		ctx: ctx.WithValue(context.TaskKey, fmt.Sprintf("%d", taskID)),
 	}
+	t.ctx, t.cancel = context.WithCancel(
+		ctx.WithValue(context.TaskKey, fmt.Sprintf("%d", taskID)))
+	t.store = s.store
 
 	s.Lock()
 	s.tasks[taskID] = t
 	s.Unlock()
 
+	t.save()
+	t.publish()
+
 	return t
 }
 
@@ -197,11 +465,56 @@ func (s *globalTaskService) TaskEnqueue(
 // TaskInspect returns the task with the specified ID.
 func (s *globalTaskService) TaskInspect(taskID int) *types.Task {
 	s.RLock()
-	defer s.RUnlock()
-	if t, ok := s.tasks[taskID]; ok {
+	t, ok := s.tasks[taskID]
+	s.RUnlock()
+	if ok {
 		return &t.Task
 	}
-	return nil
+
+	st, err := s.store.Get(taskID)
+	if err != nil {
+		log.WithField("taskID", taskID).WithError(err).Error(
+			"error getting stored task")
+		return nil
+	}
+	return st
+}
+
+// TaskCancel cancels the task with the specified ID.
+func (s *globalTaskService) TaskCancel(taskID int) (*types.Task, error) {
+	s.RLock()
+	t, ok := s.tasks[taskID]
+	s.RUnlock()
+	if ok {
+		return t.cancelTask()
+	}
+
+	// tasks that are no longer tracked in memory have already completed
+	st, err := s.store.Get(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if st == nil {
+		return nil, utils.NewNotFoundError(fmt.Sprintf("%d", taskID))
+	}
+	if st.State == types.TaskStateCancelled {
+		return st, nil
+	}
+	return nil, utils.NewTaskCompletedError(st.ID, st.State)
+}
+
+// taskStorageService returns the storage service of the task with the
+// specified ID, if the task is tracked and was created for a service.
+func (s *globalTaskService) taskStorageService(
+	taskID int) types.StorageService {
+
+	s.RLock()
+	t, ok := s.tasks[taskID]
+	s.RUnlock()
+	if !ok {
+		return nil
+	}
+	return t.storService
 }
 
 // TaskWait blocks until the specified task is completed.
@@ -235,8 +548,9 @@ func (s *globalTaskService) TaskWaitC(taskID int) <-chan int {
 	return c
 }
 
-// taskRemoveAfter tells the task service to remove the task after the duration
-// specified by `libstorage.server.tasks.logTimeout`.
+// taskRemoveAfter tells the task service to remove the task from memory after
+// the duration specified by `libstorage.server.tasks.logTimeout`. The task is
+// still returned from the task store until its retention elapses.
 func (s *globalTaskService) taskRemoveAfter(t *task) {
 	go func() {
 		logTimeoutDur, err := time.ParseDuration(
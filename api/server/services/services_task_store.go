@@ -0,0 +1,81 @@
+package services
+
+import (
+	"sync"
+
+	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+const memTaskStoreName = "memory"
+
+func init() {
+	registry.RegisterTaskStore(memTaskStoreName, newMemTaskStore)
+}
+
+// memTaskStore is a task store that keeps tasks in memory. Task IDs are
+// monotonic for the life of the process.
+type memTaskStore struct {
+	sync.RWMutex
+	nextID int
+	tasks  map[int]*types.Task
+}
+
+func newMemTaskStore() types.TaskStore {
+	return &memTaskStore{}
+}
+
+func (s *memTaskStore) Name() string {
+	return memTaskStoreName
+}
+
+func (s *memTaskStore) Init(ctx types.Context, config gofig.Config) error {
+	s.tasks = map[int]*types.Task{}
+	return nil
+}
+
+func (s *memTaskStore) NextID() (int, error) {
+	s.Lock()
+	defer s.Unlock()
+	id := s.nextID
+	s.nextID++
+	return id, nil
+}
+
+func (s *memTaskStore) Save(task *types.Task) error {
+	t := *task
+	s.Lock()
+	defer s.Unlock()
+	s.tasks[t.ID] = &t
+	return nil
+}
+
+func (s *memTaskStore) Get(taskID int) (*types.Task, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if t, ok := s.tasks[taskID]; ok {
+		c := *t
+		return &c, nil
+	}
+	return nil, nil
+}
+
+func (s *memTaskStore) Remove(taskID int) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.tasks, taskID)
+	return nil
+}
+
+func (s *memTaskStore) List() ([]*types.Task, error) {
+	s.RLock()
+	defer s.RUnlock()
+	tasks := make([]*types.Task, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		c := *t
+		tasks = append(tasks, &c)
+	}
+	return tasks, nil
+}
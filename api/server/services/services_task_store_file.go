@@ -0,0 +1,247 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+const (
+	fileTaskStoreName   = "file"
+	fileTaskStoreNextID = "next"
+)
+
+func init() {
+	registry.RegisterTaskStore(fileTaskStoreName, newFileTaskStore)
+}
+
+// fileTaskStore is a task store that persists each task as a JSON document
+// in a directory on disk. The next task ID is persisted as well so that IDs
+// are never reused across restarts.
+type fileTaskStore struct {
+	sync.Mutex
+	dir    string
+	nextID int
+}
+
+// fileTaskRecord is the on-disk representation of a task. The task's result
+// and error are persisted as raw JSON since their concrete types are not
+// known when the record is read.
+type fileTaskRecord struct {
+	ID           int             `json:"id"`
+	User         string          `json:"user,omitempty"`
+	CompleteTime int64           `json:"completeTime,omitempty"`
+	QueueTime    int64           `json:"queueTime"`
+	StartTime    int64           `json:"startTime,omitempty"`
+	State        types.TaskState `json:"state"`
+	Result       json.RawMessage `json:"result,omitempty"`
+	Error        json.RawMessage `json:"error,omitempty"`
+	ErrorMessage string          `json:"errorMessage,omitempty"`
+}
+
+// storedTaskError is an error read from a task store. It marshals to the
+// same JSON as the error that was originally stored.
+type storedTaskError struct {
+	msg string
+	raw json.RawMessage
+}
+
+func (e *storedTaskError) Error() string {
+	return e.msg
+}
+
+func (e *storedTaskError) MarshalJSON() ([]byte, error) {
+	if len(e.raw) == 0 {
+		return json.Marshal(e.msg)
+	}
+	return e.raw, nil
+}
+
+func newFileTaskStore() types.TaskStore {
+	return &fileTaskStore{}
+}
+
+func (s *fileTaskStore) Name() string {
+	return fileTaskStoreName
+}
+
+func (s *fileTaskStore) Init(ctx types.Context, config gofig.Config) error {
+	s.dir = config.GetString(types.ConfigServerTasksStorePath)
+	if s.dir == "" {
+		return goof.New("task store path is required")
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return goof.WithFieldE("path", s.dir, "error creating task store", err)
+	}
+
+	buf, err := ioutil.ReadFile(path.Join(s.dir, fileTaskStoreNextID))
+	if err != nil && !os.IsNotExist(err) {
+		return goof.WithFieldE(
+			"path", s.dir, "error reading next task id", err)
+	}
+	if len(buf) > 0 {
+		szID := strings.TrimSpace(string(buf))
+		if s.nextID, err = strconv.Atoi(szID); err != nil {
+			return goof.WithFieldE("path", s.dir, "invalid next task id", err)
+		}
+	}
+
+	// guard against a next ID file that is behind the stored tasks
+	tasks, err := s.List()
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		if t.ID >= s.nextID {
+			s.nextID = t.ID + 1
+		}
+	}
+
+	ctx.WithFields(log.Fields{
+		"path":   s.dir,
+		"nextID": s.nextID,
+		"tasks":  len(tasks),
+	}).Debug("initialized file task store")
+
+	return nil
+}
+
+func (s *fileTaskStore) NextID() (int, error) {
+	s.Lock()
+	defer s.Unlock()
+	id := s.nextID
+	s.nextID++
+	return id, s.writeFile(
+		fileTaskStoreNextID, []byte(strconv.Itoa(s.nextID)))
+}
+
+func (s *fileTaskStore) Save(task *types.Task) error {
+	rec := &fileTaskRecord{
+		ID:           task.ID,
+		User:         task.User,
+		CompleteTime: task.CompleteTime,
+		QueueTime:    task.QueueTime,
+		StartTime:    task.StartTime,
+		State:        task.State,
+	}
+
+	if task.Result != nil {
+		buf, err := json.Marshal(task.Result)
+		if err != nil {
+			return goof.WithFieldE(
+				"taskID", task.ID, "error marshaling task result", err)
+		}
+		rec.Result = buf
+	}
+
+	if task.Error != nil {
+		rec.ErrorMessage = task.Error.Error()
+		// errors without any exported fields marshal to an empty object,
+		// in which case only the error message is persisted
+		buf, err := json.Marshal(task.Error)
+		if err == nil && string(buf) != "{}" {
+			rec.Error = buf
+		}
+	}
+
+	buf, err := json.Marshal(rec)
+	if err != nil {
+		return goof.WithFieldE("taskID", task.ID, "error marshaling task", err)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	return s.writeFile(taskFileName(task.ID), buf)
+}
+
+func (s *fileTaskStore) Get(taskID int) (*types.Task, error) {
+	t, err := s.readTask(path.Join(s.dir, taskFileName(taskID)))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return t, err
+}
+
+func (s *fileTaskStore) Remove(taskID int) error {
+	err := os.Remove(path.Join(s.dir, taskFileName(taskID)))
+	if err != nil && !os.IsNotExist(err) {
+		return goof.WithFieldE("taskID", taskID, "error removing task", err)
+	}
+	return nil
+}
+
+func (s *fileTaskStore) List() ([]*types.Task, error) {
+	fis, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, goof.WithFieldE(
+			"path", s.dir, "error listing task store", err)
+	}
+
+	tasks := []*types.Task{}
+	for _, fi := range fis {
+		if fi.IsDir() || path.Ext(fi.Name()) != ".json" {
+			continue
+		}
+		t, err := s.readTask(path.Join(s.dir, fi.Name()))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+func (s *fileTaskStore) readTask(filePath string) (*types.Task, error) {
+	buf, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &fileTaskRecord{}
+	if err := json.Unmarshal(buf, rec); err != nil {
+		return nil, goof.WithFieldE(
+			"path", filePath, "error unmarshaling task", err)
+	}
+
+	t := &types.Task{
+		ID:           rec.ID,
+		User:         rec.User,
+		CompleteTime: rec.CompleteTime,
+		QueueTime:    rec.QueueTime,
+		StartTime:    rec.StartTime,
+		State:        rec.State,
+	}
+	if len(rec.Result) > 0 {
+		t.Result = rec.Result
+	}
+	if rec.ErrorMessage != "" || len(rec.Error) > 0 {
+		t.Error = &storedTaskError{msg: rec.ErrorMessage, raw: rec.Error}
+	}
+	return t, nil
+}
+
+// writeFile atomically writes the data to the named file in the store's
+// directory.
+func (s *fileTaskStore) writeFile(name string, data []byte) error {
+	return utils.WriteFileAtomic(path.Join(s.dir, name), data)
+}
+
+func taskFileName(taskID int) string {
+	return fmt.Sprintf("%d.json", taskID)
+}
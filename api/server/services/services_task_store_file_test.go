@@ -0,0 +1,169 @@
+package services
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	gofigCore "github.com/akutz/gofig"
+	"github.com/akutz/goof"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func newTestFileTaskStore(t *testing.T, dir string) types.TaskStore {
+	config := gofigCore.New()
+	config.Set(types.ConfigServerTasksStorePath, dir)
+	s := newFileTaskStore()
+	if err := s.Init(context.Background(), config); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func newTestTaskStoreDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "libstorage-tasks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestFileTaskStoreNextID(t *testing.T) {
+	dir := newTestTaskStoreDir(t)
+	defer os.RemoveAll(dir)
+
+	s := newTestFileTaskStore(t, dir)
+	for i := 0; i < 3; i++ {
+		id, err := s.NextID()
+		assert.NoError(t, err)
+		assert.Equal(t, i, id)
+	}
+
+	// the next ID is persisted across restarts
+	s = newTestFileTaskStore(t, dir)
+	id, err := s.NextID()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, id)
+
+	// and is never behind the stored tasks
+	assert.NoError(t, s.Save(&types.Task{ID: 10}))
+	s = newTestFileTaskStore(t, dir)
+	id, err = s.NextID()
+	assert.NoError(t, err)
+	assert.Equal(t, 11, id)
+}
+
+func TestFileTaskStoreSaveGetListRemove(t *testing.T) {
+	dir := newTestTaskStoreDir(t)
+	defer os.RemoveAll(dir)
+
+	s := newTestFileTaskStore(t, dir)
+
+	assert.NoError(t, s.Save(&types.Task{
+		ID:           1,
+		QueueTime:    1491238950,
+		CompleteTime: 1491238951,
+		State:        types.TaskStateSuccess,
+		Result:       map[string]string{"id": "vol-000"},
+	}))
+	assert.NoError(t, s.Save(&types.Task{
+		ID:           2,
+		QueueTime:    1491238950,
+		CompleteTime: 1491238951,
+		State:        types.TaskStateError,
+		Error:        goof.New("device busy"),
+	}))
+
+	tk, err := s.Get(1)
+	assert.NoError(t, err)
+	if !assert.NotNil(t, tk) {
+		t.FailNow()
+	}
+	assert.Equal(t, types.TaskStateSuccess, tk.State)
+	assert.Equal(t, int64(1491238951), tk.CompleteTime)
+	assert.Equal(t, `{"id":"vol-000"}`, string(tk.Result.(json.RawMessage)))
+	assert.Nil(t, tk.Error)
+
+	tk, err = s.Get(2)
+	assert.NoError(t, err)
+	if !assert.NotNil(t, tk) {
+		t.FailNow()
+	}
+	assert.Equal(t, types.TaskStateError, tk.State)
+	assert.EqualError(t, tk.Error, "device busy")
+
+	tasks, err := s.List()
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+
+	assert.NoError(t, s.Remove(1))
+	tk, err = s.Get(1)
+	assert.NoError(t, err)
+	assert.Nil(t, tk)
+	assert.NoError(t, s.Remove(1))
+
+	tasks, err = s.List()
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+}
+
+func TestReconcileAndGCStoredTasks(t *testing.T) {
+	dir := newTestTaskStoreDir(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		ctx = context.Background()
+		now = time.Now()
+		s   = newTestFileTaskStore(t, dir)
+	)
+
+	for _, tk := range []*types.Task{
+		{ID: 1, State: types.TaskStateQueued},
+		{ID: 2, State: types.TaskStateRunning},
+		{
+			ID:           3,
+			State:        types.TaskStateSuccess,
+			CompleteTime: now.Add(-2 * time.Hour).Unix(),
+		},
+		{
+			ID:           4,
+			State:        types.TaskStateSuccess,
+			CompleteTime: now.Add(-10 * time.Minute).Unix(),
+		},
+	} {
+		assert.NoError(t, s.Save(tk))
+	}
+
+	ts := &globalTaskService{
+		tasks:     map[int]*task{},
+		store:     s,
+		retention: time.Hour,
+	}
+	assert.NoError(t, ts.reconcileStoredTasks(ctx))
+	assert.NoError(t, ts.gcStoredTasks(ctx))
+
+	// the tasks interrupted by the restart are failed and retained
+	for _, id := range []int{1, 2} {
+		tk, err := s.Get(id)
+		assert.NoError(t, err)
+		if assert.NotNil(t, tk) {
+			assert.Equal(t, types.TaskStateError, tk.State)
+			assert.NotEqual(t, int64(0), tk.CompleteTime)
+		}
+	}
+
+	// the task whose retention elapsed is removed
+	tk, err := s.Get(3)
+	assert.NoError(t, err)
+	assert.Nil(t, tk)
+
+	// while the task whose retention has not elapsed is kept
+	tk, err = s.Get(4)
+	assert.NoError(t, err)
+	assert.NotNil(t, tk)
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+type testReservation struct {
+	committed bool
+	cancelled bool
+}
+
+func (r *testReservation) Commit(ctx types.Context, v *types.Volume) error {
+	r.committed = true
+	return nil
+}
+
+func (r *testReservation) Cancel() {
+	r.cancelled = true
+}
+
+func newTestTask(ctx types.Context, run types.TaskRunFunc) *task {
+	t := &task{
+		Task: types.Task{
+			ID:        1,
+			QueueTime: time.Now().Unix(),
+			State:     types.TaskStateQueued,
+		},
+		runFunc: run,
+		done:    make(chan int),
+	}
+	t.ctx, t.cancel = context.WithCancel(ctx)
+	return t
+}
+
+func waitTask(t *testing.T, tk *task) {
+	select {
+	case <-tk.done:
+	case <-time.After(time.Duration(5) * time.Second):
+		t.Fatal("timed out waiting for task")
+	}
+}
+
+func TestCancelQueuedTask(t *testing.T) {
+	res := &testReservation{}
+	ctx := context.Background().WithValue(context.QuotaReservationKey, res)
+
+	ran := false
+	tk := newTestTask(ctx, func(ctx types.Context) (interface{}, error) {
+		ran = true
+		return nil, nil
+	})
+
+	task, err := tk.cancelTask()
+	assert.NoError(t, err)
+	assert.Equal(t, types.TaskStateCancelled, task.State)
+	assert.Equal(t, types.ErrTaskCancelled, task.Error)
+	waitTask(t, tk)
+
+	// the quota reserved for the task is released
+	assert.True(t, res.cancelled)
+	assert.False(t, res.committed)
+
+	// the task is not run once it is dequeued
+	execTask(tk)
+	assert.False(t, ran)
+	assert.Equal(t, types.TaskStateCancelled, tk.State)
+
+	// cancelling the task again is a no-op
+	task, err = tk.cancelTask()
+	assert.NoError(t, err)
+	assert.Equal(t, types.TaskStateCancelled, task.State)
+}
+
+func TestCancelRunningTask(t *testing.T) {
+	running := make(chan bool)
+	tk := newTestTask(
+		context.Background(),
+		func(ctx types.Context) (interface{}, error) {
+			close(running)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+	go execTask(tk)
+	<-running
+
+	task, err := tk.cancelTask()
+	assert.NoError(t, err)
+	assert.Equal(t, types.TaskStateRunning, task.State)
+	waitTask(t, tk)
+
+	tk.Lock()
+	assert.Equal(t, types.TaskStateCancelled, tk.State)
+	assert.Equal(t, types.ErrTaskCancelled, tk.Error)
+	tk.Unlock()
+}
+
+// TestCancelRunningTaskRace verifies the outcome of a running task whose run
+// function returns at the same time the task is cancelled.
+func TestCancelRunningTaskRace(t *testing.T) {
+
+	// a task that fails after it was cancelled is recorded as cancelled
+	running := make(chan bool)
+	cancelled := make(chan bool)
+	tk := newTestTask(
+		context.Background(),
+		func(ctx types.Context) (interface{}, error) {
+			close(running)
+			<-cancelled
+			return nil, errors.New("device busy")
+		})
+
+	go execTask(tk)
+	<-running
+	_, err := tk.cancelTask()
+	assert.NoError(t, err)
+	close(cancelled)
+	waitTask(t, tk)
+
+	tk.Lock()
+	assert.Equal(t, types.TaskStateCancelled, tk.State)
+	assert.Equal(t, types.ErrTaskCancelled, tk.Error)
+	tk.Unlock()
+
+	// a task that succeeds even though it was cancelled is recorded as
+	// successful since its result stands
+	running = make(chan bool)
+	cancelled = make(chan bool)
+	tk = newTestTask(
+		context.Background(),
+		func(ctx types.Context) (interface{}, error) {
+			close(running)
+			<-cancelled
+			return "vol-000", nil
+		})
+
+	go execTask(tk)
+	<-running
+	_, err = tk.cancelTask()
+	assert.NoError(t, err)
+	close(cancelled)
+	waitTask(t, tk)
+
+	tk.Lock()
+	assert.Equal(t, types.TaskStateSuccess, tk.State)
+	assert.Equal(t, "vol-000", tk.Result)
+	tk.Unlock()
+
+	// a task that completed may not be cancelled
+	_, err = tk.cancelTask()
+	assert.Error(t, err)
+	assert.IsType(t, &types.ErrTaskCompleted{}, err)
+}
+
+func TestTaskFailsWithoutCancel(t *testing.T) {
+	tk := newTestTask(
+		context.Background(),
+		func(ctx types.Context) (interface{}, error) {
+			return nil, errors.New("device busy")
+		})
+
+	execTask(tk)
+	waitTask(t, tk)
+	assert.Equal(t, types.TaskStateError, tk.State)
+	assert.EqualError(t, tk.Error, "device busy")
+}
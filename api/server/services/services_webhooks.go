@@ -0,0 +1,308 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// webhookQueueSize is the number of events queued for delivery to a target.
+// Events published to a target with a full queue are dead-lettered.
+const webhookQueueSize = 1000
+
+// webhooks delivers the server's events to the configured webhook targets.
+type webhooks struct {
+	targets        []*webhookTarget
+	client         *http.Client
+	retries        int
+	backoff        time.Duration
+	maxBackoff     time.Duration
+	deadLetterPath string
+	deadLetterLock sync.Mutex
+}
+
+// webhookTarget is an endpoint to which matching events are delivered in
+// the order in which they were published.
+type webhookTarget struct {
+	name       string
+	url        string
+	secret     []byte
+	events     []types.EventType
+	taskStates []types.TaskState
+	queue      chan *types.Event
+}
+
+// webhookDeadLetter is the record of an event that could not be delivered.
+type webhookDeadLetter struct {
+	Time     int64        `json:"time"`
+	Target   string       `json:"target"`
+	URL      string       `json:"url"`
+	Attempts int          `json:"attempts"`
+	Error    string       `json:"error"`
+	Event    *types.Event `json:"event"`
+}
+
+func (wh *webhooks) Init(ctx types.Context, config gofig.Config) error {
+	targets, ok := config.Get(
+		types.ConfigServerWebhooksTargets).(map[string]interface{})
+	if !ok || len(targets) == 0 {
+		return nil
+	}
+
+	wh.retries = config.GetInt(types.ConfigServerWebhooksRetries)
+	wh.backoff = parseDuration(
+		config.GetString(types.ConfigServerWebhooksBackoff), time.Second)
+	wh.maxBackoff = parseDuration(
+		config.GetString(types.ConfigServerWebhooksMaxBackoff), time.Minute)
+	wh.client = &http.Client{
+		Timeout: parseDuration(
+			config.GetString(types.ConfigServerWebhooksTimeout),
+			time.Duration(10)*time.Second),
+	}
+
+	wh.deadLetterPath = config.GetString(
+		types.ConfigServerWebhooksDeadLetterPath)
+	if wh.deadLetterPath != "" {
+		if err := os.MkdirAll(path.Dir(wh.deadLetterPath), 0755); err != nil {
+			return goof.WithFieldE(
+				"path", wh.deadLetterPath,
+				"error creating webhook dead-letter log dir", err)
+		}
+	}
+
+	for name := range targets {
+		key := fmt.Sprintf("%s.%s", types.ConfigServerWebhooksTargets, name)
+		t := &webhookTarget{
+			name:   name,
+			url:    config.GetString(key + ".url"),
+			secret: []byte(config.GetString(key + ".secret")),
+			queue:  make(chan *types.Event, webhookQueueSize),
+		}
+		if t.url == "" {
+			return goof.WithField("target", name, "webhook url is required")
+		}
+		if len(t.secret) == 0 {
+			return goof.WithField(
+				"target", name, "webhook secret is required")
+		}
+		for _, e := range config.GetStringSlice(key + ".events") {
+			t.events = append(t.events, types.EventType(e))
+		}
+		for _, s := range config.GetStringSlice(key + ".taskStates") {
+			t.taskStates = append(t.taskStates, types.TaskState(s))
+		}
+		wh.targets = append(wh.targets, t)
+
+		go wh.deliverAll(ctx, t)
+
+		ctx.WithFields(log.Fields{
+			"target": name,
+			"url":    t.url,
+			"events": t.events,
+		}).Info("configured webhook")
+	}
+
+	return nil
+}
+
+// match returns a flag indicating whether the event should be delivered to
+// the target.
+func (t *webhookTarget) match(ev *types.Event) bool {
+	if len(t.events) > 0 {
+		ok := false
+		for _, e := range t.events {
+			if e == ev.Type {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	if ev.Type == types.EventTaskState && len(t.taskStates) > 0 {
+		for _, s := range t.taskStates {
+			if s == ev.TaskState {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (wh *webhooks) notify(ctx types.Context, ev *types.Event) {
+	for _, t := range wh.targets {
+		if !t.match(ev) {
+			continue
+		}
+		select {
+		case t.queue <- ev:
+		default:
+			wh.deadLetter(ctx, t, ev, 0, goof.New("webhook queue full"))
+		}
+	}
+}
+
+func (wh *webhooks) deliverAll(ctx types.Context, t *webhookTarget) {
+	for ev := range t.queue {
+		wh.deliver(ctx, t, ev)
+	}
+}
+
+// deliver posts the event to the target, retrying failed attempts with an
+// exponential backoff and jitter. The event is dead-lettered once the
+// retries are exhausted.
+func (wh *webhooks) deliver(
+	ctx types.Context, t *webhookTarget, ev *types.Event) {
+
+	body, err := json.Marshal(ev)
+	if err != nil {
+		wh.deadLetter(ctx, t, ev, 0, err)
+		return
+	}
+
+	backoff := wh.backoff
+	for attempt := 1; ; attempt++ {
+		if err = t.post(wh.client, ev, body); err == nil {
+			return
+		}
+		if attempt > wh.retries {
+			wh.deadLetter(ctx, t, ev, attempt, err)
+			return
+		}
+		wait := jitter(backoff)
+		ctx.WithFields(log.Fields{
+			"target":  t.name,
+			"eventID": ev.ID,
+			"attempt": attempt,
+			"backoff": wait,
+		}).WithError(err).Warn("error delivering webhook")
+		time.Sleep(wait)
+		if backoff *= 2; backoff > wh.maxBackoff {
+			backoff = wh.maxBackoff
+		}
+	}
+}
+
+// jitter returns a random duration between half of the given duration and
+// the duration so that the retries of the deliveries that failed together
+// are spread out.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+func (t *webhookTarget) post(
+	client *http.Client, ev *types.Event, body []byte) error {
+
+	req, err := http.NewRequest(http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	// the timestamp is signed with the body so that a receiver may reject
+	// a delivery that is replayed after the receiver's tolerance
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(types.WebhookEventHeader, string(ev.Type))
+	req.Header.Set(types.WebhookTimestampHeader, ts)
+	req.Header.Set(types.WebhookSignatureHeader, "sha256="+t.sign(ts, body))
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(ioutil.Discard, res.Body)
+	res.Body.Close()
+
+	if res.StatusCode > 299 {
+		return goof.WithField("status", res.StatusCode, "webhook rejected")
+	}
+	return nil
+}
+
+// sign returns the hex-encoded HMAC-SHA256 of the timestamp, a period, and
+// the body keyed by the target's secret.
+func (t *webhookTarget) sign(ts string, body []byte) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// deadLetter appends the record of an undeliverable event to the dead-letter
+// log.
+func (wh *webhooks) deadLetter(
+	ctx types.Context,
+	t *webhookTarget,
+	ev *types.Event,
+	attempts int,
+	err error) {
+
+	ctx.WithFields(log.Fields{
+		"target":   t.name,
+		"eventID":  ev.ID,
+		"attempts": attempts,
+	}).WithError(err).Error("webhook delivery failed")
+
+	if wh.deadLetterPath == "" {
+		return
+	}
+
+	buf, merr := json.Marshal(&webhookDeadLetter{
+		Time:     time.Now().Unix(),
+		Target:   t.name,
+		URL:      t.url,
+		Attempts: attempts,
+		Error:    err.Error(),
+		Event:    ev,
+	})
+	if merr != nil {
+		ctx.WithError(merr).Error("error marshaling webhook dead letter")
+		return
+	}
+	buf = append(buf, '\n')
+
+	wh.deadLetterLock.Lock()
+	defer wh.deadLetterLock.Unlock()
+
+	f, ferr := os.OpenFile(
+		wh.deadLetterPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if ferr != nil {
+		ctx.WithError(ferr).Error("error opening webhook dead-letter log")
+		return
+	}
+	defer f.Close()
+	if _, ferr := f.Write(buf); ferr != nil {
+		ctx.WithError(ferr).Error("error writing webhook dead-letter log")
+	}
+}
+
+func parseDuration(val string, def time.Duration) time.Duration {
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return def
+	}
+	return d
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	gofigCore "github.com/akutz/gofig"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func TestWebhooksInitSecretRequired(t *testing.T) {
+	config := gofigCore.New()
+	err := config.ReadConfig(bytes.NewReader([]byte(`
+libstorage:
+  server:
+    webhooks:
+      deadLetterPath: ""
+      targets:
+        test:
+          url: http://localhost:8080/events
+`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wh := &webhooks{}
+	err = wh.Init(context.Background(), config)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "webhook secret is required")
+	}
+}
+
+func TestWebhookPostSigned(t *testing.T) {
+	var req *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			req = r
+			body, _ = ioutil.ReadAll(r.Body)
+		}))
+	defer srv.Close()
+
+	target := &webhookTarget{name: "test", url: srv.URL, secret: []byte("s")}
+	ev := &types.Event{Type: types.EventVolumeCreated}
+	err := target.post(http.DefaultClient, ev, []byte(`{"id":1}`))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	// the signature is that of the timestamp and the body
+	ts := req.Header.Get(types.WebhookTimestampHeader)
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	assert.NoError(t, err)
+	assert.True(t, time.Now().Unix()-sec < 60)
+
+	mac := hmac.New(sha256.New, []byte("s"))
+	mac.Write([]byte(ts + "." + string(body)))
+	assert.Equal(t,
+		"sha256="+hex.EncodeToString(mac.Sum(nil)),
+		req.Header.Get(types.WebhookSignatureHeader))
+}
+
+func TestWebhookJitter(t *testing.T) {
+	backoff := time.Duration(100) * time.Millisecond
+	for i := 0; i < 100; i++ {
+		wait := jitter(backoff)
+		assert.True(t, wait >= backoff/2 && wait <= backoff)
+	}
+	assert.Equal(t, time.Duration(0), jitter(0))
+}
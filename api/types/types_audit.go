@@ -0,0 +1,141 @@
+package types
+
+import "strings"
+
+// AuditOutcome is the outcome of an audited API call.
+type AuditOutcome string
+
+const (
+	// AuditSuccess indicates the API call succeeded.
+	AuditSuccess AuditOutcome = "success"
+
+	// AuditAccepted indicates the API call's task was accepted for
+	// asynchronous execution.
+	AuditAccepted AuditOutcome = "accepted"
+
+	// AuditFailure indicates the API call failed.
+	AuditFailure AuditOutcome = "failure"
+)
+
+// AuditRecord is the record of a mutating API call.
+type AuditRecord struct {
+
+	// Time is the epoch, in seconds, at which the call was received.
+	Time int64 `json:"time" yaml:"time"`
+
+	// Route is the name of the route that handled the call.
+	Route string `json:"route" yaml:"route"`
+
+	// Method is the call's HTTP method.
+	Method string `json:"method" yaml:"method"`
+
+	// Service is the name of the storage service targeted by the call.
+	Service string `json:"service,omitempty" yaml:"service,omitempty"`
+
+	// VolumeID is the ID of the volume targeted or created by the call.
+	VolumeID string `json:"volumeID,omitempty" yaml:"volumeID,omitempty"`
+
+	// SnapshotID is the ID of the snapshot targeted or created by the call.
+	SnapshotID string `json:"snapshotID,omitempty" yaml:"snapshotID,omitempty"`
+
+	// TaskID is the ID of the task that executed the call.
+	TaskID *int `json:"taskID,omitempty" yaml:"taskID,omitempty"`
+
+	// Subject is the subject of the call's auth token.
+	Subject string `json:"subject,omitempty" yaml:"subject,omitempty"`
+
+	// TransactionID is the ID of the call's transaction.
+	TransactionID string `json:"txID,omitempty" yaml:"txID,omitempty"`
+
+	// InstanceID is the ID of the instance from which the call was made.
+	InstanceID string `json:"instanceID,omitempty" yaml:"instanceID,omitempty"`
+
+	// Outcome is the outcome of the call.
+	Outcome AuditOutcome `json:"outcome" yaml:"outcome"`
+
+	// Status is the HTTP status of the call's response.
+	Status int `json:"status" yaml:"status"`
+
+	// Error is the error that caused the call to fail.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+
+	// Duration is the time, in milliseconds, taken to handle the call.
+	Duration int64 `json:"duration" yaml:"duration"`
+
+	// TaskStatus is the HTTP status with which the result of the call's task
+	// is written. It is used to record the outcome of asynchronous calls.
+	TaskStatus int `json:"-" yaml:"-"`
+}
+
+// AuditQuery describes the audit records to return from a query. Empty
+// fields match all records.
+type AuditQuery struct {
+
+	// Route matches the records' route names.
+	Route string
+
+	// Service matches the records' service names.
+	Service string
+
+	// Subject matches the records' subjects.
+	Subject string
+
+	// VolumeID matches the records' volume IDs.
+	VolumeID string
+
+	// SnapshotID matches the records' snapshot IDs.
+	SnapshotID string
+
+	// Outcome matches the records' outcomes.
+	Outcome AuditOutcome
+
+	// Since is the epoch, in seconds, before which records do not match.
+	Since int64
+
+	// Limit is the maximum number of records to return. A value of zero
+	// indicates no limit.
+	Limit int
+}
+
+// Match returns a flag indicating whether the record matches the query.
+func (q *AuditQuery) Match(r *AuditRecord) bool {
+	if q.Since > 0 && r.Time < q.Since {
+		return false
+	}
+	if q.Outcome != "" && q.Outcome != r.Outcome {
+		return false
+	}
+	for _, f := range [][2]string{
+		{q.Route, r.Route},
+		{q.Service, r.Service},
+		{q.Subject, r.Subject},
+		{q.VolumeID, r.VolumeID},
+		{q.SnapshotID, r.SnapshotID},
+	} {
+		if f[0] != "" && !strings.EqualFold(f[0], f[1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewAuditSink is a function that constructs a new AuditSink.
+type NewAuditSink func() AuditSink
+
+// AuditSink is the interface implemented by types that durably record the
+// audit records of a libStorage server.
+type AuditSink interface {
+	Driver
+
+	// Write records the audit record.
+	Write(ctx Context, record *AuditRecord) error
+}
+
+// AuditReader is an optional interface that may be implemented by an
+// AuditSink in order to read back the most recent records it has written.
+type AuditReader interface {
+
+	// Recent returns up to n of the most recently written records in the
+	// order in which they were written.
+	Recent(n int) ([]*AuditRecord, error)
+}
@@ -18,6 +18,9 @@ type AuthToken struct {
 
 	// Encoded is the encoded JWT string.
 	Encoded string `json:"enc"`
+
+	// Roles are the roles assigned to the subject by the token's roles claim.
+	Roles []string `json:"roles,omitempty"`
 }
 
 // String returns the subject of the security token.
@@ -42,4 +45,30 @@ type AuthConfig struct {
 
 	// Alg is the cryptographic algorithm used to sign and verify the token.
 	Alg string
+
+	// Roles maps the names of roles to the names of the routes the roles may
+	// access. These definitions take precedence over the built-in roles.
+	Roles map[string][]string
+
+	// Subjects maps tokens to the names of the roles assigned to them.
+	Subjects map[string][]string
+
+	// DefaultRoles are the roles of the tokens that are not assigned any
+	// roles. If there are no default roles then such tokens may access all
+	// routes.
+	DefaultRoles []string
 }
+
+const (
+	// AuthRoleReader is the built-in role that may access the routes that do
+	// not modify any resources.
+	AuthRoleReader = "reader"
+
+	// AuthRoleOperator is the built-in role that may access the routes of
+	// the reader role as well as the routes that create, modify, attach, and
+	// detach resources. The operator role may not remove resources.
+	AuthRoleOperator = "operator"
+
+	// AuthRoleAdmin is the built-in role that may access all routes.
+	AuthRoleAdmin = "admin"
+)
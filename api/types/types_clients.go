@@ -88,6 +88,11 @@ type APIClient interface {
 	// LogResponses enables or disables the logging of client HTTP responses.
 	LogResponses(enabled bool)
 
+	// SetRetryPolicy sets the policy with which the client retries the
+	// requests that fail for transient reasons. A nil policy restores the
+	// default policy.
+	SetRetryPolicy(policy *RetryPolicy)
+
 	// Root returns a list of root resources.
 	Root(ctx Context) ([]string, error)
 
@@ -103,6 +108,35 @@ type APIClient interface {
 	// ServiceInspect returns information about a service.
 	ServiceInspect(ctx Context, name string) (*ServiceInfo, error)
 
+	// ServiceCreate adds a service to the server. This requires the server's
+	// admin role.
+	ServiceCreate(
+		ctx Context, request *ServiceCreateRequest) (*ServiceInfo, error)
+
+	// ServiceUpdate reconfigures a service. This requires the server's admin
+	// role.
+	ServiceUpdate(
+		ctx Context,
+		name string,
+		request *ServiceUpdateRequest) (*ServiceInfo, error)
+
+	// ServiceRemove drains a service and removes it from the server. This
+	// requires the server's admin role.
+	ServiceRemove(ctx Context, name string) error
+
+	// Quotas returns the quotas and usage of the client's auth subject for
+	// all Services.
+	Quotas(ctx Context) (ServiceQuotaMap, error)
+
+	// Audit returns the recent audit records that match the query, newest
+	// first.
+	Audit(ctx Context, query *AuditQuery) ([]*AuditRecord, error)
+
+	// Events subscribes to the server's events that match the filter. The
+	// returned channel is closed when the context is cancelled or the
+	// connection to the server is lost.
+	Events(ctx Context, filter *EventFilter) (<-chan *Event, error)
+
 	// Volumes returns a list of all Volumes for all Services.
 	Volumes(
 		ctx Context,
@@ -114,6 +148,23 @@ type APIClient interface {
 		service string,
 		attachments VolumeAttachmentsTypes) (VolumeMap, error)
 
+	// VolumesPage returns a page of the Volumes for all Services in the
+	// order in which they are sorted and the continuation token for the
+	// next page.
+	VolumesPage(
+		ctx Context,
+		attachments VolumeAttachmentsTypes,
+		page *PageOpts) ([]*ServiceVolume, string, error)
+
+	// VolumesByServicePage returns a page of the Volumes for a service in
+	// the order in which they are sorted and the continuation token for the
+	// next page.
+	VolumesByServicePage(
+		ctx Context,
+		service string,
+		attachments VolumeAttachmentsTypes,
+		page *PageOpts) ([]*Volume, string, error)
+
 	// VolumeInspect gets information about a single volume by ID.
 	VolumeInspect(
 		ctx Context,
@@ -144,6 +195,12 @@ type APIClient interface {
 		service, volumeID string,
 		request *VolumeCopyRequest) (*Volume, error)
 
+	// VolumeResize resizes a single volume.
+	VolumeResize(
+		ctx Context,
+		service, volumeID string,
+		request *VolumeResizeRequest) (*Volume, error)
+
 	// VolumeRemove removes a single volume.
 	VolumeRemove(
 		ctx Context,
@@ -189,6 +246,20 @@ type APIClient interface {
 	SnapshotsByService(
 		ctx Context, service string) (SnapshotMap, error)
 
+	// SnapshotsPage returns a page of the Snapshots for all Services in the
+	// order in which they are sorted and the continuation token for the
+	// next page.
+	SnapshotsPage(
+		ctx Context, page *PageOpts) ([]*ServiceSnapshot, string, error)
+
+	// SnapshotsByServicePage returns a page of the Snapshots for a single
+	// service in the order in which they are sorted and the continuation
+	// token for the next page.
+	SnapshotsByServicePage(
+		ctx Context,
+		service string,
+		page *PageOpts) ([]*Snapshot, string, error)
+
 	// SnapshotInspect gets information about a single snapshot.
 	SnapshotInspect(
 		ctx Context,
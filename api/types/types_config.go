@@ -90,9 +90,48 @@ const (
 	// ConfigExecutorNoDownload is a config key.
 	ConfigExecutorNoDownload = ConfigRoot + ".executor.disableDownload"
 
+	// ConfigExecutorPublicKey is a config key.
+	ConfigExecutorPublicKey = ConfigRoot + ".executor.publicKey"
+
+	// ConfigExecutorSocket is a config key.
+	ConfigExecutorSocket = ConfigRoot + ".executor.socket"
+
+	// ConfigExecutorLockTimeout is a config key.
+	ConfigExecutorLockTimeout = ConfigRoot + ".executor.lock.timeout"
+
+	// ConfigExecutorLockShared is a config key.
+	ConfigExecutorLockShared = ConfigRoot + ".executor.lock.shared"
+
 	// ConfigClientCacheInstanceID is a config key.
 	ConfigClientCacheInstanceID = ConfigClient + ".cache.instanceID"
 
+	// ConfigClientEndpointsRoundRobin is a config key.
+	ConfigClientEndpointsRoundRobin = ConfigClient + ".endpoints.roundRobin"
+
+	// ConfigClientEndpointsDowntime is a config key.
+	ConfigClientEndpointsDowntime = ConfigClient + ".endpoints.downtime"
+
+	// ConfigClientRetry is a config key.
+	ConfigClientRetry = ConfigClient + ".retry"
+
+	// ConfigClientRetryMaxAttempts is a config key.
+	ConfigClientRetryMaxAttempts = ConfigClientRetry + ".maxAttempts"
+
+	// ConfigClientRetryBackoff is a config key.
+	ConfigClientRetryBackoff = ConfigClientRetry + ".backoff"
+
+	// ConfigClientRetryMaxBackoff is a config key.
+	ConfigClientRetryMaxBackoff = ConfigClientRetry + ".maxBackoff"
+
+	// ConfigClientRetryStatusCodes is a config key.
+	ConfigClientRetryStatusCodes = ConfigClientRetry + ".statusCodes"
+
+	// ConfigClientRetryErrors is a config key.
+	ConfigClientRetryErrors = ConfigClientRetry + ".errors"
+
+	// ConfigClientRetryMutating is a config key.
+	ConfigClientRetryMutating = ConfigClientRetry + ".mutating"
+
 	// ConfigTLS is a config key.
 	ConfigTLS = ConfigRoot + ".tls"
 
@@ -142,6 +181,121 @@ const (
 	// ConfigServerTasksLogTimeout is a config key.
 	ConfigServerTasksLogTimeout = ConfigServerTasks + ".logTimeout"
 
+	// ConfigServerTasksWorkers is a config key.
+	ConfigServerTasksWorkers = ConfigServerTasks + ".workers"
+
+	// ConfigServerTasksStore is a config key.
+	ConfigServerTasksStore = ConfigServerTasks + ".store"
+
+	// ConfigServerTasksStoreType is a config key.
+	ConfigServerTasksStoreType = ConfigServerTasksStore + ".type"
+
+	// ConfigServerTasksStorePath is a config key.
+	ConfigServerTasksStorePath = ConfigServerTasksStore + ".path"
+
+	// ConfigServerTasksStoreRetention is a config key.
+	ConfigServerTasksStoreRetention = ConfigServerTasksStore + ".retention"
+
+	// ConfigServerQuotas is a config key.
+	ConfigServerQuotas = ConfigServer + ".quotas"
+
+	// ConfigServerQuotasVolumes is a config key.
+	ConfigServerQuotasVolumes = ConfigServerQuotas + ".volumes"
+
+	// ConfigServerQuotasSize is a config key.
+	ConfigServerQuotasSize = ConfigServerQuotas + ".size"
+
+	// ConfigServerQuotasMaxVolumeSize is a config key.
+	ConfigServerQuotasMaxVolumeSize = ConfigServerQuotas + ".maxVolumeSize"
+
+	// ConfigServerQuotasSubjects is a config key.
+	ConfigServerQuotasSubjects = ConfigServerQuotas + ".subjects"
+
+	// ConfigServerQuotasPath is a config key.
+	ConfigServerQuotasPath = ConfigServerQuotas + ".path"
+
+	// ConfigServerAudit is a config key.
+	ConfigServerAudit = ConfigServer + ".audit"
+
+	// ConfigServerAuditDisabled is a config key.
+	ConfigServerAuditDisabled = ConfigServerAudit + ".disabled"
+
+	// ConfigServerAuditRecent is a config key.
+	ConfigServerAuditRecent = ConfigServerAudit + ".recent"
+
+	// ConfigServerAuditSink is a config key.
+	ConfigServerAuditSink = ConfigServerAudit + ".sink"
+
+	// ConfigServerAuditSinkType is a config key.
+	ConfigServerAuditSinkType = ConfigServerAuditSink + ".type"
+
+	// ConfigServerAuditSinkPath is a config key.
+	ConfigServerAuditSinkPath = ConfigServerAuditSink + ".path"
+
+	// ConfigServerAuditSinkMaxSize is a config key.
+	ConfigServerAuditSinkMaxSize = ConfigServerAuditSink + ".maxSize"
+
+	// ConfigServerAuditSinkMaxBackups is a config key.
+	ConfigServerAuditSinkMaxBackups = ConfigServerAuditSink + ".maxBackups"
+
+	// ConfigServerHealth is a config key.
+	ConfigServerHealth = ConfigServer + ".health"
+
+	// ConfigServerHealthTimeout is a config key.
+	ConfigServerHealthTimeout = ConfigServerHealth + ".timeout"
+
+	// ConfigServerHealthCritical is a config key.
+	ConfigServerHealthCritical = ConfigServerHealth + ".critical"
+
+	// ConfigServerWebhooks is a config key.
+	ConfigServerWebhooks = ConfigServer + ".webhooks"
+
+	// ConfigServerWebhooksTargets is a config key.
+	ConfigServerWebhooksTargets = ConfigServerWebhooks + ".targets"
+
+	// ConfigServerWebhooksRetries is a config key.
+	ConfigServerWebhooksRetries = ConfigServerWebhooks + ".retries"
+
+	// ConfigServerWebhooksBackoff is a config key.
+	ConfigServerWebhooksBackoff = ConfigServerWebhooks + ".backoff"
+
+	// ConfigServerWebhooksMaxBackoff is a config key.
+	ConfigServerWebhooksMaxBackoff = ConfigServerWebhooks + ".maxBackoff"
+
+	// ConfigServerWebhooksTimeout is a config key.
+	ConfigServerWebhooksTimeout = ConfigServerWebhooks + ".timeout"
+
+	// ConfigServerWebhooksDeadLetterPath is a config key.
+	ConfigServerWebhooksDeadLetterPath = ConfigServerWebhooks +
+		".deadLetterPath"
+
+	// ConfigServerRateLimit is a config key.
+	ConfigServerRateLimit = ConfigServer + ".rateLimit"
+
+	// ConfigServerRateLimitKey is a config key.
+	ConfigServerRateLimitKey = ConfigServerRateLimit + ".key"
+
+	// ConfigServerRateLimitRate is a config key.
+	ConfigServerRateLimitRate = ConfigServerRateLimit + ".rate"
+
+	// ConfigServerRateLimitBurst is a config key.
+	ConfigServerRateLimitBurst = ConfigServerRateLimit + ".burst"
+
+	// ConfigServerRateLimitRoutes is a config key.
+	ConfigServerRateLimitRoutes = ConfigServerRateLimit + ".routes"
+
+	// ConfigServerExecutorsSignatures is a config key.
+	ConfigServerExecutorsSignatures = ConfigServer + ".executors.signatures"
+
+	// ConfigServerIdempotency is a config key.
+	ConfigServerIdempotency = ConfigServer + ".idempotency"
+
+	// ConfigServerIdempotencyDisabled is a config key.
+	ConfigServerIdempotencyDisabled = ConfigServerIdempotency + ".disabled"
+
+	// ConfigServerIdempotencyWindow is a config key.
+	ConfigServerIdempotencyWindow = ConfigServerIdempotency + ".window"
+
 	// ConfigClientAuth is a config key.
 	ConfigClientAuth = ConfigClient + ".auth"
 
@@ -165,4 +319,13 @@ const (
 
 	// ConfigServerAuthDisabled is a config key.
 	ConfigServerAuthDisabled = ConfigServerAuth + ".disabled"
+
+	// ConfigServerAuthRoles is a config key.
+	ConfigServerAuthRoles = ConfigServerAuth + ".roles"
+
+	// ConfigServerAuthSubjects is a config key.
+	ConfigServerAuthSubjects = ConfigServerAuth + ".subjects"
+
+	// ConfigServerAuthDefaultRoles is a config key.
+	ConfigServerAuthDefaultRoles = ConfigServerAuth + ".defaultRoles"
 )
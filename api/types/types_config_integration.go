@@ -16,6 +16,9 @@ const (
 	//ConfigIgVolOpsMountPreempt is a config key.
 	ConfigIgVolOpsMountPreempt = ConfigIgVolOpsMount + ".preempt"
 
+	//ConfigIgVolOpsMountGrowFS is a config key.
+	ConfigIgVolOpsMountGrowFS = ConfigIgVolOpsMount + ".growFS"
+
 	//ConfigIgVolOpsMountPath is a config key.
 	ConfigIgVolOpsMountPath = ConfigIgVolOpsMount + ".path"
 
@@ -28,6 +31,9 @@ const (
 	//ConfigIgVolOpsMountRetryWait is a config key.
 	ConfigIgVolOpsMountRetryWait = ConfigIgVolOpsMount + ".retryWait"
 
+	//ConfigIgVolOpsMountRefsPath is a config key.
+	ConfigIgVolOpsMountRefsPath = ConfigIgVolOpsMount + ".refsPath"
+
 	//ConfigIgVolOpsUnmount is a config key.
 	ConfigIgVolOpsUnmount = ConfigIgVolOps + ".unmount"
 
@@ -46,8 +46,61 @@ const (
 
 	// LSXCmdMounts is the command for getting a list of mount info objects.
 	LSXCmdMounts = "mounts"
+
+	// LSXCmdServe is the command for running the executor as a daemon that
+	// answers the other commands over a unix socket.
+	LSXCmdServe = "serve"
 )
 
+// LSXCmdReadOnly returns a flag indicating whether the executor command only
+// reads the host's state and so may run alongside other such commands.
+func LSXCmdReadOnly(cmd string) bool {
+	switch cmd {
+	case LSXCmdSupported,
+		LSXCmdInstanceID,
+		LSXCmdLocalDevices,
+		LSXCmdWaitForDevice,
+		LSXCmdMounts:
+		return true
+	}
+	return false
+}
+
+// LSXRequest is a request sent to an executor daemon.
+type LSXRequest struct {
+
+	// Args are the executor's arguments, ex. ["vfs", "instanceID"].
+	Args []string `json:"args"`
+
+	// Checksum is the SHA-256 checksum of the executor the client would
+	// otherwise invoke. The daemon rejects the request unless it is running
+	// the same executor.
+	Checksum string `json:"checksum"`
+
+	// ConfigChecksum is the checksum of the client's configuration. The
+	// daemon rejects the request unless its configuration is the same.
+	ConfigChecksum string `json:"configChecksum"`
+}
+
+// LSXResponse is the response from an executor daemon. The response
+// contains the same output and exit code as invoking the executor with the
+// request's arguments.
+type LSXResponse struct {
+
+	// ExitCode is the command's exit code.
+	ExitCode int `json:"exitCode"`
+
+	// Stdout is the command's output.
+	Stdout []byte `json:"stdout,omitempty"`
+
+	// Stderr is the command's error output.
+	Stderr string `json:"stderr,omitempty"`
+
+	// Rejected is the reason the daemon did not execute the request, if
+	// any. The client invokes the executor instead.
+	Rejected string `json:"rejected,omitempty"`
+}
+
 const (
 
 	// DeviceScanQuick performs a shallow, quick scan.
@@ -11,6 +11,22 @@ type VolumeMountOpts struct {
 	Opts        Store
 }
 
+// VolumeConsumerIDOptKey is the key of the option that contains the ID of
+// the consumer, such as a container, on whose behalf a volume is mounted or
+// unmounted.
+const VolumeConsumerIDOptKey = "consumerID"
+
+// VolumeRefs are the references held on a mounted volume.
+type VolumeRefs struct {
+
+	// Count is the number of references held on the volume.
+	Count int `json:"count" yaml:"count"`
+
+	// Consumers are the IDs of the consumers that hold references on the
+	// volume. References taken without a consumer ID are only counted.
+	Consumers []string `json:"consumers,omitempty" yaml:"consumers,omitempty"`
+}
+
 // VolumeMapping is a volume's name and the path to which it is mounted.
 type VolumeMapping interface {
 	// VolumeName returns the volume's name.
@@ -94,3 +110,13 @@ type IntegrationDriver interface {
 		volumeName string,
 		opts *VolumeDetachOpts) error
 }
+
+// IntegrationDriverWithRefs is an IntegrationDriver that tracks the
+// references held on the volumes it mounts.
+type IntegrationDriverWithRefs interface {
+	IntegrationDriver
+
+	// Refs returns the references held on the mounted volumes keyed by the
+	// volumes' names.
+	Refs(ctx Context) (map[string]*VolumeRefs, error)
+}
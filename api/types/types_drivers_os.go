@@ -61,3 +61,16 @@ type OSDriver interface {
 		deviceName string,
 		opts *DeviceFormatOpts) error
 }
+
+// OSDriverWithGrowFS is an interface that OS driver implementations may use
+// to grow file systems after their devices are resized.
+type OSDriverWithGrowFS interface {
+
+	// GrowFS grows a device's file system to fill the device. The mount point
+	// is required for file systems that can only be grown while mounted.
+	// ErrNotImplemented is returned for file systems that cannot be grown.
+	GrowFS(
+		ctx Context,
+		deviceName, mountPoint string,
+		opts Store) error
+}
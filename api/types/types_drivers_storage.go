@@ -229,6 +229,7 @@ type VolumeCreateOpts struct {
 	Type             *string
 	Encrypted        *bool
 	EncryptionKey    *string
+	Labels           map[string]string
 	Opts             Store
 }
 
@@ -251,6 +252,13 @@ type VolumeRemoveOpts struct {
 	Opts  Store
 }
 
+// VolumeResizeOpts are options for resizing a volume.
+type VolumeResizeOpts struct {
+	Size  *int64
+	Force bool
+	Opts  Store
+}
+
 // StorageDriverManager is the management wrapper for a StorageDriver.
 type StorageDriverManager interface {
 	StorageDriver
@@ -387,3 +395,51 @@ type StorageDriverVolInspectByName interface {
 		volumeName string,
 		opts *VolumeInspectOpts) (*Volume, error)
 }
+
+// StorageDriverWithVolumesPage is a StorageDriver that is able to return a
+// page of volumes natively.
+type StorageDriverWithVolumesPage interface {
+	StorageDriver
+
+	// VolumesPage returns a page of volumes and the continuation token for
+	// the next page, or an empty string if there are no more volumes.
+	VolumesPage(
+		ctx Context,
+		opts *VolumesOpts,
+		page *PageOpts) ([]*Volume, string, error)
+}
+
+// StorageDriverWithSnapshotsPage is a StorageDriver that is able to return
+// a page of snapshots natively.
+type StorageDriverWithSnapshotsPage interface {
+	StorageDriver
+
+	// SnapshotsPage returns a page of snapshots and the continuation token
+	// for the next page, or an empty string if there are no more snapshots.
+	SnapshotsPage(
+		ctx Context,
+		opts Store,
+		page *PageOpts) ([]*Snapshot, string, error)
+}
+
+// StorageDriverWithVolumeResize is a StorageDriver with a VolumeResize
+// function.
+type StorageDriverWithVolumeResize interface {
+	StorageDriver
+
+	// VolumeResize grows a volume to the size specified in the options.
+	VolumeResize(
+		ctx Context,
+		volumeID string,
+		opts *VolumeResizeOpts) (*Volume, error)
+}
+
+// StorageDriverWithHealthCheck is a StorageDriver with a HealthCheck
+// function.
+type StorageDriverWithHealthCheck interface {
+	StorageDriver
+
+	// HealthCheck returns an error if the storage platform is unreachable.
+	HealthCheck(
+		ctx Context) error
+}
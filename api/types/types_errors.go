@@ -52,6 +52,51 @@ type ErrBatchProcess struct{ goof.Goof }
 // string.
 type ErrBadFilter struct{ goof.Goof }
 
+// ErrTaskCancelled is the error that is used to indicate a task was
+// cancelled.
+var ErrTaskCancelled = goof.New("task cancelled")
+
+// ErrTaskCompleted occurs when an operation that requires a task to be queued
+// or running, such as cancellation, is sent to a task that has completed.
+type ErrTaskCompleted struct{ goof.Goof }
+
+// ErrBadPageOpts occurs when invalid pagination or sorting options are
+// supplied via the query string.
+type ErrBadPageOpts struct{ goof.Goof }
+
+// ErrQuotaExceeded occurs when a request would cause an auth subject to
+// exceed its quota for a storage service.
+type ErrQuotaExceeded struct{ goof.Goof }
+
+// ErrQuotaSizeRequired occurs when a volume is created without a size, and
+// without a snapshot or volume from which to take the size, while a size
+// quota is enforced for the auth subject.
+type ErrQuotaSizeRequired struct{ goof.Goof }
+
+// ErrForbidden occurs when an auth token is valid but none of the roles
+// assigned to the token's subject may access the requested route.
+type ErrForbidden struct{ goof.Goof }
+
+// ErrRateLimited occurs when a request exceeds one of the server's rate
+// limits.
+type ErrRateLimited struct{ goof.Goof }
+
+// ErrIdempotencyKeyReused occurs when an idempotency key is sent with a
+// request that differs from the one for which the key was first used.
+type ErrIdempotencyKeyReused struct{ goof.Goof }
+
+// ErrExecutorLockTimeout occurs when the executor lock is not acquired before
+// the configured timeout.
+type ErrExecutorLockTimeout struct{ goof.Goof }
+
+// ErrServiceExists occurs when a storage service is created with the name of
+// an existing service.
+type ErrServiceExists struct{ goof.Goof }
+
+// ErrServiceStopped occurs when a task is enqueued for a storage service
+// that was removed or replaced.
+type ErrServiceStopped struct{ goof.Goof }
+
 // ErrMissingStorageService occurs when the storage service is expected in
 // the provided context but is not there.
 var ErrMissingStorageService = goof.New("missing storage service")
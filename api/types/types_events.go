@@ -0,0 +1,126 @@
+package types
+
+import "strings"
+
+// EventStreamContentType is the content type of a stream of server-sent
+// events.
+const EventStreamContentType = "text/event-stream"
+
+// EventType is the type of an event.
+type EventType string
+
+const (
+	// EventVolumeCreated is the type of the event published when a volume
+	// is created.
+	EventVolumeCreated EventType = "volume.created"
+
+	// EventVolumeAttached is the type of the event published when a volume
+	// is attached.
+	EventVolumeAttached EventType = "volume.attached"
+
+	// EventVolumeDetached is the type of the event published when a volume
+	// is detached.
+	EventVolumeDetached EventType = "volume.detached"
+
+	// EventVolumeResized is the type of the event published when a volume
+	// is resized.
+	EventVolumeResized EventType = "volume.resized"
+
+	// EventVolumeRemoved is the type of the event published when a volume
+	// is removed.
+	EventVolumeRemoved EventType = "volume.removed"
+
+	// EventSnapshotCreated is the type of the event published when a
+	// snapshot is created.
+	EventSnapshotCreated EventType = "snapshot.created"
+
+	// EventSnapshotRemoved is the type of the event published when a
+	// snapshot is removed.
+	EventSnapshotRemoved EventType = "snapshot.removed"
+
+	// EventTaskState is the type of the event published when a task
+	// transitions to a new state.
+	EventTaskState EventType = "task.state"
+)
+
+// Event describes a change to a volume, snapshot, or task.
+type Event struct {
+
+	// ID is the sequence number of the event. IDs are unique to the server
+	// that published the event.
+	ID int64 `json:"id" yaml:"id"`
+
+	// Type is the type of the event.
+	Type EventType `json:"type" yaml:"type"`
+
+	// Time is the epoch, in seconds, at which the event was published.
+	Time int64 `json:"time" yaml:"time"`
+
+	// Service is the name of the service to which the resource belongs.
+	Service string `json:"service,omitempty" yaml:"service,omitempty"`
+
+	// VolumeID is the ID of the volume that changed.
+	VolumeID string `json:"volumeID,omitempty" yaml:"volumeID,omitempty"`
+
+	// SnapshotID is the ID of the snapshot that changed.
+	SnapshotID string `json:"snapshotID,omitempty" yaml:"snapshotID,omitempty"`
+
+	// TaskID is the ID of the task that changed.
+	TaskID *int `json:"taskID,omitempty" yaml:"taskID,omitempty"`
+
+	// TaskState is the state to which the task transitioned.
+	TaskState TaskState `json:"taskState,omitempty" yaml:"taskState,omitempty"`
+
+	// Subject is the subject of the auth token of the call that caused the
+	// change.
+	Subject string `json:"subject,omitempty" yaml:"subject,omitempty"`
+
+	// TransactionID is the ID of the transaction that caused the change.
+	TransactionID string `json:"txID,omitempty" yaml:"txID,omitempty"`
+}
+
+// EventFilter describes the events to receive from a subscription. Empty
+// fields match all events.
+type EventFilter struct {
+
+	// Types matches the events' types.
+	Types []EventType
+
+	// Service matches the events' service names.
+	Service string
+
+	// VolumeID matches the events' volume IDs.
+	VolumeID string
+
+	// SnapshotID matches the events' snapshot IDs.
+	SnapshotID string
+}
+
+// Match returns a flag indicating whether the event matches the filter.
+func (f *EventFilter) Match(e *Event) bool {
+	if len(f.Types) > 0 {
+		ok := false
+		for _, t := range f.Types {
+			if t == e.Type {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	for _, v := range [][2]string{
+		{f.Service, e.Service},
+		{f.VolumeID, e.VolumeID},
+		{f.SnapshotID, e.SnapshotID},
+	} {
+		if v[0] != "" && !strings.EqualFold(v[0], v[1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// EventFunc is a function that publishes an event.
+type EventFunc func(ctx Context, event *Event)
@@ -0,0 +1,51 @@
+package types
+
+// HealthStatus is the status of the server or a storage service.
+type HealthStatus string
+
+const (
+	// HealthStatusUp indicates the server or service is available.
+	HealthStatusUp HealthStatus = "up"
+
+	// HealthStatusDown indicates the server or service is unavailable.
+	HealthStatusDown HealthStatus = "down"
+)
+
+// Health is the health of the server process.
+type Health struct {
+
+	// Status is the status of the server process.
+	Status HealthStatus `json:"status" yaml:"status"`
+}
+
+// ServiceHealth is the result of probing a storage service.
+type ServiceHealth struct {
+
+	// Status is the status of the service.
+	Status HealthStatus `json:"status" yaml:"status"`
+
+	// Critical indicates that the server is not ready when the service is
+	// down.
+	Critical bool `json:"critical" yaml:"critical"`
+
+	// Latency is the time, in milliseconds, taken to probe the service.
+	Latency int64 `json:"latency" yaml:"latency"`
+
+	// LastError is the error returned by the most recent failed probe.
+	LastError string `json:"lastError,omitempty" yaml:"lastError,omitempty"`
+
+	// LastErrorTime is the epoch, in seconds, of the most recent failed
+	// probe.
+	LastErrorTime int64 `json:"lastErrorTime,omitempty" yaml:"lastErrorTime,omitempty"`
+}
+
+// Readiness is the result of probing all of the storage services.
+type Readiness struct {
+
+	// Status is down if any of the critical services are down.
+	Status HealthStatus `json:"status" yaml:"status"`
+
+	// Services is a map of the results of probing the services, keyed by
+	// the services' names.
+	Services map[string]*ServiceHealth `json:"services" yaml:"services"`
+}
@@ -22,4 +22,45 @@ const (
 	// AuthorizationHeader is the HTTP header that contains the Authorization
 	// information.
 	AuthorizationHeader = "Authorization"
+
+	// NextMarkerHeader is the HTTP header that contains the continuation
+	// token for the next page of a paginated collection. The header is
+	// omitted when the response contains the last page.
+	NextMarkerHeader = "Libstorage-Nextmarker"
+
+	// WebhookEventHeader is the HTTP header that contains the type of the
+	// event delivered by a webhook.
+	WebhookEventHeader = "Libstorage-Event"
+
+	// WebhookSignatureHeader is the HTTP header that contains the
+	// hex-encoded HMAC-SHA256 of the timestamp and payload delivered by a
+	// webhook, prefixed with "sha256=".
+	WebhookSignatureHeader = "Libstorage-Signature"
+
+	// WebhookTimestampHeader is the HTTP header that contains the epoch, in
+	// seconds, at which a webhook's payload was signed.
+	WebhookTimestampHeader = "Libstorage-Timestamp"
+
+	// IdempotencyKeyHeader is the HTTP header that contains the key with
+	// which the server identifies the retries of a mutating request. The
+	// request's transaction ID is used when the header is omitted.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotencyHeader is the HTTP header with which a server advertises
+	// that it returns the outcome of a mutating request to the retries of
+	// the request that share its transaction ID. The header's value is
+	// IdempotencyTransaction.
+	IdempotencyHeader = "Libstorage-Idempotency"
+
+	// IdempotencyTransaction is the value of the IdempotencyHeader.
+	IdempotencyTransaction = "tx"
+
+	// DigestHeader is the HTTP header that contains the base64-encoded
+	// SHA-256 checksum of a downloaded executor, ex. "SHA-256=<checksum>".
+	DigestHeader = "Digest"
+
+	// RetryAfterHeader is the HTTP header that contains the number of
+	// seconds a client should wait before retrying a request that exceeded
+	// a rate limit.
+	RetryAfterHeader = "Retry-After"
 )
@@ -13,6 +13,7 @@ type VolumeCreateRequest struct {
 	IOPS             *int64                 `json:"iops,omitempty"`
 	Size             *int64                 `json:"size,omitempty"`
 	Type             *string                `json:"type,omitempty"`
+	Labels           map[string]string      `json:"labels,omitempty"`
 	Opts             map[string]interface{} `json:"opts,omitempty"`
 }
 
@@ -22,6 +23,13 @@ type VolumeCopyRequest struct {
 	Opts       map[string]interface{} `json:"opts,omitempty"`
 }
 
+// VolumeResizeRequest is the JSON body for resizing a volume.
+type VolumeResizeRequest struct {
+	Size  int64                  `json:"size"`
+	Force bool                   `json:"force,omitempty"`
+	Opts  map[string]interface{} `json:"opts,omitempty"`
+}
+
 // VolumeSnapshotRequest is the JSON body for snapshotting a volume.
 type VolumeSnapshotRequest struct {
 	SnapshotName string                 `json:"snapshotName"`
@@ -41,6 +49,17 @@ type VolumeDetachRequest struct {
 	Opts  map[string]interface{} `json:"opts,omitempty"`
 }
 
+// ServiceCreateRequest is the JSON body for creating a storage service.
+type ServiceCreateRequest struct {
+	Name   string                 `json:"name"`
+	Config map[string]interface{} `json:"config"`
+}
+
+// ServiceUpdateRequest is the JSON body for reconfiguring a storage service.
+type ServiceUpdateRequest struct {
+	Config map[string]interface{} `json:"config"`
+}
+
 // SnapshotCopyRequest is the JSON body for copying a snapshot.
 type SnapshotCopyRequest struct {
 	SnapshotName  string                 `json:"snapshotName"`
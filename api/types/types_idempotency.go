@@ -0,0 +1,16 @@
+package types
+
+// IdempotencyRecord is the record of a mutating request whose outcome is
+// returned to the retries of the request.
+type IdempotencyRecord struct {
+
+	// Key is the key that identifies the request and its retries.
+	Key string
+
+	// TaskID is the ID of the task that executed the request.
+	TaskID *int
+
+	// TaskStatus is the HTTP status with which the result of the task is
+	// written.
+	TaskStatus int
+}
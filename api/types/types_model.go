@@ -187,6 +187,10 @@ type Volume struct {
 	// The volume IOPs.
 	IOPS int64 `json:"iops,omitempty" yaml:"iops,omitempty"`
 
+	// Labels are user-defined key/value pairs that are used to organize and
+	// select volumes.
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+
 	// The name of the volume.
 	Name string `json:"name" yaml:"name,omitempty"`
 
@@ -279,7 +283,7 @@ type VolumeDevice struct {
 }
 
 // ExecutorInfo contains information about a client-side executor, such as
-// its name and MD5 checksum.
+// its name and checksums.
 type ExecutorInfo struct {
 
 	// Name is the name of the executor.
@@ -289,6 +293,14 @@ type ExecutorInfo struct {
 	// determine if a local copy of the executor needs to be updated.
 	MD5Checksum string `json:"md5checksum" yaml:"md5checksum"`
 
+	// SHA256Checksum is the SHA-256 checksum of the executor. This is used
+	// in favor of the MD5 checksum when it is present.
+	SHA256Checksum string `json:"sha256checksum,omitempty" yaml:"sha256checksum,omitempty"`
+
+	// Signature is the base64-encoded detached signature of the executor's
+	// SHA-256 checksum.
+	Signature string `json:"signature,omitempty" yaml:"signature,omitempty"`
+
 	// Size is the size of the executor in bytes.
 	Size int64 `json:"size"`
 
@@ -362,6 +374,9 @@ const (
 
 	// TaskStateError is the state for a task that has completed with an error.
 	TaskStateError = "error"
+
+	// TaskStateCancelled is the state for a task that was cancelled.
+	TaskStateCancelled = "cancelled"
 )
 
 // Task is a representation of an asynchronous, long-running task.
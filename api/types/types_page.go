@@ -0,0 +1,51 @@
+package types
+
+// PageOpts are the options used to request a single page of a collection.
+type PageOpts struct {
+
+	// Limit is the maximum number of objects in the page. A value of zero
+	// indicates there is no limit.
+	Limit int
+
+	// Offset is the number of objects to skip before the page begins.
+	Offset int
+
+	// Marker is the continuation token returned with the previous page. The
+	// page begins with the object that follows the one encoded in the marker.
+	Marker string
+
+	// Sort is the name of the attribute by which the collection is sorted.
+	// A leading "-" sorts the collection in descending order.
+	Sort string
+}
+
+// Page is implemented by results that are a single page of a larger
+// collection.
+type Page interface {
+
+	// NextMarker returns the continuation token used to request the next
+	// page. An empty string indicates this is the last page.
+	NextMarker() string
+}
+
+// ServiceVolume is a volume and the name of the service to which it belongs.
+// A page of the volumes for all services is a list of them.
+type ServiceVolume struct {
+
+	// Service is the name of the service to which the volume belongs.
+	Service string `json:"service" yaml:"service"`
+
+	// Volume is the volume.
+	Volume *Volume `json:"volume" yaml:"volume"`
+}
+
+// ServiceSnapshot is a snapshot and the name of the service to which it
+// belongs. A page of the snapshots for all services is a list of them.
+type ServiceSnapshot struct {
+
+	// Service is the name of the service to which the snapshot belongs.
+	Service string `json:"service" yaml:"service"`
+
+	// Snapshot is the snapshot.
+	Snapshot *Snapshot `json:"snapshot" yaml:"snapshot"`
+}
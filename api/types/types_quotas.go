@@ -0,0 +1,57 @@
+package types
+
+// QuotaLimits are the limits of an auth subject's quota for a storage
+// service. A value of zero indicates the limit is not enforced.
+type QuotaLimits struct {
+
+	// Volumes is the maximum number of volumes.
+	Volumes int64 `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+
+	// Size is the maximum total size of the volumes (GB).
+	Size int64 `json:"size,omitempty" yaml:"size,omitempty"`
+
+	// MaxVolumeSize is the maximum size of a single volume (GB).
+	MaxVolumeSize int64 `json:"maxVolumeSize,omitempty" yaml:"maxVolumeSize,omitempty"`
+}
+
+// QuotaUsage is an auth subject's usage of a storage service.
+type QuotaUsage struct {
+
+	// Volumes is the number of volumes.
+	Volumes int64 `json:"volumes" yaml:"volumes"`
+
+	// Size is the total size of the volumes (GB).
+	Size int64 `json:"size" yaml:"size"`
+}
+
+// Quota is an auth subject's quota and usage for a storage service.
+type Quota struct {
+
+	// Subject is the auth subject to which the quota applies. Requests that
+	// do not include an auth token are accounted to the empty subject.
+	Subject string `json:"subject" yaml:"subject"`
+
+	// Limits are the quota's limits.
+	Limits *QuotaLimits `json:"limits" yaml:"limits"`
+
+	// Usage is the subject's usage.
+	Usage *QuotaUsage `json:"usage" yaml:"usage"`
+}
+
+// QuotaMap is a map of quotas keyed by auth subject.
+type QuotaMap map[string]*Quota
+
+// ServiceQuotaMap is a map of quota maps keyed by service name.
+type ServiceQuotaMap map[string]QuotaMap
+
+// QuotaReservation is the space reserved against an auth subject's quota
+// while a volume is created. A reservation is either committed once the
+// volume is created or cancelled if the volume could not be created.
+type QuotaReservation interface {
+
+	// Commit records the volume as owned by the reservation's subject.
+	Commit(ctx Context, volume *Volume) error
+
+	// Cancel releases the reservation.
+	Cancel()
+}
@@ -0,0 +1,51 @@
+package types
+
+import "time"
+
+const (
+	// RetryErrorConnect is the class of errors that occur when a connection
+	// to the server cannot be established. A request that fails with such an
+	// error was not sent.
+	RetryErrorConnect = "connect"
+
+	// RetryErrorTimeout is the class of errors that occur when the server
+	// does not answer a request in time.
+	RetryErrorTimeout = "timeout"
+
+	// RetryErrorNetwork is the class of errors that occur when the connection
+	// to the server is lost, such as when it is reset or closed before the
+	// response is read.
+	RetryErrorNetwork = "network"
+)
+
+// RetryPolicy is the policy with which a client retries the requests that
+// fail for transient reasons.
+type RetryPolicy struct {
+
+	// MaxAttempts is the number of times a request is sent, including the
+	// first attempt. A value of one or less disables retries.
+	MaxAttempts int
+
+	// Backoff is the time to wait before the first retry. The time doubles
+	// with each retry, up to MaxBackoff, and is randomized so that clients
+	// do not retry in lockstep.
+	Backoff time.Duration
+
+	// MaxBackoff is the longest time to wait before a retry.
+	MaxBackoff time.Duration
+
+	// StatusCodes are the HTTP status codes of the responses that are
+	// retried.
+	StatusCodes []int
+
+	// Errors are the classes of errors that are retried, ex.
+	// RetryErrorConnect.
+	Errors []string
+
+	// Mutating indicates whether requests that modify something are retried
+	// when they may have reached the server. Such requests are retried only
+	// with their original transaction ID, which the server uses to return the
+	// outcome of the original request instead of executing it again.
+	// Requests that never reached the server are always retried.
+	Mutating bool
+}
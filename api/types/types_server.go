@@ -52,4 +52,9 @@ type Server interface {
 
 	// Addrs returns the server's configured endpoint addresses.
 	Addrs() []string
+
+	// Reload re-reads the server's configuration and applies it to the
+	// running server. An invalid configuration is rejected without
+	// modifying the server.
+	Reload() error
 }
@@ -61,6 +61,10 @@ type TaskTrackingService interface {
 	// TaskInspect returns the task with the specified ID.
 	TaskInspect(taskID int) *Task
 
+	// TaskCancel cancels the task with the specified ID. A queued task is
+	// cancelled immediately, and a running task's context is cancelled.
+	TaskCancel(taskID int) (*Task, error)
+
 	// TaskWait blocks until the specified task completes.
 	TaskWait(taskID int) <-chan int
 
@@ -76,6 +80,33 @@ type TaskTrackingService interface {
 	TaskWaitAllC(taskIDs ...int) <-chan int
 }
 
+// NewTaskStore is a function that constructs a new TaskStore.
+type NewTaskStore func() TaskStore
+
+// TaskStore is the interface implemented by types that persist the tasks
+// tracked by a TaskTrackingService.
+type TaskStore interface {
+	Driver
+
+	// NextID returns the next task ID. Task IDs are monotonic and are never
+	// reused, even across restarts for stores that are persistent. The
+	// returned ID is valid even if an error occurred persisting it.
+	NextID() (int, error)
+
+	// Save creates or updates a task.
+	Save(task *Task) error
+
+	// Get returns the task with the specified ID; nil if the task does not
+	// exist.
+	Get(taskID int) (*Task, error)
+
+	// Remove removes the task with the specified ID.
+	Remove(taskID int) error
+
+	// List returns all of the stored tasks.
+	List() ([]*Task, error)
+}
+
 // TaskExecutionService is a service for executing tasks.
 type TaskExecutionService interface {
 	Service
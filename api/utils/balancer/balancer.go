@@ -0,0 +1,272 @@
+// Package balancer provides an HTTP transport that sends each request to one
+// of several endpoints, failing over to the next endpoint when one cannot be
+// reached.
+package balancer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the sticky routes that have not been used for
+// longer than their TTL are removed from a transport.
+const sweepInterval = time.Minute
+
+// DialError is returned by an endpoint's transport when the connection to
+// the endpoint could not be established. A request that failed with a
+// DialError was not sent, so it is safe to send it to another endpoint.
+type DialError struct {
+	Err error
+}
+
+func (e *DialError) Error() string {
+	return e.Err.Error()
+}
+
+// Endpoint is an endpoint to which requests are sent.
+type Endpoint struct {
+
+	// Host is the value of the Host header of the requests sent to the
+	// endpoint.
+	Host string
+
+	// Transport sends the requests to the endpoint.
+	Transport http.RoundTripper
+
+	downUntil time.Time
+}
+
+// Options are the options of a transport.
+type Options struct {
+
+	// RoundRobin spreads the requests that do not modify anything among the
+	// available endpoints. Otherwise requests are sent to the first available
+	// endpoint in the order in which the endpoints were given.
+	RoundRobin bool
+
+	// Downtime is how long an endpoint that could not be reached is passed
+	// over for the other endpoints.
+	Downtime time.Duration
+
+	// StickyHeader is the name of the header whose value pins a request to
+	// the endpoint that answered the last request with the same value.
+	StickyHeader string
+
+	// StickyTTL is how long a pinned value is kept after its last request.
+	StickyTTL time.Duration
+}
+
+type stickyRoute struct {
+	endpoint *Endpoint
+	last     time.Time
+}
+
+// Transport is an HTTP transport that sends each request to one of several
+// endpoints.
+type Transport struct {
+	sync.Mutex
+	endpoints []*Endpoint
+	opts      Options
+	next      int
+	sticky    map[string]*stickyRoute
+	inflight  map[*http.Request]*inflightRequest
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type inflightRequest struct {
+	req      *http.Request
+	endpoint *Endpoint
+}
+
+// New returns a new transport that sends requests to the given endpoints.
+func New(endpoints []*Endpoint, opts Options) *Transport {
+	return &Transport{
+		endpoints: endpoints,
+		opts:      opts,
+		sticky:    map[string]*stickyRoute{},
+		inflight:  map[*http.Request]*inflightRequest{},
+		now:       time.Now,
+	}
+}
+
+// Endpoints returns the transport's endpoints.
+func (t *Transport) Endpoints() []*Endpoint {
+	return t.endpoints
+}
+
+// RoundTrip sends the request to the endpoint selected for it. If the
+// endpoint cannot be reached the request is sent to the next endpoint. A
+// request that does not modify anything is also sent to the next endpoint
+// when the response from the selected endpoint cannot be read.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	// the body is buffered so that it may be sent again
+	var body []byte
+	if req.Body != nil {
+		buf, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = buf
+	}
+
+	defer func() {
+		t.Lock()
+		delete(t.inflight, req)
+		t.Unlock()
+	}()
+
+	var (
+		res     *http.Response
+		err     error
+		stickTo = req.Header.Get(t.opts.StickyHeader)
+	)
+
+	for _, ep := range t.order(req.Method, stickTo) {
+
+		epReq := newEndpointRequest(req, ep, body)
+
+		t.Lock()
+		t.inflight[req] = &inflightRequest{req: epReq, endpoint: ep}
+		t.Unlock()
+
+		res, err = ep.Transport.RoundTrip(epReq)
+		if err == nil {
+			t.markUp(ep, stickTo)
+			return res, nil
+		}
+
+		if _, ok := err.(*DialError); !ok && !isReadOnly(req.Method) {
+			return nil, err
+		}
+		t.markDown(ep)
+	}
+
+	if de, ok := err.(*DialError); ok {
+		return nil, de.Err
+	}
+	return nil, err
+}
+
+// CancelRequest cancels an in-flight request.
+func (t *Transport) CancelRequest(req *http.Request) {
+	t.Lock()
+	ifr, ok := t.inflight[req]
+	t.Unlock()
+	if !ok {
+		return
+	}
+	type canceler interface {
+		CancelRequest(*http.Request)
+	}
+	if c, ok := ifr.endpoint.Transport.(canceler); ok {
+		c.CancelRequest(ifr.req)
+	}
+}
+
+// order returns the endpoints in the order in which a request is tried. The
+// endpoints that are down are tried last, in case they have come back.
+func (t *Transport) order(method, stickTo string) []*Endpoint {
+	t.Lock()
+	defer t.Unlock()
+
+	now := t.now()
+	t.sweep(now)
+
+	n := len(t.endpoints)
+	start := 0
+	if t.opts.RoundRobin && isReadOnly(method) && n > 0 {
+		start = t.next % n
+		t.next = (t.next + 1) % n
+	}
+
+	var (
+		up   []*Endpoint
+		down []*Endpoint
+	)
+
+	if sr, ok := t.sticky[stickTo]; ok && stickTo != "" &&
+		!now.Before(sr.endpoint.downUntil) {
+		up = append(up, sr.endpoint)
+	}
+
+	for i := 0; i < n; i++ {
+		ep := t.endpoints[(start+i)%n]
+		if len(up) > 0 && up[0] == ep {
+			continue
+		}
+		if now.Before(ep.downUntil) {
+			down = append(down, ep)
+		} else {
+			up = append(up, ep)
+		}
+	}
+
+	return append(up, down...)
+}
+
+// markUp records that the endpoint answered the request and pins the
+// request's sticky value, if any, to the endpoint.
+func (t *Transport) markUp(ep *Endpoint, stickTo string) {
+	t.Lock()
+	defer t.Unlock()
+
+	ep.downUntil = time.Time{}
+	if stickTo != "" {
+		t.sticky[stickTo] = &stickyRoute{endpoint: ep, last: t.now()}
+	}
+}
+
+// markDown records that the endpoint could not be reached.
+func (t *Transport) markDown(ep *Endpoint) {
+	t.Lock()
+	defer t.Unlock()
+	ep.downUntil = t.now().Add(t.opts.Downtime)
+}
+
+// sweep removes the sticky routes that have expired at most once per the
+// sweep interval.
+func (t *Transport) sweep(now time.Time) {
+	if now.Sub(t.lastSweep) < sweepInterval {
+		return
+	}
+	t.lastSweep = now
+	for k, sr := range t.sticky {
+		if now.Sub(sr.last) >= t.opts.StickyTTL {
+			delete(t.sticky, k)
+		}
+	}
+}
+
+// newEndpointRequest returns a copy of the request addressed to the endpoint.
+func newEndpointRequest(
+	req *http.Request, ep *Endpoint, body []byte) *http.Request {
+
+	epReq := new(http.Request)
+	*epReq = *req
+
+	u := *req.URL
+	u.Host = ep.Host
+	epReq.URL = &u
+	epReq.Host = ep.Host
+
+	epReq.Header = http.Header{}
+	for k, v := range req.Header {
+		epReq.Header[k] = v
+	}
+
+	if body != nil {
+		epReq.Body = ioutil.NopCloser(bytes.NewReader(body))
+		epReq.ContentLength = int64(len(body))
+	}
+	return epReq
+}
+
+func isReadOnly(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
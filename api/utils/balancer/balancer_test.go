@@ -0,0 +1,204 @@
+package balancer
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testTransport answers every request with its name unless it is down.
+type testTransport struct {
+	name   string
+	down   bool
+	broken bool
+	hosts  []string
+	bodies []string
+}
+
+func (tt *testTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if tt.down {
+		return nil, &DialError{errors.New(tt.name + " is down")}
+	}
+	if tt.broken {
+		return nil, errors.New(tt.name + " is broken")
+	}
+	tt.hosts = append(tt.hosts, req.Host)
+	if req.Body != nil {
+		buf, _ := ioutil.ReadAll(req.Body)
+		tt.bodies = append(tt.bodies, string(buf))
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Endpoint": []string{tt.name}},
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func newTestTransport(
+	opts Options, names ...string) (*Transport, []*testTransport, *time.Time) {
+
+	var (
+		eps []*Endpoint
+		tts []*testTransport
+	)
+	for _, name := range names {
+		tt := &testTransport{name: name}
+		tts = append(tts, tt)
+		eps = append(eps, &Endpoint{Host: name, Transport: tt})
+	}
+	now := time.Unix(1491238950, 0)
+	t := New(eps, opts)
+	t.now = func() time.Time { return now }
+	return t, tts, &now
+}
+
+func send(
+	t *testing.T,
+	tr *Transport,
+	method, body, stickTo string) (string, error) {
+
+	var req *http.Request
+	if body == "" {
+		req, _ = http.NewRequest(method, "http://default/volumes", nil)
+	} else {
+		req, _ = http.NewRequest(
+			method, "http://default/volumes", strings.NewReader(body))
+	}
+	if stickTo != "" {
+		req.Header.Set("Libstorage-Tx", stickTo)
+	}
+	res, err := tr.RoundTrip(req)
+	if err != nil {
+		return "", err
+	}
+	res.Body.Close()
+	return res.Header.Get("Endpoint"), nil
+}
+
+func TestFailover(t *testing.T) {
+	tr, tts, now := newTestTransport(
+		Options{Downtime: time.Minute}, "lss1", "lss2", "lss3")
+
+	ep, err := send(t, tr, "POST", `{"name":"vol"}`, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "lss1", ep)
+	assert.Equal(t, []string{"lss1"}, tts[0].hosts)
+
+	// a request that could not be sent is sent to the next endpoint along
+	// with its body
+	tts[0].down = true
+	ep, err = send(t, tr, "POST", `{"name":"vol"}`, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "lss2", ep)
+	assert.Equal(t, []string{`{"name":"vol"}`}, tts[1].bodies)
+
+	// the endpoint that is down is passed over until its downtime elapses
+	tts[0].down = false
+	ep, err = send(t, tr, "GET", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "lss2", ep)
+
+	*now = now.Add(time.Minute)
+	ep, err = send(t, tr, "GET", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "lss1", ep)
+}
+
+func TestFailoverAllDown(t *testing.T) {
+	tr, tts, _ := newTestTransport(
+		Options{Downtime: time.Minute}, "lss1", "lss2")
+
+	tts[0].down = true
+	tts[1].down = true
+	_, err := send(t, tr, "GET", "", "")
+	assert.Error(t, err)
+	assert.Equal(t, "lss2 is down", err.Error())
+
+	// endpoints that are down are still tried when no others are left
+	tts[1].down = false
+	ep, err := send(t, tr, "GET", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "lss2", ep)
+}
+
+func TestFailoverMutatingRequest(t *testing.T) {
+	tr, tts, _ := newTestTransport(
+		Options{Downtime: time.Minute}, "lss1", "lss2")
+
+	// a mutating request that may have been sent is not sent again
+	tts[0].broken = true
+	_, err := send(t, tr, "POST", `{"name":"vol"}`, "")
+	assert.Error(t, err)
+	assert.Len(t, tts[1].bodies, 0)
+
+	// but a request that does not modify anything is
+	ep, err := send(t, tr, "GET", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "lss2", ep)
+}
+
+func TestRoundRobin(t *testing.T) {
+	tr, _, _ := newTestTransport(
+		Options{RoundRobin: true, Downtime: time.Minute},
+		"lss1", "lss2", "lss3")
+
+	for _, want := range []string{"lss1", "lss2", "lss3", "lss1"} {
+		ep, err := send(t, tr, "GET", "", "")
+		assert.NoError(t, err)
+		assert.Equal(t, want, ep)
+	}
+
+	// mutating requests are sent to the first endpoint
+	ep, err := send(t, tr, "DELETE", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "lss1", ep)
+}
+
+func TestSticky(t *testing.T) {
+	tr, tts, now := newTestTransport(
+		Options{
+			RoundRobin:   true,
+			Downtime:     time.Minute,
+			StickyHeader: "Libstorage-Tx",
+			StickyTTL:    10 * time.Minute,
+		},
+		"lss1", "lss2", "lss3")
+
+	ep, err := send(t, tr, "GET", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "lss1", ep)
+
+	// the requests of a transaction follow its first request
+	ep, err = send(t, tr, "GET", "", "tx1")
+	assert.NoError(t, err)
+	assert.Equal(t, "lss2", ep)
+	for i := 0; i < 3; i++ {
+		ep, err = send(t, tr, "POST", "{}", "tx1")
+		assert.NoError(t, err)
+		assert.Equal(t, "lss2", ep)
+	}
+
+	// a transaction whose endpoint is down moves to another endpoint
+	tts[1].down = true
+	ep, err = send(t, tr, "POST", "{}", "tx1")
+	assert.NoError(t, err)
+	assert.Equal(t, "lss1", ep)
+	tts[1].down = false
+	*now = now.Add(time.Minute)
+	ep, err = send(t, tr, "POST", "{}", "tx1")
+	assert.NoError(t, err)
+	assert.Equal(t, "lss1", ep)
+
+	// a transaction that is idle for longer than the TTL is forgotten
+	*now = now.Add(10 * time.Minute)
+	tr.order("GET", "")
+	tr.Lock()
+	assert.Len(t, tr.sticky, 0)
+	tr.Unlock()
+}
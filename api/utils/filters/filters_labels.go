@@ -0,0 +1,97 @@
+package filters
+
+import (
+	"strings"
+
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// CompileLabelSelector compiles a label selector into a filter that matches
+// the labels.<key> attributes of an object. A label selector is a
+// comma-separated list of requirements, all of which must be satisfied:
+//
+//	key=value, key==value   the label is present and equal to value
+//	key!=value              the label is absent or not equal to value
+//	key                     the label is present
+//	!key                    the label is absent
+//
+// For example, team=db,env!=prod. Values are compared the same way they are
+// in filters.
+func CompileLabelSelector(s string) (*types.Filter, error) {
+
+	if strings.TrimSpace(s) == "" {
+		return nil, goof.New("empty label selector")
+	}
+
+	f := &types.Filter{Op: filterAnd}
+
+	for _, req := range strings.Split(s, ",") {
+		req = strings.TrimSpace(req)
+
+		var (
+			key, val string
+			op       = filterPresent
+			neg      bool
+		)
+
+		switch {
+		case strings.Contains(req, "!="):
+			parts := strings.SplitN(req, "!=", 2)
+			key, val, op, neg = parts[0], parts[1], filterEqualityMatch, true
+		case strings.Contains(req, "=="):
+			parts := strings.SplitN(req, "==", 2)
+			key, val, op = parts[0], parts[1], filterEqualityMatch
+		case strings.Contains(req, "="):
+			parts := strings.SplitN(req, "=", 2)
+			key, val, op = parts[0], parts[1], filterEqualityMatch
+		case strings.HasPrefix(req, "!"):
+			key, neg = req[1:], true
+		default:
+			key = req
+		}
+
+		key = strings.TrimSpace(key)
+		if !isValidLabelKey(key) {
+			return nil, goof.WithFields(goof.Fields{
+				"selector":    s,
+				"requirement": req,
+			}, "invalid label selector requirement")
+		}
+
+		c := &types.Filter{
+			Op:    op,
+			Left:  "labels." + key,
+			Right: strings.TrimSpace(val),
+		}
+		if neg {
+			c = &types.Filter{Op: filterNot, Children: []*types.Filter{c}}
+		}
+		f.Children = append(f.Children, c)
+	}
+
+	return f, nil
+}
+
+// And returns a filter that matches when all of the non-nil filters match.
+// A nil value is returned if all of the filters are nil.
+func And(filters ...*types.Filter) *types.Filter {
+	children := []*types.Filter{}
+	for _, f := range filters {
+		if f != nil {
+			children = append(children, f)
+		}
+	}
+	switch len(children) {
+	case 0:
+		return nil
+	case 1:
+		return children[0]
+	}
+	return &types.Filter{Op: filterAnd, Children: children}
+}
+
+func isValidLabelKey(key string) bool {
+	return key != "" && !strings.ContainsAny(key, " \t\r\n,=!()")
+}
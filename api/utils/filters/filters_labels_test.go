@@ -0,0 +1,59 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func assertMatchLabels(t *testing.T, expected bool, s string) {
+	f, err := CompileLabelSelector(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := &types.Volume{
+		ID:     "vol-000",
+		Labels: map[string]string{"team": "db", "env": "dev"},
+	}
+	assert.Equal(t, expected, MatchVolume(f, v), s)
+}
+
+func TestCompileLabelSelector(t *testing.T) {
+	assertMatchLabels(t, true, "team=db")
+	assertMatchLabels(t, true, "team==db")
+	assertMatchLabels(t, false, "team=web")
+	assertMatchLabels(t, true, "team=db,env!=prod")
+	assertMatchLabels(t, false, "team=db,env!=dev")
+	assertMatchLabels(t, true, "owner!=finance")
+	assertMatchLabels(t, true, "team, !owner")
+	assertMatchLabels(t, false, "owner")
+	assertMatchLabels(t, false, "!env")
+}
+
+func TestCompileLabelSelectorInvalid(t *testing.T) {
+	for _, s := range []string{"", "=db", "team=db,", "!", "te am=db"} {
+		_, err := CompileLabelSelector(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestAnd(t *testing.T) {
+	assert.Nil(t, And(nil, nil))
+
+	f, err := CompileFilter("(size>=10)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, f, And(nil, f))
+
+	l, err := CompileLabelSelector("team=db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := &types.Volume{Size: 20, Labels: map[string]string{"team": "db"}}
+	assert.True(t, MatchVolume(And(f, l), v))
+	v.Size = 5
+	assert.False(t, MatchVolume(And(f, l), v))
+}
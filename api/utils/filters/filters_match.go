@@ -0,0 +1,203 @@
+package filters
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// AttributeFunc returns the value of the attribute with the specified name
+// and a flag indicating whether or not the attribute exists.
+type AttributeFunc func(name string) (string, bool)
+
+// Match returns a flag indicating whether or not the object described by the
+// attribute function matches the filter. Attribute names are matched without
+// regard to case, and values are compared as described by Compare.
+func Match(f *types.Filter, attr AttributeFunc) bool {
+	if f == nil {
+		return true
+	}
+
+	switch f.Op {
+	case filterAnd:
+		for _, c := range f.Children {
+			if !Match(c, attr) {
+				return false
+			}
+		}
+		return true
+	case filterOr:
+		for _, c := range f.Children {
+			if Match(c, attr) {
+				return true
+			}
+		}
+		return false
+	case filterNot:
+		if len(f.Children) == 0 {
+			return true
+		}
+		return !Match(f.Children[0], attr)
+	}
+
+	v, ok := attr(f.Left)
+	if !ok {
+		return false
+	}
+
+	switch f.Op {
+	case filterPresent:
+		return v != ""
+	case filterEqualityMatch:
+		return Compare(v, f.Right) == 0
+	case filterApproxMatch:
+		return strings.EqualFold(
+			strings.Join(strings.Fields(v), ""),
+			strings.Join(strings.Fields(f.Right), ""))
+	case filterSubstrings:
+		return strings.Contains(
+			strings.ToLower(v), strings.ToLower(f.Right))
+	case filterSubstringsPrefix:
+		// the wildcard is the prefix, ex. (name=*Texas)
+		return strings.HasSuffix(
+			strings.ToLower(v), strings.ToLower(f.Right))
+	case filterSubstringsPostfix:
+		// the wildcard is the postfix, ex. (name=Texas*)
+		return strings.HasPrefix(
+			strings.ToLower(v), strings.ToLower(f.Right))
+	case filterGreaterOrEqual:
+		return Compare(v, f.Right) >= 0
+	case filterLessOrEqual:
+		return Compare(v, f.Right) <= 0
+	}
+
+	return false
+}
+
+// MatchVolume returns a flag indicating whether or not the volume matches the
+// filter.
+func MatchVolume(f *types.Filter, v *types.Volume) bool {
+	return Match(f, VolumeAttributes(v))
+}
+
+// MatchSnapshot returns a flag indicating whether or not the snapshot matches
+// the filter.
+func MatchSnapshot(f *types.Filter, s *types.Snapshot) bool {
+	return Match(f, SnapshotAttributes(s))
+}
+
+// VolumeAttributes returns an attribute function for the volume's id, name,
+// type, status, availabilityZone, networkName, size, iops, encrypted, and
+// attachmentState attributes as well as any of its fields with
+// fields.<name> and any of its labels with labels.<key>.
+func VolumeAttributes(v *types.Volume) AttributeFunc {
+	return func(name string) (string, bool) {
+		switch strings.ToLower(name) {
+		case "id":
+			return v.ID, true
+		case "name":
+			return v.Name, true
+		case "type":
+			return v.Type, true
+		case "status":
+			return v.Status, true
+		case "availabilityzone":
+			return v.AvailabilityZone, true
+		case "networkname":
+			return v.NetworkName, true
+		case "size":
+			return strconv.FormatInt(v.Size, 10), true
+		case "iops":
+			return strconv.FormatInt(v.IOPS, 10), true
+		case "encrypted":
+			return strconv.FormatBool(v.Encrypted), true
+		case "attachmentstate":
+			return strconv.Itoa(int(v.AttachmentState)), true
+		}
+		if val, ok := mapValue(v.Labels, "labels.", name); ok {
+			return val, true
+		}
+		return mapValue(v.Fields, "fields.", name)
+	}
+}
+
+// SnapshotAttributes returns an attribute function for the snapshot's id,
+// name, description, status, volumeID, volumeSize, startTime, and encrypted
+// attributes as well as any of its fields with fields.<name>.
+func SnapshotAttributes(s *types.Snapshot) AttributeFunc {
+	return func(name string) (string, bool) {
+		switch strings.ToLower(name) {
+		case "id":
+			return s.ID, true
+		case "name":
+			return s.Name, true
+		case "description":
+			return s.Description, true
+		case "status":
+			return s.Status, true
+		case "volumeid":
+			return s.VolumeID, true
+		case "volumesize":
+			return strconv.FormatInt(s.VolumeSize, 10), true
+		case "starttime":
+			return strconv.FormatInt(s.StartTime, 10), true
+		case "encrypted":
+			return strconv.FormatBool(s.Encrypted), true
+		}
+		return mapValue(s.Fields, "fields.", name)
+	}
+}
+
+func mapValue(m map[string]string, prefix, name string) (string, bool) {
+	if len(name) <= len(prefix) ||
+		!strings.EqualFold(name[:len(prefix)], prefix) {
+		return "", false
+	}
+	key := name[len(prefix):]
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// Compare compares two values numerically if both are numbers; otherwise
+// the values are compared as strings without regard to case. Numbers are
+// ordered before all other values so that the order is the same for any
+// mix of values. The result is -1 if a < b, 0 if a == b, and 1 if a > b.
+func Compare(a, b string) int {
+	fa, aok := parseNumber(a)
+	fb, bok := parseNumber(b)
+	switch {
+	case aok && bok:
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		default:
+			return 0
+		}
+	case aok:
+		return -1
+	case bok:
+		return 1
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// parseNumber returns the value as a number and a flag indicating whether or
+// not the value is a number. NaN is not a number since it is not ordered.
+func parseNumber(v string) (float64, bool) {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(f) {
+		return 0, false
+	}
+	return f, true
+}
@@ -0,0 +1,105 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func newTestVolume() *types.Volume {
+	return &types.Volume{
+		ID:     "vol-000",
+		Name:   "Texas-Data",
+		Type:   "gp2",
+		Size:   100,
+		IOPS:   3000,
+		Fields: map[string]string{"datacenter": "irvine"},
+	}
+}
+
+func assertMatchVolume(t *testing.T, expected bool, s string) {
+	f, err := CompileFilter(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, expected, MatchVolume(f, newTestVolume()), s)
+}
+
+func TestMatchVolumeEquality(t *testing.T) {
+	assertMatchVolume(t, true, `(name=texas-data)`)
+	assertMatchVolume(t, false, `(name=houston)`)
+	assertMatchVolume(t, true, `(size=100)`)
+	assertMatchVolume(t, true, `(fields.datacenter=irvine)`)
+	assertMatchVolume(t, false, `(fields.department=finance)`)
+}
+
+func TestMatchVolumePresent(t *testing.T) {
+	assertMatchVolume(t, true, `(fields.datacenter=*)`)
+	assertMatchVolume(t, false, `(fields.department=*)`)
+	assertMatchVolume(t, false, `(networkName=*)`)
+}
+
+func TestMatchVolumeSubstrings(t *testing.T) {
+	assertMatchVolume(t, true, `(name=*as-Da*)`)
+	assertMatchVolume(t, true, `(name=*data)`)
+	assertMatchVolume(t, true, `(name=texas*)`)
+	assertMatchVolume(t, false, `(name=data*)`)
+}
+
+func TestMatchVolumeNumeric(t *testing.T) {
+	assertMatchVolume(t, true, `(size>=20)`)
+	assertMatchVolume(t, false, `(size<=20)`)
+	assertMatchVolume(t, true, `(&(iops>=1000)(iops<=3000))`)
+}
+
+func TestMatchVolumeAndOrNot(t *testing.T) {
+	assertMatchVolume(t, true,
+		`(&(|(fields.datacenter=irvine)(fields.datacenter=houston))(type=gp2))`)
+	assertMatchVolume(t, false, `(!(type=gp2))`)
+	assertMatchVolume(t, true, `(|(type=io1)(!(size<=10)))`)
+}
+
+func TestMatchSnapshot(t *testing.T) {
+	s := &types.Snapshot{
+		ID:         "snap-000",
+		VolumeID:   "vol-000",
+		VolumeSize: 10,
+		Fields:     map[string]string{"Owner": "finance"},
+	}
+
+	f, err := CompileFilter(`(&(volumeID=vol-000)(fields.owner=fin*))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, MatchSnapshot(f, s))
+
+	f, err = CompileFilter(`(volumeSize>=20)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, MatchSnapshot(f, s))
+}
+
+func TestCompareMixed(t *testing.T) {
+	assert.Equal(t, -1, Compare("9", "10"))
+	assert.Equal(t, 1, Compare("b", "A"))
+	assert.Equal(t, 0, Compare("abc", "ABC"))
+
+	// numbers are ordered before other values so that the order is
+	// transitive for any mix of values
+	vals := []string{"10", "9a", "9", "abc", "NaN", "-1.5", ""}
+	for _, a := range vals {
+		for _, b := range vals {
+			assert.Equal(t, -Compare(b, a), Compare(a, b), "%q %q", a, b)
+			for _, c := range vals {
+				if Compare(a, b) < 0 && Compare(b, c) < 0 {
+					assert.Equal(t, -1, Compare(a, c), "%q %q %q", a, b, c)
+				}
+			}
+		}
+	}
+	assert.Equal(t, -1, Compare("10", "9a"))
+	assert.Equal(t, -1, Compare("10", "NaN"))
+}
@@ -0,0 +1,151 @@
+// Package flock provides cross-process reader/writer locks backed by the
+// advisory file locks of the operating system. A lock is released by the
+// operating system when the process that holds it exits, so the lock of a
+// process that dies while holding it is reclaimed by the next process to
+// acquire it.
+package flock
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
+)
+
+// pollInterval is how often a lock that is held by another process is tried
+// again.
+const pollInterval = time.Duration(100) * time.Millisecond
+
+// ErrTimeout is returned when a lock is not acquired before the timeout.
+var ErrTimeout = errors.New("timed out acquiring lock")
+
+// Lock is an acquired lock.
+type Lock struct {
+	f      *os.File
+	shared bool
+
+	// Stale is the PID recorded by a process that exited while holding the
+	// lock exclusively. The value is zero if the lock was released normally.
+	Stale int
+}
+
+// Acquire acquires the lock at the given path exclusively, waiting up to the
+// given timeout for the processes that hold it to release it. A timeout of
+// zero or less waits indefinitely.
+func Acquire(path string, timeout time.Duration) (*Lock, error) {
+	return acquire(path, false, timeout)
+}
+
+// AcquireShared acquires the lock at the given path shared with other
+// readers, waiting up to the given timeout for a process that holds the lock
+// exclusively to release it. A timeout of zero or less waits indefinitely.
+func AcquireShared(path string, timeout time.Duration) (*Lock, error) {
+	return acquire(path, true, timeout)
+}
+
+func acquire(path string, shared bool, timeout time.Duration) (*Lock, error) {
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	for {
+		ok, err := tryLock(f, shared)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if ok {
+			break
+		}
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			f.Close()
+			return nil, ErrTimeout
+		}
+		time.Sleep(pollInterval)
+	}
+
+	l := &Lock{f: f, shared: shared}
+	if shared {
+		return l, nil
+	}
+
+	// an exclusive holder records its PID and removes it when it releases
+	// the lock, so a PID that is present when the lock is acquired belongs
+	// to a process that exited while holding the lock
+	l.Stale = readOwner(f)
+	if err := writeOwner(f, os.Getpid()); err != nil {
+		l.Release()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Release releases the lock.
+func (l *Lock) Release() error {
+	if !l.shared {
+		if err := writeOwner(l.f, 0); err != nil {
+			unlock(l.f)
+			l.f.Close()
+			return err
+		}
+	}
+	if err := unlock(l.f); err != nil {
+		l.f.Close()
+		return err
+	}
+	return l.f.Close()
+}
+
+// Owner returns the PID of the process that holds the lock at the given path
+// exclusively. The value is zero if the lock is not held exclusively or the
+// PID cannot be read.
+func Owner(path string) int {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	return parseOwner(buf)
+}
+
+func readOwner(f *os.File) int {
+	if _, err := f.Seek(0, 0); err != nil {
+		return 0
+	}
+	buf, err := ioutil.ReadAll(f)
+	if err != nil {
+		return 0
+	}
+	return parseOwner(buf)
+}
+
+func writeOwner(f *os.File, pid int) error {
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	if pid == 0 {
+		return nil
+	}
+	if _, err := f.Seek(0, 0); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(f, "%d\n", pid)
+	return err
+}
+
+func parseOwner(buf []byte) int {
+	pid, err := strconv.Atoi(string(bytes.TrimSpace(buf)))
+	if err != nil {
+		return 0
+	}
+	return pid
+}
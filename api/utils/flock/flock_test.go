@@ -0,0 +1,105 @@
+package flock
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newLockPath(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "flock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path.Join(dir, "test.lock")
+}
+
+func TestAcquire(t *testing.T) {
+	p := newLockPath(t)
+	defer os.RemoveAll(path.Dir(p))
+
+	l, err := Acquire(p, time.Second)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, 0, l.Stale)
+	assert.Equal(t, os.Getpid(), Owner(p))
+
+	_, err = Acquire(p, time.Duration(200)*time.Millisecond)
+	assert.Equal(t, ErrTimeout, err)
+	_, err = AcquireShared(p, time.Duration(200)*time.Millisecond)
+	assert.Equal(t, ErrTimeout, err)
+
+	assert.NoError(t, l.Release())
+	assert.Equal(t, 0, Owner(p))
+
+	l, err = Acquire(p, time.Second)
+	assert.NoError(t, err)
+	assert.NoError(t, l.Release())
+}
+
+func TestAcquireShared(t *testing.T) {
+	p := newLockPath(t)
+	defer os.RemoveAll(path.Dir(p))
+
+	l1, err := AcquireShared(p, time.Second)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	l2, err := AcquireShared(p, time.Second)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	_, err = Acquire(p, time.Duration(200)*time.Millisecond)
+	assert.Equal(t, ErrTimeout, err)
+
+	assert.NoError(t, l1.Release())
+	assert.NoError(t, l2.Release())
+
+	l, err := Acquire(p, time.Second)
+	assert.NoError(t, err)
+	assert.NoError(t, l.Release())
+}
+
+func TestAcquireWait(t *testing.T) {
+	p := newLockPath(t)
+	defer os.RemoveAll(path.Dir(p))
+
+	l, err := Acquire(p, time.Second)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	released := make(chan error, 1)
+	go func(l *Lock) {
+		time.Sleep(time.Duration(200) * time.Millisecond)
+		released <- l.Release()
+	}(l)
+
+	l2, err := Acquire(p, time.Duration(5)*time.Second)
+	assert.NoError(t, err)
+	assert.NoError(t, <-released)
+	assert.NoError(t, l2.Release())
+}
+
+func TestAcquireStale(t *testing.T) {
+	p := newLockPath(t)
+	defer os.RemoveAll(path.Dir(p))
+
+	// the PID of a process that exited while holding the lock
+	if err := ioutil.WriteFile(p, []byte("12345\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := Acquire(p, time.Second)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, 12345, l.Stale)
+	assert.Equal(t, os.Getpid(), Owner(p))
+	assert.NoError(t, l.Release())
+}
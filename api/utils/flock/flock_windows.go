@@ -0,0 +1,51 @@
+// +build windows
+
+package flock
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	lockfileFailImmediately = 0x1
+	lockfileExclusiveLock   = 0x2
+
+	errorLockViolation syscall.Errno = 33
+)
+
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = kernel32.NewProc("LockFileEx")
+	procUnlockFileEx = kernel32.NewProc("UnlockFileEx")
+)
+
+// tryLock tries to lock the file without blocking and returns a flag
+// indicating whether it was locked.
+func tryLock(f *os.File, shared bool) (bool, error) {
+	flags := uintptr(lockfileFailImmediately)
+	if !shared {
+		flags |= lockfileExclusiveLock
+	}
+	ol := &syscall.Overlapped{}
+	r, _, err := procLockFileEx.Call(
+		f.Fd(), flags, 0, 1, 0, uintptr(unsafe.Pointer(ol)))
+	if r != 0 {
+		return true, nil
+	}
+	if err == errorLockViolation {
+		return false, nil
+	}
+	return false, err
+}
+
+func unlock(f *os.File) error {
+	ol := &syscall.Overlapped{}
+	r, _, err := procUnlockFileEx.Call(
+		f.Fd(), 0, 1, 0, uintptr(unsafe.Pointer(ol)))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,349 @@
+// Package metrics provides the counters, gauges, and histograms recorded by
+// libStorage and writes them in the Prometheus text exposition format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ContentType is the content type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4"
+
+// DefaultBuckets are the default upper bounds, in seconds, of a histogram's
+// buckets.
+var DefaultBuckets = []float64{
+	.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120, 300,
+}
+
+var (
+	collectors    = map[string]Collector{}
+	collectorsRWL = &sync.RWMutex{}
+)
+
+// Collector is a family of metrics.
+type Collector interface {
+
+	// Name returns the name of the metric family.
+	Name() string
+
+	// WriteText writes the metric family in the Prometheus text exposition
+	// format.
+	WriteText(w io.Writer) error
+}
+
+// Register registers a collector so that it is included in the output of
+// WriteText. Registering two collectors with the same name panics.
+func Register(c Collector) {
+	collectorsRWL.Lock()
+	defer collectorsRWL.Unlock()
+	if _, ok := collectors[c.Name()]; ok {
+		panic(fmt.Sprintf("duplicate metric: %s", c.Name()))
+	}
+	collectors[c.Name()] = c
+}
+
+// WriteText writes all of the registered collectors, ordered by name, in the
+// Prometheus text exposition format.
+func WriteText(w io.Writer) error {
+	collectorsRWL.RLock()
+	names := make([]string, 0, len(collectors))
+	for name := range collectors {
+		names = append(names, name)
+	}
+	cs := make([]Collector, len(names))
+	sort.Strings(names)
+	for i, name := range names {
+		cs[i] = collectors[name]
+	}
+	collectorsRWL.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	for _, c := range cs {
+		if err := c.WriteText(bw); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// desc describes a metric family.
+type desc struct {
+	name       string
+	help       string
+	typ        string
+	labelNames []string
+}
+
+func (d *desc) Name() string {
+	return d.name
+}
+
+func (d *desc) writeHeader(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", d.name, escapeHelp(d.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", d.name, d.typ)
+}
+
+// writeSample writes a sample. The extra label is appended to the family's
+// labels if it is not empty.
+func (d *desc) writeSample(
+	w io.Writer,
+	suffix string,
+	labelValues []string,
+	extraName, extraValue string,
+	v float64) {
+
+	pairs := []string{}
+	for i, name := range d.labelNames {
+		pairs = append(pairs, fmt.Sprintf(
+			`%s="%s"`, name, escapeLabelValue(labelValues[i])))
+	}
+	if extraName != "" {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extraName, extraValue))
+	}
+	labels := ""
+	if len(pairs) > 0 {
+		labels = "{" + strings.Join(pairs, ",") + "}"
+	}
+	fmt.Fprintf(w, "%s%s%s %s\n", d.name, suffix, labels, formatFloat(v))
+}
+
+func (d *desc) key(labelValues []string) string {
+	if len(labelValues) != len(d.labelNames) {
+		panic(fmt.Sprintf(
+			"metric %s: expected %d label values, got %d",
+			d.name, len(d.labelNames), len(labelValues)))
+	}
+	return strings.Join(labelValues, "\xff")
+}
+
+// CounterVec is a family of counters partitioned by label values.
+type CounterVec struct {
+	desc
+	sync.Mutex
+	values map[string]*counter
+}
+
+type counter struct {
+	labelValues []string
+	value       float64
+}
+
+// NewCounterVec returns and registers a new CounterVec.
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{
+		desc:   desc{name, help, "counter", labelNames},
+		values: map[string]*counter{},
+	}
+	Register(c)
+	return c
+}
+
+// Inc increments the counter with the label values by one.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds v, which must not be negative, to the counter with the label
+// values.
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	k := c.key(labelValues)
+	c.Lock()
+	defer c.Unlock()
+	ctr, ok := c.values[k]
+	if !ok {
+		ctr = &counter{labelValues: copyStrings(labelValues)}
+		c.values[k] = ctr
+	}
+	ctr.value += v
+}
+
+// WriteText writes the counters in the Prometheus text exposition format.
+func (c *CounterVec) WriteText(w io.Writer) error {
+	c.Lock()
+	defer c.Unlock()
+	c.writeHeader(w)
+	for _, k := range sortedKeys(c.values) {
+		ctr := c.values[k]
+		c.writeSample(w, "", ctr.labelValues, "", "", ctr.value)
+	}
+	return nil
+}
+
+// HistogramVec is a family of histograms partitioned by label values.
+type HistogramVec struct {
+	desc
+	sync.Mutex
+	buckets []float64
+	values  map[string]*histogram
+}
+
+type histogram struct {
+	labelValues []string
+	counts      []uint64
+	count       uint64
+	sum         float64
+}
+
+// NewHistogramVec returns and registers a new HistogramVec. DefaultBuckets
+// are used if buckets is nil.
+func NewHistogramVec(
+	name, help string,
+	buckets []float64,
+	labelNames ...string) *HistogramVec {
+
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	h := &HistogramVec{
+		desc:    desc{name, help, "histogram", labelNames},
+		buckets: buckets,
+		values:  map[string]*histogram{},
+	}
+	Register(h)
+	return h
+}
+
+// Observe records the value in the histogram with the label values.
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	k := h.key(labelValues)
+	h.Lock()
+	defer h.Unlock()
+	hist, ok := h.values[k]
+	if !ok {
+		hist = &histogram{
+			labelValues: copyStrings(labelValues),
+			counts:      make([]uint64, len(h.buckets)),
+		}
+		h.values[k] = hist
+	}
+	for i, ub := range h.buckets {
+		if v <= ub {
+			hist.counts[i]++
+		}
+	}
+	hist.count++
+	hist.sum += v
+}
+
+// ObserveSince records the seconds elapsed since start in the histogram
+// with the label values.
+func (h *HistogramVec) ObserveSince(start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+// WriteText writes the histograms in the Prometheus text exposition format.
+func (h *HistogramVec) WriteText(w io.Writer) error {
+	h.Lock()
+	defer h.Unlock()
+	h.writeHeader(w)
+	for _, k := range sortedKeys(h.values) {
+		hist := h.values[k]
+		for i, ub := range h.buckets {
+			h.writeSample(w, "_bucket", hist.labelValues,
+				"le", formatFloat(ub), float64(hist.counts[i]))
+		}
+		h.writeSample(w, "_bucket", hist.labelValues,
+			"le", "+Inf", float64(hist.count))
+		h.writeSample(w, "_sum", hist.labelValues, "", "", hist.sum)
+		h.writeSample(
+			w, "_count", hist.labelValues, "", "", float64(hist.count))
+	}
+	return nil
+}
+
+// GaugeCollectFunc collects the values of a family of gauges when they are
+// written by invoking emit once for each set of label values.
+type GaugeCollectFunc func(emit func(v float64, labelValues ...string))
+
+// GaugeFunc is a family of gauges whose values are collected when they are
+// written.
+type GaugeFunc struct {
+	desc
+	collect GaugeCollectFunc
+}
+
+// NewGaugeFunc returns and registers a new GaugeFunc.
+func NewGaugeFunc(
+	name, help string,
+	collect GaugeCollectFunc,
+	labelNames ...string) *GaugeFunc {
+
+	g := &GaugeFunc{
+		desc:    desc{name, help, "gauge", labelNames},
+		collect: collect,
+	}
+	Register(g)
+	return g
+}
+
+// WriteText writes the gauges in the Prometheus text exposition format.
+func (g *GaugeFunc) WriteText(w io.Writer) error {
+	values := map[string]*counter{}
+	g.collect(func(v float64, labelValues ...string) {
+		k := g.key(labelValues)
+		if ctr, ok := values[k]; ok {
+			ctr.value += v
+			return
+		}
+		values[k] = &counter{labelValues: labelValues, value: v}
+	})
+	g.writeHeader(w)
+	for _, k := range sortedKeys(values) {
+		g.writeSample(w, "", values[k].labelValues, "", "", values[k].value)
+	}
+	return nil
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+	switch tm := m.(type) {
+	case map[string]*counter:
+		for k := range tm {
+			keys = append(keys, k)
+		}
+	case map[string]*histogram:
+		for k := range tm {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func copyStrings(s []string) []string {
+	return append([]string{}, s...)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueReplacer = strings.NewReplacer(
+		`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
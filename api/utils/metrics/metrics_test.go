@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounterVec(t *testing.T) {
+	c := NewCounterVec("test_counter_total", "A test counter.", "a", "b")
+	c.Inc("x", `y"z`)
+	c.Add(2, "x", `y"z`)
+	c.Inc("w", "v")
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, c.WriteText(buf))
+	assert.Equal(t, `# HELP test_counter_total A test counter.
+# TYPE test_counter_total counter
+test_counter_total{a="w",b="v"} 1
+test_counter_total{a="x",b="y\"z"} 3
+`, buf.String())
+
+	assert.Panics(t, func() { c.Inc("x") })
+}
+
+func TestHistogramVec(t *testing.T) {
+	h := NewHistogramVec(
+		"test_duration_seconds", "A test histogram.", []float64{1, 5}, "op")
+	h.Observe(0.5, "get")
+	h.Observe(2, "get")
+	h.Observe(10, "get")
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, h.WriteText(buf))
+	assert.Equal(t, `# HELP test_duration_seconds A test histogram.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{op="get",le="1"} 1
+test_duration_seconds_bucket{op="get",le="5"} 2
+test_duration_seconds_bucket{op="get",le="+Inf"} 3
+test_duration_seconds_sum{op="get"} 12.5
+test_duration_seconds_count{op="get"} 3
+`, buf.String())
+}
+
+func TestGaugeFunc(t *testing.T) {
+	g := NewGaugeFunc("test_gauge", "A test gauge.",
+		func(emit func(float64, ...string)) {
+			emit(1, "a")
+			emit(2, "a")
+			emit(4, "b")
+		}, "name")
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, g.WriteText(buf))
+	assert.Equal(t, `# HELP test_gauge A test gauge.
+# TYPE test_gauge gauge
+test_gauge{name="a"} 3
+test_gauge{name="b"} 4
+`, buf.String())
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	NewCounterVec("test_duplicate_total", "A test counter.")
+	assert.Panics(t, func() {
+		NewCounterVec("test_duplicate_total", "A test counter.")
+	})
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, WriteText(buf))
+	assert.Contains(t, buf.String(), "# TYPE test_duplicate_total counter\n")
+}
@@ -0,0 +1,347 @@
+/*
+Package paging provides the pagination and sorting of the collections returned
+by the libStorage API.
+
+While a whole collection is returned as a JSON object keyed by service name
+and object ID, a page of it is returned as a JSON array so that the objects
+appear in the order in which they are sorted. A collection is always sorted
+by its service names and object IDs after the sort attribute so that pages
+are stable.
+*/
+package paging
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+	"github.com/codedellemc/libstorage/api/utils/filters"
+)
+
+// Item is an object in a collection that is being paged.
+type Item struct {
+
+	// Service is the name of the service to which the object belongs.
+	Service string
+
+	// ID is the object's ID.
+	ID string
+
+	// Attrs returns the object's attributes.
+	Attrs filters.AttributeFunc
+
+	// Object is the object.
+	Object interface{}
+}
+
+// Result is a page of a collection. The result marshals to JSON as the
+// ordered list of objects it wraps.
+type Result struct {
+
+	// Items is the page of the collection in the order in which it is sorted.
+	Items interface{}
+
+	// Next is the continuation token for the next page.
+	Next string
+}
+
+// NextMarker returns the continuation token for the next page.
+func (r *Result) NextMarker() string {
+	return r.Next
+}
+
+// MarshalJSON marshals the page of the collection to JSON.
+func (r *Result) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Items)
+}
+
+// marker is the decoded form of a continuation token. It records the sort
+// order and the position of the last object in the previous page.
+type marker struct {
+	Sort    string `json:"sort,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Service string `json:"service,omitempty"`
+	ID      string `json:"id"`
+}
+
+// ParsePageOpts parses the limit, offset, marker, and sort options from the
+// store. A nil value is returned if none of the options are present.
+func ParsePageOpts(store types.Store) (*types.PageOpts, error) {
+	if !store.IsSet("limit") && !store.IsSet("offset") &&
+		!store.IsSet("marker") && !store.IsSet("sort") {
+		return nil, nil
+	}
+
+	opts := &types.PageOpts{}
+
+	for _, k := range []string{"limit", "offset"} {
+		if !store.IsSet(k) {
+			continue
+		}
+		v, err := strconv.Atoi(store.GetString(k))
+		if err != nil || v < 0 {
+			return nil, utils.NewBadPageOptsErr(k, store.Get(k))
+		}
+		if k == "limit" {
+			opts.Limit = v
+		} else {
+			opts.Offset = v
+		}
+	}
+
+	if store.IsSet("marker") {
+		opts.Marker = store.GetString("marker")
+	}
+	if store.IsSet("sort") {
+		opts.Sort = store.GetString("sort")
+	}
+
+	return opts, nil
+}
+
+// Paginate sorts the items and returns the page of them specified by the
+// options along with the continuation token for the next page.
+func Paginate(items []*Item, opts *types.PageOpts) ([]*Item, string, error) {
+
+	attr, desc := parseSort(opts.Sort)
+
+	s := &itemSorter{items: items, attr: attr, desc: desc}
+	sort.Sort(s)
+
+	start := 0
+	if opts.Marker != "" {
+		m, err := decodeMarker(opts.Marker)
+		if err != nil || !strings.EqualFold(m.Sort, opts.Sort) {
+			return nil, "", utils.NewBadPageOptsErr("marker", opts.Marker)
+		}
+		start = sort.Search(len(items), func(i int) bool {
+			return s.compare(
+				s.value(items[i]), items[i].Service, items[i].ID,
+				m.Value, m.Service, m.ID) > 0
+		})
+	}
+
+	start += opts.Offset
+	if start > len(items) {
+		start = len(items)
+	}
+
+	end := len(items)
+	if opts.Limit > 0 && start+opts.Limit < end {
+		end = start + opts.Limit
+	}
+
+	page := items[start:end]
+	if end == len(items) || len(page) == 0 {
+		return page, "", nil
+	}
+
+	last := page[len(page)-1]
+	next, err := encodeMarker(&marker{
+		Sort:    opts.Sort,
+		Value:   s.value(last),
+		Service: last.Service,
+		ID:      last.ID,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return page, next, nil
+}
+
+// ServiceVolumeMap returns the page of the volumes specified by the options.
+// The page's items are a []*types.ServiceVolume.
+func ServiceVolumeMap(
+	objMap types.ServiceVolumeMap, opts *types.PageOpts) (*Result, error) {
+
+	items := []*Item{}
+	for service, volMap := range objMap {
+		for _, v := range volMap {
+			items = append(items, &Item{
+				Service: service,
+				ID:      v.ID,
+				Attrs:   filters.VolumeAttributes(v),
+				Object:  v,
+			})
+		}
+	}
+
+	page, next, err := Paginate(items, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := []*types.ServiceVolume{}
+	for _, i := range page {
+		reply = append(reply, &types.ServiceVolume{
+			Service: i.Service,
+			Volume:  i.Object.(*types.Volume),
+		})
+	}
+
+	return &Result{Items: reply, Next: next}, nil
+}
+
+// VolumeMap returns the page of the volumes specified by the options. The
+// page's items are a []*types.Volume.
+func VolumeMap(
+	objMap types.VolumeMap, opts *types.PageOpts) (*Result, error) {
+
+	items := []*Item{}
+	for _, v := range objMap {
+		items = append(items, &Item{
+			ID:     v.ID,
+			Attrs:  filters.VolumeAttributes(v),
+			Object: v,
+		})
+	}
+
+	page, next, err := Paginate(items, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := []*types.Volume{}
+	for _, i := range page {
+		reply = append(reply, i.Object.(*types.Volume))
+	}
+
+	return &Result{Items: reply, Next: next}, nil
+}
+
+// ServiceSnapshotMap returns the page of the snapshots specified by the
+// options. The page's items are a []*types.ServiceSnapshot.
+func ServiceSnapshotMap(
+	objMap types.ServiceSnapshotMap, opts *types.PageOpts) (*Result, error) {
+
+	items := []*Item{}
+	for service, snapMap := range objMap {
+		for _, s := range snapMap {
+			items = append(items, &Item{
+				Service: service,
+				ID:      s.ID,
+				Attrs:   filters.SnapshotAttributes(s),
+				Object:  s,
+			})
+		}
+	}
+
+	page, next, err := Paginate(items, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := []*types.ServiceSnapshot{}
+	for _, i := range page {
+		reply = append(reply, &types.ServiceSnapshot{
+			Service:  i.Service,
+			Snapshot: i.Object.(*types.Snapshot),
+		})
+	}
+
+	return &Result{Items: reply, Next: next}, nil
+}
+
+// SnapshotMap returns the page of the snapshots specified by the options.
+// The page's items are a []*types.Snapshot.
+func SnapshotMap(
+	objMap types.SnapshotMap, opts *types.PageOpts) (*Result, error) {
+
+	items := []*Item{}
+	for _, s := range objMap {
+		items = append(items, &Item{
+			ID:     s.ID,
+			Attrs:  filters.SnapshotAttributes(s),
+			Object: s,
+		})
+	}
+
+	page, next, err := Paginate(items, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := []*types.Snapshot{}
+	for _, i := range page {
+		reply = append(reply, i.Object.(*types.Snapshot))
+	}
+
+	return &Result{Items: reply, Next: next}, nil
+}
+
+func parseSort(s string) (string, bool) {
+	if strings.HasPrefix(s, "-") {
+		return s[1:], true
+	}
+	return strings.TrimPrefix(s, "+"), false
+}
+
+type itemSorter struct {
+	items []*Item
+	attr  string
+	desc  bool
+}
+
+func (s *itemSorter) Len() int {
+	return len(s.items)
+}
+
+func (s *itemSorter) Swap(i, j int) {
+	s.items[i], s.items[j] = s.items[j], s.items[i]
+}
+
+func (s *itemSorter) Less(i, j int) bool {
+	a, b := s.items[i], s.items[j]
+	return s.compare(
+		s.value(a), a.Service, a.ID,
+		s.value(b), b.Service, b.ID) < 0
+}
+
+func (s *itemSorter) value(i *Item) string {
+	if s.attr == "" || i.Attrs == nil {
+		return ""
+	}
+	v, _ := i.Attrs(s.attr)
+	return v
+}
+
+func (s *itemSorter) compare(
+	aValue, aService, aID, bValue, bService, bID string) int {
+
+	c := filters.Compare(aValue, bValue)
+	if c == 0 {
+		c = strings.Compare(aService, bService)
+	}
+	if c == 0 {
+		c = strings.Compare(aID, bID)
+	}
+	if s.desc {
+		return -c
+	}
+	return c
+}
+
+func encodeMarker(m *marker) (string, error) {
+	buf, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func decodeMarker(s string) (*marker, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	m := &marker{}
+	if err := json.Unmarshal(buf, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,182 @@
+package paging
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func newTestVolumeMap() types.ServiceVolumeMap {
+	return types.ServiceVolumeMap{
+		"vfs-000": types.VolumeMap{
+			"vol-000": &types.Volume{ID: "vol-000", Size: 30},
+			"vol-001": &types.Volume{ID: "vol-001", Size: 10},
+		},
+		"vfs-001": types.VolumeMap{
+			"vol-000": &types.Volume{ID: "vol-000", Size: 20},
+		},
+	}
+}
+
+func pageIDs(r *Result) []string {
+	ids := []string{}
+	switch items := r.Items.(type) {
+	case []*types.ServiceVolume:
+		for _, i := range items {
+			ids = append(ids, i.Service+"/"+i.Volume.ID)
+		}
+	case []*types.Volume:
+		for _, i := range items {
+			ids = append(ids, i.ID)
+		}
+	}
+	return ids
+}
+
+func TestServiceVolumeMapLimitAndMarker(t *testing.T) {
+	objMap := newTestVolumeMap()
+
+	r, err := ServiceVolumeMap(objMap, &types.PageOpts{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"vfs-000/vol-000", "vfs-000/vol-001"}, pageIDs(r))
+	assert.NotEmpty(t, r.NextMarker())
+
+	r, err = ServiceVolumeMap(
+		objMap, &types.PageOpts{Limit: 2, Marker: r.NextMarker()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"vfs-001/vol-000"}, pageIDs(r))
+	assert.Empty(t, r.NextMarker())
+}
+
+func TestServiceVolumeMapSort(t *testing.T) {
+	objMap := newTestVolumeMap()
+
+	r, err := ServiceVolumeMap(objMap, &types.PageOpts{Limit: 2, Sort: "size"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"vfs-000/vol-001", "vfs-001/vol-000"}, pageIDs(r))
+
+	r, err = ServiceVolumeMap(
+		objMap, &types.PageOpts{Limit: 2, Sort: "size", Marker: r.NextMarker()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"vfs-000/vol-000"}, pageIDs(r))
+
+	r, err = ServiceVolumeMap(objMap, &types.PageOpts{Sort: "-size"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t,
+		[]string{"vfs-000/vol-000", "vfs-001/vol-000", "vfs-000/vol-001"},
+		pageIDs(r))
+}
+
+func TestVolumeMapOffset(t *testing.T) {
+	r, err := VolumeMap(
+		newTestVolumeMap()["vfs-000"], &types.PageOpts{Offset: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"vol-001"}, pageIDs(r))
+	assert.Empty(t, r.NextMarker())
+}
+
+func TestResultMarshalJSON(t *testing.T) {
+	r, err := VolumeMap(
+		newTestVolumeMap()["vfs-000"], &types.PageOpts{Sort: "size"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the page is marshaled as a list in the order in which it is sorted
+	var page []*types.Volume
+	if err := json.Unmarshal(buf, &page); err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, page, 2)
+	assert.Equal(t, "vol-001", page[0].ID)
+	assert.Equal(t, "vol-000", page[1].ID)
+}
+
+func TestPaginateBadMarker(t *testing.T) {
+	_, err := ServiceVolumeMap(
+		newTestVolumeMap(), &types.PageOpts{Marker: "invalid"})
+	assert.Error(t, err)
+	assert.IsType(t, &types.ErrBadPageOpts{}, err)
+
+	r, err := ServiceVolumeMap(
+		newTestVolumeMap(), &types.PageOpts{Limit: 1, Sort: "size"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ServiceVolumeMap(
+		newTestVolumeMap(), &types.PageOpts{Marker: r.NextMarker()})
+	assert.Error(t, err)
+}
+
+func TestPaginateMixedValues(t *testing.T) {
+	vals := map[string]string{
+		"vol-000": "10",
+		"vol-001": "9a",
+		"vol-002": "9",
+		"vol-003": "abc",
+		"vol-004": "ABC",
+		"vol-005": "-1",
+	}
+	newItems := func() []*Item {
+		items := []*Item{}
+		for id, v := range vals {
+			v := v
+			items = append(items, &Item{
+				ID: id,
+				Attrs: func(name string) (string, bool) {
+					return v, true
+				},
+			})
+		}
+		return items
+	}
+
+	// numbers are sorted before other values, and every item appears in
+	// exactly one page
+	for sort, want := range map[string][]string{
+		"name": {
+			"vol-005", "vol-002", "vol-000",
+			"vol-001", "vol-003", "vol-004",
+		},
+		"-name": {
+			"vol-004", "vol-003", "vol-001",
+			"vol-000", "vol-002", "vol-005",
+		},
+	} {
+		ids := []string{}
+		opts := &types.PageOpts{Limit: 4, Sort: sort}
+		for {
+			page, next, err := Paginate(newItems(), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, i := range page {
+				ids = append(ids, i.ID)
+			}
+			if next == "" {
+				break
+			}
+			opts.Marker = next
+		}
+		assert.Equal(t, want, ids, sort)
+	}
+}
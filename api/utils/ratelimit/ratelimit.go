@@ -0,0 +1,135 @@
+// Package ratelimit provides token bucket rate limiters.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the buckets that are full, and are therefore no
+// different from new buckets, are removed from a limiter.
+const sweepInterval = time.Minute
+
+// Limit is the rate at which tokens are added to a bucket and the maximum
+// number of tokens the bucket holds.
+type Limit struct {
+
+	// Rate is the number of tokens added to the bucket each second.
+	Rate float64
+
+	// Burst is the maximum number of tokens in the bucket.
+	Burst int
+}
+
+// Enabled returns a flag indicating whether the limit restricts anything.
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// Request identifies a bucket from which to take a token and the bucket's
+// limit.
+type Request struct {
+	Key   string
+	Limit Limit
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  Limit
+}
+
+// refill adds the tokens accumulated since the bucket was last refilled.
+func (b *bucket) refill(now time.Time, limit Limit) {
+	b.tokens += now.Sub(b.last).Seconds() * b.limit.Rate
+	b.last = now
+	b.limit = limit
+	if max := float64(limit.Burst); b.tokens > max {
+		b.tokens = max
+	}
+}
+
+// wait returns the time until the bucket holds a token.
+func (b *bucket) wait() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	secs := (1 - b.tokens) / b.limit.Rate
+	return time.Duration(math.Ceil(secs * float64(time.Second)))
+}
+
+// Limiter is a collection of token buckets identified by keys.
+type Limiter struct {
+	sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// New returns a new limiter.
+func New() *Limiter {
+	return &Limiter{
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+// Take removes a token from each of the requested buckets if all of them
+// hold a token, in which case zero is returned. Otherwise no tokens are
+// removed and the time until all of the buckets hold a token is returned.
+// Requests with a limit that is not enabled are ignored.
+func (l *Limiter) Take(reqs ...Request) time.Duration {
+	l.Lock()
+	defer l.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	var (
+		wait    time.Duration
+		buckets = make([]*bucket, 0, len(reqs))
+	)
+
+	for _, r := range reqs {
+		if !r.Limit.Enabled() {
+			continue
+		}
+		b, ok := l.buckets[r.Key]
+		if !ok {
+			b = &bucket{
+				tokens: float64(r.Limit.Burst),
+				last:   now,
+				limit:  r.Limit,
+			}
+			l.buckets[r.Key] = b
+		}
+		b.refill(now, r.Limit)
+		if w := b.wait(); w > wait {
+			wait = w
+		}
+		buckets = append(buckets, b)
+	}
+
+	if wait > 0 {
+		return wait
+	}
+	for _, b := range buckets {
+		b.tokens--
+	}
+	return 0
+}
+
+// sweep removes the buckets that have refilled completely.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for k, b := range l.buckets {
+		b.refill(now, b.limit)
+		if b.tokens >= float64(b.limit.Burst) {
+			delete(l.buckets, k)
+		}
+	}
+}
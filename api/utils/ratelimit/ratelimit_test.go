@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLimiter() (*Limiter, *time.Time) {
+	now := time.Unix(1491238950, 0)
+	l := New()
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestTake(t *testing.T) {
+	l, now := newTestLimiter()
+	r := Request{Key: "akutz", Limit: Limit{Rate: 2, Burst: 3}}
+
+	assert.Equal(t, time.Duration(0), l.Take(r))
+	assert.Equal(t, time.Duration(0), l.Take(r))
+	assert.Equal(t, time.Duration(0), l.Take(r))
+	assert.Equal(t, 500*time.Millisecond, l.Take(r))
+
+	*now = now.Add(250 * time.Millisecond)
+	assert.Equal(t, 250*time.Millisecond, l.Take(r))
+
+	*now = now.Add(250 * time.Millisecond)
+	assert.Equal(t, time.Duration(0), l.Take(r))
+	assert.Equal(t, 500*time.Millisecond, l.Take(r))
+
+	// other keys have their own buckets
+	assert.Equal(t, time.Duration(0), l.Take(Request{
+		Key: "cduchesne", Limit: r.Limit}))
+}
+
+func TestTakeAll(t *testing.T) {
+	l, now := newTestLimiter()
+	route := Request{Key: "route", Limit: Limit{Rate: 1, Burst: 2}}
+	svc := Request{Key: "svc", Limit: Limit{Rate: 1, Burst: 1}}
+
+	assert.Equal(t, time.Duration(0), l.Take(route, svc))
+
+	// the service bucket is empty so no token is taken from the route's
+	assert.Equal(t, time.Second, l.Take(route, svc))
+	assert.Equal(t, time.Duration(0), l.Take(route))
+	assert.Equal(t, time.Second, l.Take(route))
+
+	*now = now.Add(time.Second)
+	assert.Equal(t, time.Duration(0), l.Take(route, svc))
+}
+
+func TestTakeDisabled(t *testing.T) {
+	l, _ := newTestLimiter()
+	r := Request{Key: "akutz"}
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, time.Duration(0), l.Take(r))
+	}
+	assert.Len(t, l.buckets, 0)
+}
+
+func TestSweep(t *testing.T) {
+	l, now := newTestLimiter()
+	r := Request{Key: "akutz", Limit: Limit{Rate: 1, Burst: 1}}
+	assert.Equal(t, time.Duration(0), l.Take(r))
+	assert.Len(t, l.buckets, 1)
+
+	*now = now.Add(sweepInterval)
+	assert.Equal(t, time.Duration(0), l.Take(Request{}))
+	assert.Len(t, l.buckets, 0)
+}
@@ -34,12 +34,35 @@ var (
 	// resource.
 	ServiceSnapshotMapSchema = buildSchemaVar("serviceSnapshotMap")
 
+	// ServiceQuotaMapSchema is the JSON schema for the ServiceQuotaMap
+	// resource.
+	ServiceQuotaMapSchema = buildSchemaVar("serviceQuotaMap")
+
+	// AuditRecordsSchema is the JSON schema for a list of AuditRecord
+	// resources.
+	AuditRecordsSchema = buildSchemaVar("auditRecords")
+
 	// VolumeMapSchema is the JSON schema for the VolumeMap resource.
 	VolumeMapSchema = buildSchemaVar("volumeMap")
 
 	// SnapshotMapSchema is the JSON schema for the SnapshotMap resource.
 	SnapshotMapSchema = buildSchemaVar("snapshotMap")
 
+	// VolumeListSchema is the JSON schema for a page of Volume resources.
+	VolumeListSchema = buildSchemaVar("volumeList")
+
+	// SnapshotListSchema is the JSON schema for a page of Snapshot
+	// resources.
+	SnapshotListSchema = buildSchemaVar("snapshotList")
+
+	// ServiceVolumeListSchema is the JSON schema for a page of the Volume
+	// resources for all services.
+	ServiceVolumeListSchema = buildSchemaVar("serviceVolumeList")
+
+	// ServiceSnapshotListSchema is the JSON schema for a page of the
+	// Snapshot resources for all services.
+	ServiceSnapshotListSchema = buildSchemaVar("serviceSnapshotList")
+
 	// SnapshotSchema is the JSON schema for the Snapshot resource.
 	SnapshotSchema = buildSchemaVar("snapshot")
 
@@ -63,6 +86,10 @@ var (
 	// request.
 	VolumeCopyRequestSchema = buildSchemaVar("volumeCopyRequest")
 
+	// VolumeResizeRequestSchema is the JSON schema for a Volume resize
+	// request.
+	VolumeResizeRequestSchema = buildSchemaVar("volumeResizeRequest")
+
 	// VolumeSnapshotRequestSchema is the JSON schema for a Volume snapshot
 	// request.
 	VolumeSnapshotRequestSchema = buildSchemaVar("volumeSnapshotRequest")
@@ -79,6 +106,14 @@ var (
 	// request.
 	VolumeDetachRequestSchema = buildSchemaVar("volumeDetachRequest")
 
+	// ServiceCreateRequestSchema is the JSON schema for a Service create
+	// request.
+	ServiceCreateRequestSchema = buildSchemaVar("serviceCreateRequest")
+
+	// ServiceUpdateRequestSchema is the JSON schema for a Service update
+	// request.
+	ServiceUpdateRequestSchema = buildSchemaVar("serviceUpdateRequest")
+
 	// SnapshotCopyRequestSchema is the JSON schema for a Snapshot copy
 	// request.
 	SnapshotCopyRequestSchema = buildSchemaVar("snapshotCopyRequest")
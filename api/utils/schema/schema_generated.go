@@ -61,6 +61,7 @@ const (
                     "type": "string",
                     "description": "The volume status."
                 },
+                "labels": { "$ref": "#/definitions/labels" },
                 "fields": { "$ref": "#/definitions/fields" }
             },
             "required": [ "id", "name" ],
@@ -283,6 +284,14 @@ const (
                     "type": "string",
                     "description": "The file's MD5 checksum. This can be used to determine if a local copy of the executor needs to be updated."
                 },
+                "sha256checksum": {
+                    "type": "string",
+                    "description": "The file's SHA-256 checksum. This can be used to determine if a local copy of the executor needs to be updated and to verify a downloaded executor."
+                },
+                "signature": {
+                    "type": "string",
+                    "description": "The base64-encoded detached signature of the file's SHA-256 checksum."
+                },
                 "size": {
                     "type": "number",
                     "description": "The size of the executor, in bytes."
@@ -327,6 +336,16 @@ const (
         },
 
 
+        "labels": {
+            "type": "object",
+            "description": "Labels are user-defined key/value pairs that are used to organize and select volumes. Label keys may not contain whitespace or the characters ',', '=', or '!'.",
+            "patternProperties": {
+                "^[^\\s,=!]+$": { "type": "string" }
+            },
+            "additionalProperties": false
+        },
+
+
         "volumeMap": {
             "type": "object",
             "patternProperties": {
@@ -345,6 +364,18 @@ const (
         },
 
 
+        "volumeList": {
+            "type": "array",
+            "items": { "$ref": "#/definitions/volume" }
+        },
+
+
+        "snapshotList": {
+            "type": "array",
+            "items": { "$ref": "#/definitions/snapshot" }
+        },
+
+
         "taskMap": {
             "type": "object",
             "patternProperties": {
@@ -354,6 +385,146 @@ const (
         },
 
 
+        "quota": {
+            "type": "object",
+            "description": "Quota is an auth subject's quota and usage for a storage service.",
+            "properties": {
+                "subject": {
+                    "type": "string",
+                    "description": "The auth subject to which the quota applies."
+                },
+                "limits": {
+                    "type": "object",
+                    "description": "The quota's limits. A missing limit is not enforced.",
+                    "properties": {
+                        "volumes": {
+                            "type": "number",
+                            "description": "The maximum number of volumes."
+                        },
+                        "size": {
+                            "type": "number",
+                            "description": "The maximum total size of the volumes (GB)."
+                        },
+                        "maxVolumeSize": {
+                            "type": "number",
+                            "description": "The maximum size of a single volume (GB)."
+                        }
+                    },
+                    "additionalProperties": false
+                },
+                "usage": {
+                    "type": "object",
+                    "description": "The subject's usage.",
+                    "properties": {
+                        "volumes": {
+                            "type": "number",
+                            "description": "The number of volumes."
+                        },
+                        "size": {
+                            "type": "number",
+                            "description": "The total size of the volumes (GB)."
+                        }
+                    },
+                    "required": [ "volumes", "size" ],
+                    "additionalProperties": false
+                }
+            },
+            "required": [ "subject", "limits", "usage" ],
+            "additionalProperties": false
+        },
+
+
+        "quotaMap": {
+            "type": "object",
+            "patternProperties": {
+                "^.*$": { "$ref": "#/definitions/quota" }
+            },
+            "additionalProperties": false
+        },
+
+
+        "serviceQuotaMap": {
+            "type": "object",
+            "patternProperties": {
+                "^.+$": { "$ref": "#/definitions/quotaMap" }
+            },
+            "additionalProperties": false
+        },
+
+
+        "auditRecord": {
+            "type": "object",
+            "description": "AuditRecord is the record of a mutating API call.",
+            "properties": {
+                "time": {
+                    "type": "number",
+                    "description": "The epoch, in seconds, at which the call was received."
+                },
+                "route": {
+                    "type": "string",
+                    "description": "The name of the route that handled the call."
+                },
+                "method": {
+                    "type": "string",
+                    "description": "The call's HTTP method."
+                },
+                "service": {
+                    "type": "string",
+                    "description": "The name of the storage service targeted by the call."
+                },
+                "volumeID": {
+                    "type": "string",
+                    "description": "The ID of the volume targeted or created by the call."
+                },
+                "snapshotID": {
+                    "type": "string",
+                    "description": "The ID of the snapshot targeted or created by the call."
+                },
+                "taskID": {
+                    "type": "number",
+                    "description": "The ID of the task that executed the call."
+                },
+                "subject": {
+                    "type": "string",
+                    "description": "The subject of the call's auth token."
+                },
+                "txID": {
+                    "type": "string",
+                    "description": "The ID of the call's transaction."
+                },
+                "instanceID": {
+                    "type": "string",
+                    "description": "The ID of the instance from which the call was made."
+                },
+                "outcome": {
+                    "type": "string",
+                    "description": "The outcome of the call.",
+                    "enum": [ "success", "accepted", "failure" ]
+                },
+                "status": {
+                    "type": "number",
+                    "description": "The HTTP status of the call's response."
+                },
+                "error": {
+                    "type": "string",
+                    "description": "The error that caused the call to fail."
+                },
+                "duration": {
+                    "type": "number",
+                    "description": "The time, in milliseconds, taken to handle the call."
+                }
+            },
+            "required": [ "time", "route", "method", "outcome", "status", "duration" ],
+            "additionalProperties": false
+        },
+
+
+        "auditRecords": {
+            "type": "array",
+            "items": { "$ref": "#/definitions/auditRecord" }
+        },
+
+
         "serviceVolumeMap": {
             "type": "object",
             "patternProperties": {
@@ -372,6 +543,34 @@ const (
         },
 
 
+        "serviceVolumeList": {
+            "type": "array",
+            "items": {
+                "type": "object",
+                "properties": {
+                    "service": { "type": "string" },
+                    "volume": { "$ref": "#/definitions/volume" }
+                },
+                "required": [ "service", "volume" ],
+                "additionalProperties": false
+            }
+        },
+
+
+        "serviceSnapshotList": {
+            "type": "array",
+            "items": {
+                "type": "object",
+                "properties": {
+                    "service": { "type": "string" },
+                    "snapshot": { "$ref": "#/definitions/snapshot" }
+                },
+                "required": [ "service", "snapshot" ],
+                "additionalProperties": false
+            }
+        },
+
+
         "serviceTaskMap": {
             "type": "object",
             "patternProperties": {
@@ -452,6 +651,7 @@ const (
                 "type": {
                     "type": "string"
                 },
+                "labels": { "$ref": "#/definitions/labels" },
                 "opts": { "$ref" : "#/definitions/opts" }
             },
             "required": [ "name" ],
@@ -472,6 +672,22 @@ const (
         },
 
 
+        "volumeResizeRequest": {
+            "type": "object",
+            "properties": {
+                "size": {
+                    "type": "number"
+                },
+                "force": {
+                    "type": "boolean"
+                },
+                "opts": { "$ref" : "#/definitions/opts" }
+            },
+            "required": [ "size" ],
+            "additionalProperties": false
+        },
+
+
         "volumeSnapshotRequest": {
             "type": "object",
             "properties": {
@@ -523,6 +739,34 @@ const (
         },
 
 
+        "serviceCreateRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "config": {
+                    "type": "object"
+                }
+            },
+            "required": [ "name", "config" ],
+            "additionalProperties": false
+        },
+
+
+        "serviceUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "type": "object"
+                }
+            },
+            "required": [ "config" ],
+            "additionalProperties": false
+        },
+
+
         "snapshotCopyRequest": {
             "type": "object",
             "properties": {
@@ -73,5 +73,24 @@ func ParseAuthConfig(
 		f(types.ConfigServerAuthDeny, authConfig.Deny)
 	}
 
+	if isSetPrefix(config, prefix, types.ConfigServerAuthRoles, roots...) {
+		authConfig.Roles = getStringSliceMapPrefix(
+			config, prefix, types.ConfigServerAuthRoles, roots...)
+		f(types.ConfigServerAuthRoles, authConfig.Roles)
+	}
+
+	if isSetPrefix(config, prefix, types.ConfigServerAuthSubjects, roots...) {
+		authConfig.Subjects = getStringSliceMapPrefix(
+			config, prefix, types.ConfigServerAuthSubjects, roots...)
+		f(types.ConfigServerAuthSubjects, authConfig.Subjects)
+	}
+
+	if isSetPrefix(
+		config, prefix, types.ConfigServerAuthDefaultRoles, roots...) {
+		authConfig.DefaultRoles = getStringSlicePrefix(
+			config, prefix, types.ConfigServerAuthDefaultRoles, roots...)
+		f(types.ConfigServerAuthDefaultRoles, authConfig.DefaultRoles)
+	}
+
 	return authConfig, nil
 }
@@ -1,10 +1,14 @@
 package utils
 
 import (
+	"crypto/sha256"
 	"fmt"
+	"sort"
 	"strings"
 
 	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/types"
 )
 
 func isSet(
@@ -127,3 +131,70 @@ func getStringSlicePrefix(
 
 	return config.GetStringSlice(key)
 }
+
+// getStringSliceMapPrefix returns the map of string slices defined by the
+// child keys of the key. The first root at which the map is defined is used.
+func getStringSliceMapPrefix(
+	config gofig.Config,
+	prefix, key string,
+	roots ...string) map[string][]string {
+
+	keys := []string{}
+	for _, r := range roots {
+		keys = append(
+			keys, strings.Replace(key, prefix, fmt.Sprintf("%s.", r), 1))
+	}
+	keys = append(keys, key)
+
+	for _, k := range keys {
+		m, ok := config.Get(k).(map[string]interface{})
+		if !ok || len(m) == 0 {
+			continue
+		}
+		val := map[string][]string{}
+		for name := range m {
+			val[name] = config.GetStringSlice(fmt.Sprintf("%s.%s", k, name))
+		}
+		return val
+	}
+
+	return nil
+}
+
+// GetHosts returns the addresses of the hosts to which a client connects. The
+// host may be configured as a single address, a comma-separated list of
+// addresses, or a list of addresses, in the order in which they are tried.
+func GetHosts(config gofig.Config) []string {
+	return GetStringList(config, types.ConfigHost)
+}
+
+// GetStringList returns the values of a key that may be configured as a
+// comma-separated string or as a list. Empty values are omitted.
+func GetStringList(config gofig.Config, key string) []string {
+	var vals []string
+	if _, ok := config.Get(key).([]interface{}); ok {
+		vals = config.GetStringSlice(key)
+	} else {
+		vals = strings.Split(config.GetString(key), ",")
+	}
+	var list []string
+	for _, v := range vals {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// ConfigChecksum returns the SHA-256 checksum of the environment variables
+// with which the configuration is passed to the executor. Configurations with
+// the same checksum configure the executor the same way.
+func ConfigChecksum(config gofig.Config) string {
+	env := config.EnvVars()
+	sort.Strings(env)
+	h := sha256.New()
+	for _, ev := range env {
+		fmt.Fprintln(h, ev)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"time"
+
 	"github.com/akutz/goof"
 
 	"github.com/codedellemc/libstorage/api/types"
@@ -31,6 +33,24 @@ func NewNotFoundError(resourceID string) error {
 	}
 }
 
+// NewBadPageOptsErr returns a new ErrBadPageOpts error.
+func NewBadPageOptsErr(key string, val interface{}) error {
+	return &types.ErrBadPageOpts{Goof: goof.WithFields(goof.Fields{
+		"key":   key,
+		"value": val,
+	}, "invalid page option")}
+}
+
+// NewTaskCompletedError returns a new ErrTaskCompleted error.
+func NewTaskCompletedError(taskID int, state types.TaskState) error {
+	return &types.ErrTaskCompleted{
+		Goof: goof.WithFields(goof.Fields{
+			"taskID": taskID,
+			"state":  state,
+		}, "task already completed"),
+	}
+}
+
 // NewMissingInstanceIDError returns a new ErrMissingInstanceID error.
 func NewMissingInstanceIDError(service string) error {
 	return &types.ErrMissingInstanceID{
@@ -71,3 +91,79 @@ func NewBadFilterErr(filter string, err error) error {
 	return &types.ErrBadFilter{Goof: goof.WithFieldE(
 		"filter", filter, "bad filter", err)}
 }
+
+// NewQuotaExceededError returns a new ErrQuotaExceeded error.
+func NewQuotaExceededError(
+	service, subject, limit string, max, requested int64) error {
+	return &types.ErrQuotaExceeded{Goof: goof.WithFields(goof.Fields{
+		"service":   service,
+		"subject":   subject,
+		"limit":     limit,
+		"max":       max,
+		"requested": requested,
+	}, "quota exceeded")}
+}
+
+// NewQuotaSizeRequiredError returns a new ErrQuotaSizeRequired error.
+func NewQuotaSizeRequiredError(service, subject, limit string) error {
+	return &types.ErrQuotaSizeRequired{Goof: goof.WithFields(goof.Fields{
+		"service": service,
+		"subject": subject,
+		"limit":   limit,
+	}, "volume size required by quota")}
+}
+
+// NewForbiddenError returns a new ErrForbidden error.
+func NewForbiddenError(subject, route string) error {
+	return &types.ErrForbidden{Goof: goof.WithFields(goof.Fields{
+		"subject": subject,
+		"route":   route,
+	}, "access to route not granted")}
+}
+
+// NewServiceExistsError returns a new ErrServiceExists error.
+func NewServiceExistsError(service string) error {
+	return &types.ErrServiceExists{
+		Goof: goof.WithField("service", service, "service already exists"),
+	}
+}
+
+// NewServiceStoppedError returns a new ErrServiceStopped error.
+func NewServiceStoppedError(service string) error {
+	return &types.ErrServiceStopped{
+		Goof: goof.WithField("service", service, "service stopped"),
+	}
+}
+
+// NewRateLimitedError returns a new ErrRateLimited error.
+func NewRateLimitedError(key string, retryAfter int) error {
+	return &types.ErrRateLimited{Goof: goof.WithFields(goof.Fields{
+		"key":        key,
+		"retryAfter": retryAfter,
+	}, "rate limit exceeded")}
+}
+
+// NewIdempotencyKeyReusedError returns a new ErrIdempotencyKeyReused error.
+func NewIdempotencyKeyReusedError(key string) error {
+	return &types.ErrIdempotencyKeyReused{
+		Goof: goof.WithField(
+			"key", key, "idempotency key reused with a different request"),
+	}
+}
+
+// NewExecutorLockTimeoutError returns a new ErrExecutorLockTimeout error. The
+// owner is the PID of the process that holds the lock, if known.
+func NewExecutorLockTimeoutError(
+	path string, owner int, timeout time.Duration) error {
+
+	fields := goof.Fields{
+		"path":    path,
+		"timeout": timeout.String(),
+	}
+	if owner > 0 {
+		fields["owner"] = owner
+	}
+	return &types.ErrExecutorLockTimeout{
+		Goof: goof.WithFields(fields, "timed out acquiring executor lock"),
+	}
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/akutz/goof"
+)
+
+// WriteFileAtomic writes the data to the file by writing it to a temporary
+// file in the same directory and then renaming the temporary file. Readers
+// never observe a partially written file.
+func WriteFileAtomic(filePath string, data []byte) error {
+	dir, name := path.Split(filePath)
+	if dir == "" {
+		dir = "."
+	}
+	f, err := ioutil.TempFile(dir, "."+name)
+	if err != nil {
+		return goof.WithFieldE("path", dir, "error creating temp file", err)
+	}
+	tmpPath := f.Name()
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return goof.WithFieldE("path", tmpPath, "error writing temp file", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return goof.WithFieldE("path", tmpPath, "error syncing temp file", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return goof.WithFieldE("path", tmpPath, "error closing temp file", err)
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		os.Remove(tmpPath)
+		return goof.WithFieldE("path", filePath, "error renaming temp file", err)
+	}
+	return nil
+}
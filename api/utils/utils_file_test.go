@@ -0,0 +1,45 @@
+package utils_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+var _ = Describe("WriteFileAtomic", func() {
+
+	var tmpDir string
+
+	BeforeEach(func() {
+		td, err := ioutil.TempDir("", "")
+		if err != nil {
+			panic(err)
+		}
+		tmpDir = td
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	It("Replaces the file without leaving temp files", func() {
+		filePath := path.Join(tmpDir, "data.json")
+		Ω(utils.WriteFileAtomic(filePath, []byte("1"))).ShouldNot(HaveOccurred())
+		Ω(utils.WriteFileAtomic(filePath, []byte("2"))).ShouldNot(HaveOccurred())
+
+		buf, err := ioutil.ReadFile(filePath)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(buf)).Should(Equal("2"))
+
+		fis, err := ioutil.ReadDir(tmpDir)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(fis).Should(HaveLen(1))
+	})
+
+	It("Fails when the directory does not exist", func() {
+		filePath := path.Join(tmpDir, "missing", "data.json")
+		Ω(utils.WriteFileAtomic(filePath, []byte("1"))).Should(HaveOccurred())
+	})
+})
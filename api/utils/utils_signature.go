@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+
+	"github.com/akutz/goof"
+)
+
+// ParsePublicKey parses a PEM-encoded PKIX public key. RSA and ECDSA keys are
+// supported.
+func ParsePublicKey(buf []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(buf)
+	if block == nil {
+		return nil, goof.New("invalid pem-encoded public key")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch pub.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return pub, nil
+	}
+	return nil, goof.New("unsupported public key type")
+}
+
+// VerifySignature verifies the detached signature of a SHA-256 digest made
+// with the private key that corresponds to the given public key. RSA
+// signatures must use PKCS #1 v1.5 and ECDSA signatures must be ASN.1
+// encoded, such as those created by "openssl dgst -sha256 -sign".
+func VerifySignature(pub crypto.PublicKey, digest, sig []byte) error {
+	switch tk := pub.(type) {
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(
+			tk, crypto.SHA256, digest, sig); err != nil {
+			return goof.WithError("invalid signature", err)
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		var esig struct{ R, S *big.Int }
+		if _, err := asn1.Unmarshal(sig, &esig); err != nil {
+			return goof.WithError("invalid signature", err)
+		}
+		if !ecdsa.Verify(tk, digest, esig.R, esig.S) {
+			return goof.New("invalid signature")
+		}
+		return nil
+	}
+	return goof.New("unsupported public key type")
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodePublicKey(t *testing.T, pub crypto.PublicKey) []byte {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestVerifySignatureRSA(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte("lsx"))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, err := ParsePublicKey(encodePublicKey(t, &key.PublicKey))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NoError(t, VerifySignature(pub, digest[:], sig))
+
+	other := sha256.Sum256([]byte("lsx2"))
+	assert.Error(t, VerifySignature(pub, other[:], sig))
+}
+
+func TestVerifySignatureECDSA(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte("lsx"))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := asn1.Marshal(struct{ R, S interface{} }{r, s})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, err := ParsePublicKey(encodePublicKey(t, &key.PublicKey))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NoError(t, VerifySignature(pub, digest[:], sig))
+
+	other := sha256.Sum256([]byte("lsx2"))
+	assert.Error(t, VerifySignature(pub, other[:], sig))
+	assert.Error(t, VerifySignature(pub, digest[:], []byte("invalid")))
+}
+
+func TestParsePublicKeyInvalid(t *testing.T) {
+	_, err := ParsePublicKey([]byte("invalid"))
+	assert.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -27,6 +28,9 @@ import (
 var cmdRx = regexp.MustCompile(
 	`(?i)^((?:un?)?mounts?|supported|instanceid|nextdevice|localdevices|wait)$`)
 
+// errUsage is returned when a command's arguments are invalid.
+var errUsage = errors.New("invalid arguments")
+
 // Run runs the executor CLI.
 func Run() {
 
@@ -35,6 +39,11 @@ func Run() {
 	registry.ProcessRegisteredConfigs(ctx)
 
 	args := os.Args
+	if len(args) > 1 && strings.EqualFold(args[1], apitypes.LSXCmdServe) {
+		runServe(ctx, args[2:])
+		return
+	}
+
 	if len(args) < 3 {
 		printUsageAndExit()
 	}
@@ -45,8 +54,6 @@ func Run() {
 		os.Exit(1)
 	}
 
-	driverName := strings.ToLower(d.Name())
-
 	config, err := apiconfig.NewConfig(ctx)
 	if err != nil {
 		fmt.Fprintf(apitypes.Stderr, "error: %v\n", err)
@@ -60,17 +67,35 @@ func Run() {
 		os.Exit(1)
 	}
 
-	cmd := cmdRx.FindString(args[2])
-	if cmd == "" {
+	result, op, exitCode, err := execute(ctx, d, args[2:])
+	if err == errUsage {
 		printUsageAndExit()
 	}
-	store := utils.NewStore()
 
-	var (
-		result   interface{}
-		op       string
-		exitCode int
-	)
+	stdout, stderr, exitCode := encodeResult(result, op, exitCode, err)
+	apitypes.Stdout.Write(stdout)
+	fmt.Fprint(apitypes.Stderr, stderr)
+	os.Exit(exitCode)
+}
+
+// execute executes the command in the given arguments, ex. ["instanceID"],
+// with the given executor. The errUsage error is returned if the arguments
+// are invalid.
+func execute(
+	ctx apitypes.Context,
+	d apitypes.StorageExecutor,
+	args []string) (result interface{}, op string, exitCode int, err error) {
+
+	driverName := strings.ToLower(d.Name())
+
+	if len(args) == 0 {
+		return nil, "", 0, errUsage
+	}
+	cmd := cmdRx.FindString(args[0])
+	if cmd == "" {
+		return nil, "", 0, errUsage
+	}
+	store := utils.NewStore()
 
 	if strings.EqualFold(cmd, apitypes.LSXCmdSupported) {
 		op = apitypes.LSXCmdSupported
@@ -122,9 +147,9 @@ func Run() {
 				mountPath  string
 				mountOpts  = &apitypes.DeviceMountOpts{Opts: store}
 			)
-			mountArgs := args[3:]
+			mountArgs := args[1:]
 			if len(mountArgs) == 0 {
-				printUsageAndExit()
+				return nil, op, 0, errUsage
 			}
 
 			remArgs := []string{}
@@ -147,7 +172,7 @@ func Run() {
 			}
 
 			if len(remArgs) != 2 {
-				printUsageAndExit()
+				return nil, op, 0, errUsage
 			}
 
 			deviceName = remArgs[0]
@@ -167,10 +192,10 @@ func Run() {
 		if !ok {
 			err = apitypes.ErrNotImplemented
 		} else {
-			if len(args) < 4 {
-				printUsageAndExit()
+			if len(args) < 2 {
+				return nil, op, 0, errUsage
 			}
-			mountPath := args[3]
+			mountPath := args[1]
 			opErr := dd.Unmount(ctx, mountPath, store)
 			if opErr != nil {
 				err = opErr
@@ -196,12 +221,12 @@ func Run() {
 			result = opResult
 		}
 	} else if strings.EqualFold(cmd, apitypes.LSXCmdLocalDevices) {
-		if len(args) < 4 {
-			printUsageAndExit()
+		if len(args) < 2 {
+			return nil, op, 0, errUsage
 		}
 		op = apitypes.LSXCmdLocalDevices
 		opResult, opErr := d.LocalDevices(ctx, &apitypes.LocalDevicesOpts{
-			ScanType: apitypes.ParseDeviceScanType(args[3]),
+			ScanType: apitypes.ParseDeviceScanType(args[1]),
 			Opts:     store,
 		})
 		if opErr != nil {
@@ -211,17 +236,17 @@ func Run() {
 			result = opResult
 		}
 	} else if strings.EqualFold(cmd, apitypes.LSXCmdWaitForDevice) {
-		if len(args) < 6 {
-			printUsageAndExit()
+		if len(args) < 4 {
+			return nil, op, 0, errUsage
 		}
 		op = apitypes.LSXCmdWaitForDevice
 		opts := &apitypes.WaitForDeviceOpts{
 			LocalDevicesOpts: apitypes.LocalDevicesOpts{
-				ScanType: apitypes.ParseDeviceScanType(args[3]),
+				ScanType: apitypes.ParseDeviceScanType(args[1]),
 				Opts:     store,
 			},
-			Token:   strings.ToLower(args[4]),
-			Timeout: utils.DeviceAttachTimeout(args[5]),
+			Token:   strings.ToLower(args[2]),
+			Timeout: utils.DeviceAttachTimeout(args[3]),
 		}
 
 		ldl := func() (bool, *apitypes.LocalDevices, error) {
@@ -267,6 +292,17 @@ func Run() {
 		}
 	}
 
+	return result, op, exitCode, err
+}
+
+// encodeResult returns the output and exit code of a command with the given
+// result and error.
+func encodeResult(
+	result interface{},
+	op string,
+	exitCode int,
+	err error) ([]byte, string, int) {
+
 	if err != nil {
 		// if the function is not implemented then exit with
 		// apitypes.LSXExitCodeNotImplemented to let callers
@@ -283,37 +319,33 @@ func Run() {
 		default:
 			errStr = e.Error()
 		}
-		fmt.Fprintf(apitypes.Stderr,
-			"error: error getting %s: %v\n", op, errStr)
-		os.Exit(exitCode)
+		return nil, fmt.Sprintf(
+			"error: error getting %s: %v\n", op, errStr), exitCode
 	}
 
 	switch tr := result.(type) {
 	case bool:
-		fmt.Fprintf(apitypes.Stdout, "%v", result)
+		return []byte(fmt.Sprintf("%v", result)), "", exitCode
 	case string:
-		fmt.Fprintln(apitypes.Stdout, result)
+		return []byte(fmt.Sprintln(result)), "", exitCode
 	case encoding.TextMarshaler:
 		buf, err := tr.MarshalText()
 		if err != nil {
-			fmt.Fprintf(apitypes.Stderr, "error: error encoding %s: %v\n", op, err)
-			os.Exit(1)
+			return nil, fmt.Sprintf(
+				"error: error encoding %s: %v\n", op, err), 1
 		}
-		apitypes.Stdout.Write(buf)
+		return buf, "", exitCode
 	default:
 		buf, err := json.Marshal(result)
 		if err != nil {
-			fmt.Fprintf(apitypes.Stderr, "error: error encoding %s: %v\n", op, err)
-			os.Exit(1)
+			return nil, fmt.Sprintf(
+				"error: error encoding %s: %v\n", op, err), 1
 		}
 		if isNullBuf(buf) {
-			apitypes.Stdout.Write(emptyJSONBuff)
-		} else {
-			apitypes.Stdout.Write(buf)
+			return emptyJSONBuff, "", exitCode
 		}
+		return buf, "", exitCode
 	}
-
-	os.Exit(exitCode)
 }
 
 const (
@@ -358,6 +390,7 @@ func printUsage() {
 	printUsageLeftPadded(w, lpad2, "mounts\n")
 	printUsageLeftPadded(w, lpad2, "mount [-l label] [-o options] device path\n")
 	printUsageLeftPadded(w, lpad2, "umount path\n")
+	printUsageLeftPadded(w, lpad1, "%s serve [socket]\n", os.Args[0])
 	fmt.Fprintln(w)
 	executorVar := "executor:    "
 	printUsageLeftPadded(w, lpad1, executorVar)
@@ -0,0 +1,284 @@
+package lsx
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/registry"
+	apitypes "github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+	apiconfig "github.com/codedellemc/libstorage/api/utils/config"
+	"github.com/codedellemc/libstorage/api/utils/flock"
+)
+
+// runServe runs the executor as a daemon that answers the executor commands
+// sent to a unix socket. The socket's path is the first argument, if any, or
+// the configured socket path.
+func runServe(ctx apitypes.Context, args []string) {
+
+	config, err := apiconfig.NewConfig(ctx)
+	if err != nil {
+		fmt.Fprintf(apitypes.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	apiconfig.UpdateLogLevel(config)
+
+	sockPath := config.GetString(apitypes.ConfigExecutorSocket)
+	if len(args) > 0 {
+		sockPath = args[0]
+	}
+
+	d, err := newDaemon(ctx, config, sockPath)
+	if err != nil {
+		fmt.Fprintf(apitypes.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-sigc
+		ctx.WithField("signal", sig).Info("executor daemon stopping")
+		d.close()
+	}()
+
+	ctx.WithField("socket", sockPath).Info("executor daemon listening")
+	d.serve()
+}
+
+// daemon answers the executor commands sent to a unix socket.
+type daemon struct {
+	sync.Mutex
+	ctx         apitypes.Context
+	config      gofig.Config
+	l           net.Listener
+	sockPath    string
+	lockPath    string
+	lockTimeout time.Duration
+	executors   map[string]apitypes.StorageExecutor
+
+	// checksum is the SHA-256 checksum of the daemon's executor, and
+	// configChecksum is the checksum of its configuration. The daemon
+	// answers only the clients that would otherwise invoke the same
+	// executor with the same configuration.
+	checksum       string
+	configChecksum string
+}
+
+func newDaemon(
+	ctx apitypes.Context,
+	config gofig.Config,
+	sockPath string) (*daemon, error) {
+
+	// a socket left behind by a daemon that exited is removed, but one on
+	// which a daemon is listening is not
+	if _, err := os.Stat(sockPath); err == nil {
+		if conn, err := net.Dial("unix", sockPath); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf(
+				"executor daemon already listening on %s", sockPath)
+		}
+		if err := os.Remove(sockPath); err != nil {
+			return nil, err
+		}
+	}
+
+	checksum, err := getExecutableChecksum()
+	if err != nil {
+		return nil, err
+	}
+
+	// only the socket's owner may invoke the executor through it
+	l, err := listenSocket(sockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	lockTimeout, err := time.ParseDuration(
+		config.GetString(apitypes.ConfigExecutorLockTimeout))
+	if err != nil {
+		lockTimeout = time.Duration(5) * time.Minute
+	}
+
+	pathConfig := context.MustPathConfig(ctx)
+
+	return &daemon{
+		ctx:      ctx,
+		config:   config,
+		l:        l,
+		sockPath: sockPath,
+		lockPath: path.Join(
+			pathConfig.Run, fmt.Sprintf("%s.lock", path.Base(pathConfig.LSX))),
+		lockTimeout:    lockTimeout,
+		executors:      map[string]apitypes.StorageExecutor{},
+		checksum:       checksum,
+		configChecksum: utils.ConfigChecksum(config),
+	}, nil
+}
+
+// getExecutableChecksum returns the SHA-256 checksum of the executable with
+// which the process was started.
+func getExecutableChecksum() (string, error) {
+	exePath, err := exec.LookPath(os.Args[0])
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(exePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+func (d *daemon) serve() {
+	for {
+		conn, err := d.l.Accept()
+		if err != nil {
+			return
+		}
+		go d.handle(conn)
+	}
+}
+
+func (d *daemon) close() {
+	d.l.Close()
+	os.Remove(d.sockPath)
+}
+
+// handle answers the request sent on the connection and closes it.
+func (d *daemon) handle(conn net.Conn) {
+	defer conn.Close()
+
+	req := &apitypes.LSXRequest{}
+	if err := json.NewDecoder(conn).Decode(req); err != nil {
+		d.ctx.WithError(err).Error("error decoding executor request")
+		return
+	}
+
+	res := d.reject(req)
+	if res == nil {
+		res = d.execute(req.Args)
+	}
+	if err := json.NewEncoder(conn).Encode(res); err != nil {
+		d.ctx.WithError(err).Error("error encoding executor response")
+	}
+}
+
+// reject returns the response to a request from a client that would invoke a
+// different executor or configure it differently than the daemon, such as
+// after the executor is updated; otherwise a nil value is returned. The
+// response fails the command for clients that do not invoke the executor when
+// the daemon rejects their requests.
+func (d *daemon) reject(req *apitypes.LSXRequest) *apitypes.LSXResponse {
+	var reason string
+	switch {
+	case req.Checksum != d.checksum:
+		reason = "executor checksum mismatch"
+	case req.ConfigChecksum != d.configChecksum:
+		reason = "config checksum mismatch"
+	default:
+		return nil
+	}
+	d.ctx.WithField("reason", reason).Debug("rejected executor request")
+	return &apitypes.LSXResponse{
+		ExitCode: 1,
+		Stderr:   fmt.Sprintf("error: %s\n", reason),
+		Rejected: reason,
+	}
+}
+
+// execute executes the command in the given arguments with the same locking,
+// output, and exit codes as invoking the executor with them.
+func (d *daemon) execute(args []string) *apitypes.LSXResponse {
+
+	if len(args) < 2 {
+		return &apitypes.LSXResponse{
+			ExitCode: 1,
+			Stderr:   "error: missing executor or command\n",
+		}
+	}
+
+	se, err := d.getExecutor(args[0])
+	if err != nil {
+		return &apitypes.LSXResponse{
+			ExitCode: 1,
+			Stderr:   fmt.Sprintf("error: %v\n", err),
+		}
+	}
+
+	// the daemon holds the executor lock for each command as the clients
+	// that invoke the executor directly do
+	acquire := flock.Acquire
+	if apitypes.LSXCmdReadOnly(args[1]) {
+		acquire = flock.AcquireShared
+	}
+	lock, err := acquire(d.lockPath, d.lockTimeout)
+	if err != nil {
+		return &apitypes.LSXResponse{
+			ExitCode: 1,
+			Stderr:   fmt.Sprintf("error: executor lock: %v\n", err),
+		}
+	}
+	defer lock.Release()
+
+	result, op, exitCode, err := execute(d.ctx, se, args[1:])
+	if err == errUsage {
+		return &apitypes.LSXResponse{
+			ExitCode: 1,
+			Stderr: fmt.Sprintf(
+				"error: invalid arguments: %s\n", strings.Join(args, " ")),
+		}
+	}
+
+	stdout, stderr, exitCode := encodeResult(result, op, exitCode, err)
+	return &apitypes.LSXResponse{
+		ExitCode: exitCode,
+		Stdout:   stdout,
+		Stderr:   stderr,
+	}
+}
+
+// getExecutor returns the initialized executor with the given name. Each
+// executor is initialized once, when it is first requested.
+func (d *daemon) getExecutor(name string) (apitypes.StorageExecutor, error) {
+	name = strings.ToLower(name)
+
+	d.Lock()
+	defer d.Unlock()
+
+	if se, ok := d.executors[name]; ok {
+		return se, nil
+	}
+
+	se, err := registry.NewStorageExecutor(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := se.Init(d.ctx, d.config); err != nil {
+		return nil, err
+	}
+
+	d.executors[name] = se
+	d.ctx.WithField("executor", name).Info("initialized executor")
+	return se, nil
+}
@@ -0,0 +1,174 @@
+// +build !windows
+
+package lsx
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	gofigCore "github.com/akutz/gofig"
+	"github.com/akutz/goof"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	apitypes "github.com/codedellemc/libstorage/api/types"
+)
+
+// testExecutor implements only the InstanceID and NextDevice functions.
+type testExecutor struct {
+	apitypes.StorageExecutor
+}
+
+func (e *testExecutor) Name() string {
+	return "test"
+}
+
+func (e *testExecutor) InstanceID(
+	ctx apitypes.Context,
+	opts apitypes.Store) (*apitypes.InstanceID, error) {
+	return &apitypes.InstanceID{ID: "iid-000", Driver: "test"}, nil
+}
+
+func (e *testExecutor) NextDevice(
+	ctx apitypes.Context,
+	opts apitypes.Store) (string, error) {
+	return "", goof.New("no devices")
+}
+
+func newTestDaemon(t *testing.T) (*daemon, string) {
+	dir, err := ioutil.TempDir("", "libstorage-lsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background().WithValue(
+		context.PathConfigKey,
+		&apitypes.PathConfig{Run: dir, LSX: path.Join(dir, "lsx-linux")})
+
+	d, err := newDaemon(ctx, gofigCore.New(), path.Join(dir, "lsx.sock"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	d.executors["test"] = &testExecutor{}
+	go d.serve()
+	return d, dir
+}
+
+func callTestDaemon(
+	t *testing.T, d *daemon, args ...string) *apitypes.LSXResponse {
+
+	return sendTestDaemon(t, d, &apitypes.LSXRequest{
+		Args:           args,
+		Checksum:       d.checksum,
+		ConfigChecksum: d.configChecksum,
+	})
+}
+
+func sendTestDaemon(
+	t *testing.T,
+	d *daemon,
+	req *apitypes.LSXRequest) *apitypes.LSXResponse {
+
+	conn, err := net.DialTimeout(
+		"unix", d.sockPath, time.Duration(1)*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		t.Fatal(err)
+	}
+	res := &apitypes.LSXResponse{}
+	if err := json.NewDecoder(conn).Decode(res); err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestDaemonSocketMode(t *testing.T) {
+	d, dir := newTestDaemon(t)
+	defer os.RemoveAll(dir)
+	defer d.close()
+
+	fi, err := os.Stat(d.sockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+
+	// a second daemon does not replace the socket of one that is listening
+	_, err = newDaemon(d.ctx, d.config, d.sockPath)
+	assert.Error(t, err)
+}
+
+func TestDaemonExecute(t *testing.T) {
+	d, dir := newTestDaemon(t)
+	defer os.RemoveAll(dir)
+	defer d.close()
+
+	// the response has the output and exit code of invoking the executor
+	iid := &apitypes.InstanceID{ID: "iid-000", Driver: "test"}
+	out, err := iid.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := callTestDaemon(t, d, "test", apitypes.LSXCmdInstanceID)
+	assert.Equal(t, 0, res.ExitCode)
+	assert.Equal(t, string(out), string(res.Stdout))
+	assert.Empty(t, res.Stderr)
+
+	res = callTestDaemon(t, d, "test", apitypes.LSXCmdSupported)
+	assert.Equal(t, apitypes.LSXExitCodeNotImplemented, res.ExitCode)
+	assert.Empty(t, res.Stdout)
+
+	res = callTestDaemon(t, d, "test", apitypes.LSXCmdNextDevice)
+	assert.Equal(t, 1, res.ExitCode)
+	assert.Contains(t, res.Stderr, "no devices")
+
+	res = callTestDaemon(t, d, "test")
+	assert.Equal(t, 1, res.ExitCode)
+	assert.Contains(t, res.Stderr, "missing executor or command")
+
+	res = callTestDaemon(t, d, "test", "invalid")
+	assert.Equal(t, 1, res.ExitCode)
+	assert.Contains(t, res.Stderr, "invalid arguments")
+}
+
+func TestDaemonReject(t *testing.T) {
+	d, dir := newTestDaemon(t)
+	defer os.RemoveAll(dir)
+	defer d.close()
+
+	// the requests from clients that would invoke another executor, such as
+	// the one that replaced the daemon's executor, are rejected
+	res := sendTestDaemon(t, d, &apitypes.LSXRequest{
+		Args:           []string{"test", apitypes.LSXCmdInstanceID},
+		Checksum:       "0000",
+		ConfigChecksum: d.configChecksum,
+	})
+	assert.Equal(t, 1, res.ExitCode)
+	assert.Empty(t, res.Stdout)
+	assert.Equal(t, "executor checksum mismatch", res.Rejected)
+
+	// as are those from clients configured differently than the daemon
+	res = sendTestDaemon(t, d, &apitypes.LSXRequest{
+		Args:           []string{"test", apitypes.LSXCmdInstanceID},
+		Checksum:       d.checksum,
+		ConfigChecksum: "0000",
+	})
+	assert.Equal(t, 1, res.ExitCode)
+	assert.Equal(t, "config checksum mismatch", res.Rejected)
+
+	// and those from clients that predate the checksums
+	res = sendTestDaemon(t, d, &apitypes.LSXRequest{
+		Args: []string{"test", apitypes.LSXCmdInstanceID},
+	})
+	assert.Equal(t, 1, res.ExitCode)
+	assert.NotEmpty(t, res.Rejected)
+}
@@ -0,0 +1,17 @@
+// +build !windows
+
+package lsx
+
+import (
+	"net"
+	"syscall"
+)
+
+// listenSocket listens on the unix socket at the given path. The socket is
+// created with a umask that grants access only to its owner, so there is no
+// window in which other users may connect to it.
+func listenSocket(sockPath string) (net.Listener, error) {
+	mask := syscall.Umask(0177)
+	defer syscall.Umask(mask)
+	return net.Listen("unix", sockPath)
+}
@@ -0,0 +1,10 @@
+// +build windows
+
+package lsx
+
+import "net"
+
+// listenSocket listens on the unix socket at the given path.
+func listenSocket(sockPath string) (net.Listener, error) {
+	return net.Listen("unix", sockPath)
+}
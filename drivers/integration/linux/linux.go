@@ -63,6 +63,7 @@ func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 		types.ConfigIgVolOpsCreateDefaultAZ:     d.availabilityZone(),
 		types.ConfigIgVolOpsCreateDefaultFsType: d.fsType(),
 		types.ConfigIgVolOpsMountPath:           d.mountDirPath(),
+		types.ConfigIgVolOpsMountGrowFS:         d.growFS(),
 		types.ConfigIgVolOpsCreateImplicit:      d.volumeCreateImplicit(),
 	}).Info("linux integration driver successfully initialized")
 
@@ -279,6 +280,10 @@ func (d *driver) Mount(
 	}
 
 	if len(mounts) > 0 {
+		if err := d.growFSIfEnabled(
+			ctx, ma.DeviceName, mounts[0].MountPoint, opts.Opts); err != nil {
+			return "", nil, err
+		}
 		return d.volumeMountPath(mounts[0].MountPoint), vol, nil
 	}
 
@@ -312,6 +317,11 @@ func (d *driver) Mount(
 		return "", nil, err
 	}
 
+	if err := d.growFSIfEnabled(
+		ctx, ma.DeviceName, mountPath, opts.Opts); err != nil {
+		return "", nil, err
+	}
+
 	mntPath := d.volumeMountPath(mountPath)
 
 	fields := log.Fields{
@@ -323,6 +333,38 @@ func (d *driver) Mount(
 	return mntPath, vol, nil
 }
 
+// growFSIfEnabled grows the file system on the provided device if the
+// device is larger than the file system and the growFS option is enabled.
+func (d *driver) growFSIfEnabled(
+	ctx types.Context,
+	deviceName, mountPoint string,
+	opts types.Store) error {
+
+	if !d.growFS() {
+		return nil
+	}
+
+	dgf, ok := context.MustClient(ctx).OS().(types.OSDriverWithGrowFS)
+	if !ok {
+		ctx.Warn("os driver cannot grow file systems")
+		return nil
+	}
+
+	err := dgf.GrowFS(ctx, deviceName, mountPoint, opts)
+	if err == types.ErrNotImplemented {
+		ctx.WithFields(log.Fields{
+			"deviceName": deviceName,
+			"mountPoint": mountPoint,
+		}).Warn("file system cannot be grown")
+		return nil
+	}
+	if err != nil {
+		return goof.WithError("problem growing file system", err)
+	}
+
+	return nil
+}
+
 // Unmount will unmount the specified volume by volumeName or volumeID.
 func (d *driver) Unmount(
 	ctx types.Context,
@@ -600,6 +642,10 @@ func (d *driver) mountDirPath() string {
 	return d.config.GetString(types.ConfigIgVolOpsMountPath)
 }
 
+func (d *driver) growFS() bool {
+	return d.config.GetBool(types.ConfigIgVolOpsMountGrowFS)
+}
+
 func (d *driver) volumeCreateImplicit() bool {
 	return d.config.GetBool(types.ConfigIgVolOpsCreateImplicit)
 }
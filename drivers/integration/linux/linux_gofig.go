@@ -62,5 +62,10 @@ func init() {
 				gofig.Bool,
 				"", false, "",
 				types.ConfigIgVolOpsMountPreempt)
+
+			r.Key(
+				gofig.Bool,
+				"", false, "",
+				types.ConfigIgVolOpsMountGrowFS)
 		})
 }
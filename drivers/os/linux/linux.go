@@ -3,11 +3,13 @@
 package linux
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
 	"os"
 	"os/exec"
 	"runtime"
+	"strconv"
 	"strings"
 
 	log "github.com/Sirupsen/logrus"
@@ -250,6 +252,166 @@ func (d *driver) Format(
 	return nil
 }
 
+func (d *driver) GrowFS(
+	ctx types.Context,
+	deviceName, mountPoint string,
+	opts types.Store) error {
+
+	if d.isNfsDevice(deviceName) {
+		return nil
+	}
+
+	fsType, err := probeFsType(deviceName)
+	if err != nil {
+		return err
+	}
+	if fsType != "ext4" && fsType != "xfs" {
+		ctx.WithField("fsType", fsType).Debug(
+			"growing file system not supported")
+		return types.ErrNotImplemented
+	}
+
+	devSize, err := getDeviceSize(deviceName)
+	if err != nil {
+		return err
+	}
+
+	fsSize, err := getFsSize(fsType, deviceName, mountPoint)
+	if err != nil {
+		return err
+	}
+
+	fields := log.Fields{
+		"fsType":     fsType,
+		"deviceName": deviceName,
+		"mountPoint": mountPoint,
+		"deviceSize": devSize,
+		"fsSize":     fsSize,
+		"driverName": driverName}
+
+	if fsSize >= devSize {
+		ctx.WithFields(fields).Debug("file system already fills device")
+		return nil
+	}
+
+	ctx.WithFields(fields).Info("growing file system")
+
+	var cmd *exec.Cmd
+	switch fsType {
+	case "ext4":
+		cmd = exec.Command("resize2fs", deviceName)
+	case "xfs":
+		if mountPoint == "" {
+			return goof.WithField(
+				"deviceName", deviceName,
+				"mount point required to grow xfs file system")
+		}
+		cmd = exec.Command("xfs_growfs", mountPoint)
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return goof.WithFieldsE(goof.Fields{
+			"deviceName": deviceName,
+			"mountPoint": mountPoint,
+			"output":     string(out),
+		}, "error growing filesystem", err)
+	}
+
+	return nil
+}
+
+// getDeviceSize returns the size of a block device in bytes.
+func getDeviceSize(deviceName string) (int64, error) {
+	out, err := exec.Command(
+		"blockdev", "--getsize64", deviceName).Output()
+	if err != nil {
+		return 0, goof.WithFieldE(
+			"deviceName", deviceName, "error getting device size", err)
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+}
+
+// getFsSize returns the size of a device's file system in bytes.
+func getFsSize(fsType, deviceName, mountPoint string) (int64, error) {
+	switch fsType {
+	case "ext4":
+		out, err := exec.Command("dumpe2fs", "-h", deviceName).Output()
+		if err != nil {
+			return 0, goof.WithFieldE(
+				"deviceName", deviceName, "error reading file system", err)
+		}
+		return parseExt4Size(out)
+	case "xfs":
+		if mountPoint == "" {
+			return 0, goof.WithField(
+				"deviceName", deviceName,
+				"mount point required to inspect xfs file system")
+		}
+		out, err := exec.Command("xfs_info", mountPoint).Output()
+		if err != nil {
+			return 0, goof.WithFieldE(
+				"mountPoint", mountPoint, "error reading file system", err)
+		}
+		return parseXFSSize(out)
+	}
+	return 0, errUnsupportedFileSystem
+}
+
+// parseExt4Size parses the "Block count" and "Block size" fields from the
+// output of "dumpe2fs -h".
+func parseExt4Size(out []byte) (int64, error) {
+	var count, size int64
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		kv := strings.SplitN(scanner.Text(), ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch strings.TrimSpace(kv[0]) {
+		case "Block count":
+			count, _ = strconv.ParseInt(strings.TrimSpace(kv[1]), 10, 64)
+		case "Block size":
+			size, _ = strconv.ParseInt(strings.TrimSpace(kv[1]), 10, 64)
+		}
+	}
+	if count == 0 || size == 0 {
+		return 0, goof.New("error parsing ext4 file system size")
+	}
+	return count * size, nil
+}
+
+// parseXFSSize parses the "blocks" and "bsize" fields from the data section
+// of the output of "xfs_info".
+func parseXFSSize(out []byte) (int64, error) {
+	var count, size int64
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(strings.TrimSpace(line), "data") {
+			continue
+		}
+		for _, f := range strings.FieldsFunc(line, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ','
+		}) {
+			kv := strings.SplitN(f, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			switch kv[0] {
+			case "blocks":
+				count, _ = strconv.ParseInt(kv[1], 10, 64)
+			case "bsize":
+				size, _ = strconv.ParseInt(kv[1], 10, 64)
+			}
+		}
+		break
+	}
+	if count == 0 || size == 0 {
+		return 0, goof.New("error parsing xfs file system size")
+	}
+	return count * size, nil
+}
+
 func (d *driver) isNfsDevice(device string) bool {
 	return strings.Contains(device, ":")
 }
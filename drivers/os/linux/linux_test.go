@@ -0,0 +1,59 @@
+// +build linux
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const dumpe2fsOutput = `
+dumpe2fs 1.42.9 (28-Dec-2013)
+Filesystem volume name:   <none>
+Filesystem magic number:  0xEF53
+Inode count:              655360
+Block count:              2621440
+Reserved block count:     131072
+Free blocks:              2541777
+Block size:               4096
+Fragment size:            4096
+`
+
+const xfsInfoOutput = `
+meta-data=/dev/xvdb              isize=512    agcount=4, agsize=655360 blks
+         =                       sectsz=512   attr=2, projid32bit=1
+         =                       crc=1        finobt=0 spinodes=0
+data     =                       bsize=4096   blocks=2621440, imaxpct=25
+         =                       sunit=0      swidth=0 blks
+naming   =version 2              bsize=4096   ascii-ci=0 ftype=1
+log      =internal               bsize=4096   blocks=2560, version=2
+         =                       sectsz=512   sunit=0 blks, lazy-count=1
+realtime =none                   extsz=4096   blocks=0, rtextents=0
+`
+
+func TestParseExt4Size(t *testing.T) {
+	size, err := parseExt4Size([]byte(dumpe2fsOutput))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10737418240), size)
+
+	_, err = parseExt4Size([]byte("Block count:              2621440\n"))
+	assert.Error(t, err)
+
+	_, err = parseExt4Size(nil)
+	assert.Error(t, err)
+}
+
+func TestParseXFSSize(t *testing.T) {
+	size, err := parseXFSSize([]byte(xfsInfoOutput))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10737418240), size)
+
+	// the size of the log section is not the size of the file system
+	_, err = parseXFSSize([]byte(
+		"log      =internal               bsize=4096   blocks=2560\n"))
+	assert.Error(t, err)
+
+	_, err = parseXFSSize(nil)
+	assert.Error(t, err)
+}
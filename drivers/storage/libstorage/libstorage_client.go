@@ -1,12 +1,19 @@
 package libstorage
 
 import (
+	"crypto"
 	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"io/ioutil"
 	"os"
 	"path"
+	"strings"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
 
@@ -31,6 +38,10 @@ type client struct {
 	supportedCache  *lss
 	instanceIDCache types.Store
 	lsxMutexPath    string
+	lsxLockTimeout  time.Duration
+	lsxLockShared   bool
+	lsxSocketPath   string
+	lsxChecksum     executorChecksum
 }
 
 var errExecutorNotSupported = errors.New("executor not supported")
@@ -133,41 +144,43 @@ func (c *client) updateExecutor(ctx types.Context) error {
 		return goof.WithField("lsx", c.pathConfig.LSX, "unknown executor")
 	}
 
-	ctx.Debug("waiting on executor lock")
-	if err := c.lsxMutexWait(); err != nil {
+	lock, err := c.lsxLock(ctx, false)
+	if err != nil {
 		return err
 	}
-	defer func() {
-		ctx.Debug("signalling executor lock")
-		if err := c.lsxMutexSignal(); err != nil {
-			panic(err)
-		}
-	}()
+	defer c.lsxUnlock(ctx, lock)
 
 	if !gotil.FileExists(c.pathConfig.LSX) {
 		ctx.Debug("executor does not exist, download executor")
-		return c.downloadExecutor(ctx)
+		return c.downloadExecutor(ctx, lsxi)
 	}
 
 	ctx.Debug("executor exists, getting local checksum")
 
-	checksum, err := c.getExecutorChecksum(ctx)
+	// prefer the SHA-256 checksum, which older servers do not provide
+	remoteChecksum, newHash := lsxi.MD5Checksum, md5.New
+	if lsxi.SHA256Checksum != "" {
+		remoteChecksum, newHash = lsxi.SHA256Checksum, sha256.New
+	}
+
+	checksum, err := c.getExecutorChecksum(ctx, newHash)
 	if err != nil {
 		return err
 	}
 
-	if lsxi.MD5Checksum != checksum {
+	if remoteChecksum != checksum {
 		ctx.WithFields(log.Fields{
-			"remoteChecksum": lsxi.MD5Checksum,
+			"remoteChecksum": remoteChecksum,
 			"localChecksum":  checksum,
 		}).Debug("executor checksums do not match, download executor")
-		return c.downloadExecutor(ctx)
+		return c.downloadExecutor(ctx, lsxi)
 	}
 
 	return nil
 }
 
-func (c *client) getExecutorChecksum(ctx types.Context) (string, error) {
+func (c *client) getExecutorChecksum(
+	ctx types.Context, newHash func() hash.Hash) (string, error) {
 
 	if c.isController() {
 		return "", utils.NewUnsupportedForClientTypeError(
@@ -182,19 +195,9 @@ func (c *client) getExecutorChecksum(ctx types.Context) (string, error) {
 	}
 	defer f.Close()
 
-	h := md5.New()
-	buf := make([]byte, 1024)
-	for {
-		n, err := f.Read(buf)
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return "", err
-		}
-		if _, err := h.Write(buf[:n]); err != nil {
-			return "", err
-		}
+	h := newHash()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
 	}
 
 	sum := fmt.Sprintf("%x", h.Sum(nil))
@@ -202,7 +205,12 @@ func (c *client) getExecutorChecksum(ctx types.Context) (string, error) {
 	return sum, nil
 }
 
-func (c *client) downloadExecutor(ctx types.Context) error {
+// downloadExecutor downloads the executor to a temporary file, verifies its
+// checksum and, if a public key is configured, its signature, and then
+// renames it into place. The previous executor is restored if the new one
+// cannot be executed.
+func (c *client) downloadExecutor(
+	ctx types.Context, lsxi *types.ExecutorInfo) error {
 
 	if c.isController() {
 		return utils.NewUnsupportedForClientTypeError(
@@ -211,30 +219,174 @@ func (c *client) downloadExecutor(ctx types.Context) error {
 
 	ctx.Debug("downloading executor")
 
-	f, err := os.OpenFile(
-		c.pathConfig.LSX,
-		os.O_CREATE|os.O_RDWR|os.O_TRUNC,
-		0755)
+	pubKey, err := c.getExecutorPublicKey()
 	if err != nil {
 		return err
 	}
 
-	defer f.Close()
+	lsxPath := c.pathConfig.LSX
+	lsxDir, lsxName := path.Split(lsxPath)
 
-	rdr, err := c.APIClient.ExecutorGet(ctx, path.Base(c.pathConfig.LSX))
+	f, err := ioutil.TempFile(lsxDir, lsxName+".")
 	if err != nil {
 		return err
 	}
+	tmpPath := f.Name()
 
-	n, err := io.Copy(f, rdr)
+	installed := false
+	defer func() {
+		if !installed {
+			os.Remove(tmpPath)
+		}
+	}()
+
+	n, md5Sum, sha256Sum, err := c.writeExecutor(ctx, f, lsxName)
 	if err != nil {
 		return err
 	}
 
-	if err := f.Sync(); err != nil {
+	ctx.WithField("bytes", n).Debug("downloaded executor")
+
+	switch {
+	case lsxi.SHA256Checksum != "":
+		if lsxi.SHA256Checksum != fmt.Sprintf("%x", sha256Sum) {
+			return goof.WithFields(goof.Fields{
+				"expected": lsxi.SHA256Checksum,
+				"actual":   fmt.Sprintf("%x", sha256Sum),
+			}, "downloaded executor sha-256 checksum mismatch")
+		}
+	case lsxi.MD5Checksum != "":
+		if lsxi.MD5Checksum != fmt.Sprintf("%x", md5Sum) {
+			return goof.WithFields(goof.Fields{
+				"expected": lsxi.MD5Checksum,
+				"actual":   fmt.Sprintf("%x", md5Sum),
+			}, "downloaded executor md5 checksum mismatch")
+		}
+	}
+
+	if pubKey != nil {
+		if lsxi.Signature == "" {
+			return goof.WithField(
+				"lsx", lsxName, "executor signature required")
+		}
+		sig, err := base64.StdEncoding.DecodeString(lsxi.Signature)
+		if err != nil {
+			return err
+		}
+		if err := utils.VerifySignature(pubKey, sha256Sum, sig); err != nil {
+			return err
+		}
+		ctx.Debug("verified executor signature")
+	}
+
+	// keep a link to the previous executor until the new one is verified
+	prevPath := lsxPath + ".prev"
+	if err := os.Remove(prevPath); err != nil && !os.IsNotExist(err) {
+		return goof.WithFieldE(
+			"path", prevPath, "error removing previous executor", err)
+	}
+	hasPrev := false
+	if gotil.FileExists(lsxPath) {
+		if err := os.Link(lsxPath, prevPath); err != nil {
+			ctx.WithError(err).Warn("error preserving previous executor")
+		} else {
+			hasPrev = true
+		}
+	}
+
+	if err := os.Rename(tmpPath, lsxPath); err != nil {
 		return err
 	}
+	installed = true
+	ctx.Debug("installed executor")
 
-	ctx.WithField("bytes", n).Debug("downloaded executor")
+	if err := c.verifyExecutor(ctx); err != nil {
+		if !hasPrev {
+			return goof.WithError("error executing new executor", err)
+		}
+		ctx.WithError(err).Error(
+			"error executing new executor, restoring previous executor")
+		if rerr := os.Rename(prevPath, lsxPath); rerr != nil {
+			return goof.WithFieldsE(goof.Fields{
+				"rollbackError": rerr,
+			}, "error executing new executor; error restoring previous "+
+				"executor", err)
+		}
+		return goof.WithError(
+			"error executing new executor; restored previous executor", err)
+	}
+
+	if hasPrev {
+		if err := os.Remove(prevPath); err != nil {
+			ctx.WithError(err).Warn("error removing previous executor")
+		}
+	}
+	return nil
+}
+
+// writeExecutor downloads the executor to the given file and returns the
+// number of bytes written as well as the MD5 and SHA-256 checksums of the
+// executor. The file is closed.
+func (c *client) writeExecutor(
+	ctx types.Context,
+	f *os.File,
+	lsxName string) (int64, []byte, []byte, error) {
+
+	defer f.Close()
+
+	rdr, err := c.APIClient.ExecutorGet(ctx, lsxName)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer rdr.Close()
+
+	md5h, sha256h := md5.New(), sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, md5h, sha256h), rdr)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if err := f.Chmod(0755); err != nil {
+		return 0, nil, nil, err
+	}
+	if err := f.Sync(); err != nil {
+		return 0, nil, nil, err
+	}
+	return n, md5h.Sum(nil), sha256h.Sum(nil), nil
+}
+
+// getExecutorPublicKey returns the public key with which the signatures of
+// downloaded executors are verified. The key is nil if none is configured.
+func (c *client) getExecutorPublicKey() (crypto.PublicKey, error) {
+	keyPath := c.config.GetString(types.ConfigExecutorPublicKey)
+	if keyPath == "" {
+		return nil, nil
+	}
+	buf, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	return utils.ParsePublicKey(buf)
+}
+
+// verifyExecutor invokes the supported command of the executor for the
+// drivers of the client's services to ensure the executor can be executed.
+// The executor lock must be held.
+func (c *client) verifyExecutor(ctx types.Context) error {
+	drivers := map[string]bool{}
+	for _, service := range c.serviceCache.Keys() {
+		si := c.serviceCache.GetServiceInfo(service)
+		if si == nil || si.Driver == nil {
+			continue
+		}
+		driverName := strings.ToLower(si.Driver.Name)
+		if drivers[driverName] {
+			continue
+		}
+		drivers[driverName] = true
+		_, err := c.execExecutor(ctx, driverName, types.LSXCmdSupported)
+		if err != nil && err != types.ErrNotImplemented {
+			return err
+		}
+	}
 	return nil
 }
@@ -93,6 +93,38 @@ func (c *client) VolumesByService(
 	return c.APIClient.VolumesByService(ctx, service, attachments)
 }
 
+func (c *client) VolumesPage(
+	ctx types.Context,
+	attachments types.VolumeAttachmentsTypes,
+	page *types.PageOpts) ([]*types.ServiceVolume, string, error) {
+
+	ctx = c.requireCtx(ctx)
+
+	ctxA, err := c.withAllLocalDevices(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	ctx = c.withAllInstanceIDs(ctxA)
+
+	return c.APIClient.VolumesPage(ctx, attachments, page)
+}
+
+func (c *client) VolumesByServicePage(
+	ctx types.Context,
+	service string,
+	attachments types.VolumeAttachmentsTypes,
+	page *types.PageOpts) ([]*types.Volume, string, error) {
+
+	ctx = c.withInstanceID(c.requireCtx(ctx), service)
+	ctxA, err := c.withAllLocalDevices(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	ctx = ctxA
+
+	return c.APIClient.VolumesByServicePage(ctx, service, attachments, page)
+}
+
 func (c *client) VolumeInspect(
 	ctx types.Context,
 	service, volumeID string,
@@ -175,6 +207,15 @@ func (c *client) VolumeCopy(
 	return vol, nil
 }
 
+func (c *client) VolumeResize(
+	ctx types.Context,
+	service, volumeID string,
+	request *types.VolumeResizeRequest) (*types.Volume, error) {
+
+	ctx = c.withInstanceID(c.requireCtx(ctx), service)
+	return c.APIClient.VolumeResize(ctx, service, volumeID, request)
+}
+
 func (c *client) VolumeRemove(
 	ctx types.Context,
 	service, volumeID string,
@@ -295,6 +336,23 @@ func (c *client) SnapshotsByService(
 	return c.APIClient.SnapshotsByService(ctx, service)
 }
 
+func (c *client) SnapshotsPage(
+	ctx types.Context,
+	page *types.PageOpts) ([]*types.ServiceSnapshot, string, error) {
+
+	ctx = c.withAllInstanceIDs(c.requireCtx(ctx))
+	return c.APIClient.SnapshotsPage(ctx, page)
+}
+
+func (c *client) SnapshotsByServicePage(
+	ctx types.Context,
+	service string,
+	page *types.PageOpts) ([]*types.Snapshot, string, error) {
+
+	ctx = c.withInstanceID(c.requireCtx(ctx), service)
+	return c.APIClient.SnapshotsByServicePage(ctx, service, page)
+}
+
 func (c *client) SnapshotInspect(
 	ctx types.Context,
 	service, snapshotID string) (*types.Snapshot, error) {
@@ -2,11 +2,14 @@ package libstorage
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/json"
+	"net"
 	"os"
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -17,6 +20,20 @@ import (
 	"github.com/codedellemc/libstorage/api/context"
 	"github.com/codedellemc/libstorage/api/types"
 	"github.com/codedellemc/libstorage/api/utils"
+	"github.com/codedellemc/libstorage/api/utils/flock"
+	"github.com/codedellemc/libstorage/api/utils/metrics"
+)
+
+var (
+	executorInvocations = metrics.NewCounterVec(
+		"libstorage_executor_invocations_total",
+		"The number of executor invocations by command and outcome.",
+		"command", "outcome")
+
+	executorDuration = metrics.NewHistogramVec(
+		"libstorage_executor_duration_seconds",
+		"The time taken by executor invocations by command.",
+		nil, "command")
 )
 
 func (c *client) Supported(
@@ -432,17 +449,42 @@ func (c *client) runExecutor(
 			c.clientType, "runExecutor")
 	}
 
-	ctx.Debug("waiting on executor lock")
-	if err := c.lsxMutexWait(); err != nil {
-		return nil, err
+	// the executor's arguments are the driver name followed by the command
+	command := ""
+	if len(args) > 1 {
+		command = args[1]
 	}
 
-	defer func() {
-		ctx.Debug("signalling executor lock")
-		if err := c.lsxMutexSignal(); err != nil {
-			panic(err)
+	start := time.Now()
+
+	// the executor daemon, if one is listening, holds the executor lock for
+	// the command itself
+	out, ok, err := c.callExecutorDaemon(ctx, args...)
+	if !ok {
+		var lock *flock.Lock
+		lock, err = c.lsxLock(
+			ctx, c.lsxLockShared && types.LSXCmdReadOnly(command))
+		if err != nil {
+			return nil, err
 		}
-	}()
+		defer c.lsxUnlock(ctx, lock)
+
+		start = time.Now()
+		out, err = c.execExecutor(ctx, args...)
+	}
+
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	executorInvocations.Inc(command, outcome)
+	executorDuration.ObserveSince(start, command)
+
+	return out, err
+}
+
+func (c *client) execExecutor(
+	ctx types.Context, args ...string) ([]byte, error) {
 
 	lsxBin := c.pathConfig.LSX
 	cmd := exec.Command(lsxBin, args...)
@@ -488,27 +530,159 @@ func (c *client) runExecutor(
 	return out, err
 }
 
-func (c *client) lsxMutexWait() error {
+// callExecutorDaemon sends the executor command to the executor daemon. A
+// false value is returned if no daemon is listening on the executor socket,
+// in which case the caller should invoke the executor instead.
+func (c *client) callExecutorDaemon(
+	ctx types.Context, args ...string) ([]byte, bool, error) {
 
-	if c.isController() {
-		return utils.NewUnsupportedForClientTypeError(
-			c.clientType, "lsxMutexWait")
+	if c.lsxSocketPath == "" {
+		return nil, false, nil
 	}
 
-	for {
-		f, err := os.OpenFile(c.lsxMutexPath, os.O_CREATE|os.O_EXCL, 0644)
-		if err != nil {
-			time.Sleep(time.Millisecond * 500)
-			continue
-		}
-		return f.Close()
+	conn, err := net.DialTimeout(
+		"unix", c.lsxSocketPath, time.Duration(1)*time.Second)
+	if err != nil {
+		ctx.WithError(err).Debug("executor daemon unavailable")
+		return nil, false, nil
+	}
+	defer conn.Close()
+
+	// the daemon executes the command only if it would be executed by the
+	// same executor with the same configuration as invoking the executor
+	checksum, err := c.getDaemonChecksum(ctx)
+	if err != nil {
+		ctx.WithError(err).Debug("error getting executor checksum")
+		return nil, false, nil
+	}
+
+	ctx.WithFields(log.Fields{
+		"socket": c.lsxSocketPath,
+		"args":   args,
+	}).Debug("invoking executor daemon")
+
+	if err := json.NewEncoder(conn).Encode(&types.LSXRequest{
+		Args:           args,
+		Checksum:       checksum,
+		ConfigChecksum: utils.ConfigChecksum(c.config),
+	}); err != nil {
+		return nil, true, goof.WithFieldE(
+			"socket", c.lsxSocketPath, "error writing to executor daemon", err)
+	}
+
+	res := &types.LSXResponse{}
+	if err := json.NewDecoder(conn).Decode(res); err != nil {
+		return nil, true, goof.WithFieldE(
+			"socket", c.lsxSocketPath, "error reading from executor daemon", err)
+	}
+
+	if res.Rejected != "" {
+		ctx.WithFields(log.Fields{
+			"socket": c.lsxSocketPath,
+			"reason": res.Rejected,
+		}).Debug("executor daemon rejected command")
+		return nil, false, nil
+	}
+
+	switch res.ExitCode {
+	case 0:
+		return res.Stdout, true, nil
+	case types.LSXExitCodeNotImplemented:
+		return nil, true, types.ErrNotImplemented
+	case types.LSXExitCodeTimedOut:
+		return nil, true, types.ErrTimedOut
+	}
+
+	ctx.WithFields(log.Fields{
+		"socket": c.lsxSocketPath,
+		"args":   args,
+		"stderr": res.Stderr,
+	}).Error("error from executor daemon")
+	return nil, true, goof.WithFields(
+		map[string]interface{}{
+			"socket":   c.lsxSocketPath,
+			"args":     args,
+			"exitCode": res.ExitCode,
+			"stderr":   res.Stderr,
+		},
+		"error executing xcli")
+}
+
+// executorChecksum is the SHA-256 checksum of the executor as of the time
+// the executor was last modified.
+type executorChecksum struct {
+	sync.Mutex
+	sum     string
+	size    int64
+	modTime time.Time
+}
+
+// getDaemonChecksum returns the SHA-256 checksum of the executor, which is
+// computed again only after the executor is modified, such as when it is
+// updated.
+func (c *client) getDaemonChecksum(ctx types.Context) (string, error) {
+	fi, err := os.Stat(c.pathConfig.LSX)
+	if err != nil {
+		return "", err
+	}
+
+	cs := &c.lsxChecksum
+	cs.Lock()
+	defer cs.Unlock()
+
+	if cs.sum != "" && cs.size == fi.Size() && cs.modTime.Equal(fi.ModTime()) {
+		return cs.sum, nil
+	}
+
+	sum, err := c.getExecutorChecksum(ctx, sha256.New)
+	if err != nil {
+		return "", err
 	}
+	cs.sum, cs.size, cs.modTime = sum, fi.Size(), fi.ModTime()
+	return sum, nil
 }
 
-func (c *client) lsxMutexSignal() error {
+// lsxLock acquires the executor lock, which is shared among the processes
+// on the host that invoke the executor. A shared lock may be held by many
+// processes at once and excludes only those that hold the lock exclusively,
+// such as when the executor is updated.
+func (c *client) lsxLock(
+	ctx types.Context, shared bool) (*flock.Lock, error) {
+
 	if c.isController() {
-		return utils.NewUnsupportedForClientTypeError(
-			c.clientType, "lsxMutexSignal")
+		return nil, utils.NewUnsupportedForClientTypeError(
+			c.clientType, "lsxLock")
+	}
+
+	ctx.WithField("shared", shared).Debug("waiting on executor lock")
+
+	acquire := flock.Acquire
+	if shared {
+		acquire = flock.AcquireShared
+	}
+	lock, err := acquire(c.lsxMutexPath, c.lsxLockTimeout)
+	if err == flock.ErrTimeout {
+		return nil, utils.NewExecutorLockTimeoutError(
+			c.lsxMutexPath, flock.Owner(c.lsxMutexPath), c.lsxLockTimeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if lock.Stale > 0 {
+		ctx.WithField("stalePID", lock.Stale).Warn(
+			"reclaimed executor lock from exited process")
+	}
+	ctx.WithField("shared", shared).Debug("acquired executor lock")
+	return lock, nil
+}
+
+// lsxUnlock releases the executor lock. An error releasing the lock is
+// logged since the lock is released by the operating system when the process
+// exits regardless.
+func (c *client) lsxUnlock(ctx types.Context, lock *flock.Lock) {
+	ctx.Debug("releasing executor lock")
+	if err := lock.Release(); err != nil {
+		ctx.WithError(err).Error("error releasing executor lock")
 	}
-	return os.RemoveAll(c.lsxMutexPath)
 }
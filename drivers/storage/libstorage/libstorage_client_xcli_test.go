@@ -0,0 +1,170 @@
+// +build !windows
+
+package libstorage
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	gofigCore "github.com/akutz/gofig"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+func newTestXCLIClient(t *testing.T) (*client, string) {
+	dir, err := ioutil.TempDir("", "libstorage-xcli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &client{
+		config:         gofigCore.New(),
+		clientType:     types.IntegrationClient,
+		pathConfig:     &types.PathConfig{LSX: path.Join(dir, "lsx-linux")},
+		lsxMutexPath:   path.Join(dir, "lsx.lock"),
+		lsxLockTimeout: time.Duration(5) * time.Second,
+		lsxSocketPath:  path.Join(dir, "lsx.sock"),
+	}, dir
+}
+
+func writeTestExecutor(t *testing.T, c *client, script string) {
+	err := ioutil.WriteFile(c.pathConfig.LSX, []byte(script), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// serveTestDaemon answers the requests sent to the client's executor socket
+// with the responses for the requests' commands. The requests from clients
+// whose executor differs from the client's current executor are rejected.
+func serveTestDaemon(
+	t *testing.T,
+	c *client,
+	responses map[string]*types.LSXResponse) net.Listener {
+
+	checksum, err := c.getExecutorChecksum(context.Background(), sha256.New)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configChecksum := utils.ConfigChecksum(c.config)
+
+	l, err := net.Listen("unix", c.lsxSocketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			req := &types.LSXRequest{}
+			if err := json.NewDecoder(conn).Decode(req); err == nil {
+				res := responses[req.Args[1]]
+				if req.Checksum != checksum ||
+					req.ConfigChecksum != configChecksum {
+					res = &types.LSXResponse{ExitCode: 1, Rejected: "mismatch"}
+				}
+				json.NewEncoder(conn).Encode(res)
+			}
+			conn.Close()
+		}
+	}()
+	return l
+}
+
+func TestRunExecutorFallback(t *testing.T) {
+	c, dir := newTestXCLIClient(t)
+	defer os.RemoveAll(dir)
+
+	// the executor is invoked when no daemon is listening on the socket
+	writeTestExecutor(t, c, "#!/bin/sh\necho \"$@\"\n")
+
+	ctx := context.Background()
+	out, err := c.runExecutor(ctx, "vfs", types.LSXCmdInstanceID)
+	assert.NoError(t, err)
+	assert.Equal(t, "vfs instanceID\n", string(out))
+
+	c.lsxSocketPath = ""
+	out, err = c.runExecutor(ctx, "vfs", types.LSXCmdInstanceID)
+	assert.NoError(t, err)
+	assert.Equal(t, "vfs instanceID\n", string(out))
+}
+
+func TestRunExecutorDaemon(t *testing.T) {
+	c, dir := newTestXCLIClient(t)
+	defer os.RemoveAll(dir)
+
+	writeTestExecutor(t, c, "#!/bin/sh\nexit 1\n")
+	l := serveTestDaemon(t, c, map[string]*types.LSXResponse{
+		types.LSXCmdInstanceID: {Stdout: []byte("iid-000")},
+		types.LSXCmdSupported: {
+			ExitCode: types.LSXExitCodeNotImplemented,
+		},
+		types.LSXCmdNextDevice: {
+			ExitCode: 1,
+			Stderr:   "error: no devices\n",
+		},
+	})
+	defer l.Close()
+
+	// the executor is not invoked when a daemon is listening on the socket
+	ctx := context.Background()
+	out, err := c.runExecutor(ctx, "vfs", types.LSXCmdInstanceID)
+	assert.NoError(t, err)
+	assert.Equal(t, "iid-000", string(out))
+
+	_, err = c.runExecutor(ctx, "vfs", types.LSXCmdSupported)
+	assert.Equal(t, types.ErrNotImplemented, err)
+
+	_, err = c.runExecutor(ctx, "vfs", types.LSXCmdNextDevice)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "error executing xcli")
+	}
+}
+
+func TestRunExecutorDaemonMismatch(t *testing.T) {
+	c, dir := newTestXCLIClient(t)
+	defer os.RemoveAll(dir)
+
+	writeTestExecutor(t, c, "#!/bin/sh\nexit 1\n")
+	l := serveTestDaemon(t, c, map[string]*types.LSXResponse{
+		types.LSXCmdInstanceID: {Stdout: []byte("iid-000")},
+	})
+	defer l.Close()
+
+	ctx := context.Background()
+	out, err := c.runExecutor(ctx, "vfs", types.LSXCmdInstanceID)
+	assert.NoError(t, err)
+	assert.Equal(t, "iid-000", string(out))
+
+	// the executor is invoked once it is updated since the daemon is still
+	// running the previous executor
+	writeTestExecutor(t, c, "#!/bin/sh\necho \"$@\"\n")
+	out, err = c.runExecutor(ctx, "vfs", types.LSXCmdInstanceID)
+	assert.NoError(t, err)
+	assert.Equal(t, "vfs instanceID\n", string(out))
+
+	// as it is when the client's configuration differs from the daemon's
+	c, dir = newTestXCLIClient(t)
+	defer os.RemoveAll(dir)
+
+	writeTestExecutor(t, c, "#!/bin/sh\necho \"$@\"\n")
+	l2 := serveTestDaemon(t, c, map[string]*types.LSXResponse{
+		types.LSXCmdInstanceID: {Stdout: []byte("iid-000")},
+	})
+	defer l2.Close()
+
+	c.config.Set(types.ConfigExecutorSocket, c.lsxSocketPath)
+	out, err = c.runExecutor(ctx, "vfs", types.LSXCmdInstanceID)
+	assert.NoError(t, err)
+	assert.Equal(t, "vfs instanceID\n", string(out))
+}
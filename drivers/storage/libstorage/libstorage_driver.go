@@ -7,17 +7,20 @@ import (
 	"net"
 	"net/http"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
 	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
 	"github.com/akutz/gotil"
 
 	apiclient "github.com/codedellemc/libstorage/api/client"
 	"github.com/codedellemc/libstorage/api/context"
 	"github.com/codedellemc/libstorage/api/types"
 	"github.com/codedellemc/libstorage/api/utils"
+	"github.com/codedellemc/libstorage/api/utils/balancer"
 )
 
 var (
@@ -43,8 +46,11 @@ func newDriver() types.StorageDriver {
 func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 	logFields := log.Fields{}
 
-	addr := config.GetString(types.ConfigHost)
-	d.ctx = ctx.WithValue(context.HostKey, addr)
+	addrs := utils.GetHosts(config)
+	if len(addrs) == 0 {
+		return goof.New("host required")
+	}
+	d.ctx = ctx.WithValue(context.HostKey, strings.Join(addrs, ","))
 	d.ctx.Debug("got configured host address")
 
 	if tok := config.GetString(types.ConfigClientAuthToken); len(tok) > 0 {
@@ -64,93 +70,70 @@ func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 		logFields["encodedToken"] = tok
 	}
 
-	proto, lAddr, err := gotil.ParseAddress(addr)
-	if err != nil {
-		return err
-	}
+	var (
+		host      string
+		tlsConfig *types.TLSConfig
+		endpoints []*balancer.Endpoint
+	)
 
-	tlsConfig, err := utils.ParseTLSConfig(
-		d.ctx, config, proto, logFields, types.ConfigClient)
-	if err != nil {
-		return err
-	}
-
-	host := getHost(d.ctx, proto, lAddr, tlsConfig)
 	lsxPath := config.GetString(types.ConfigExecutorPath)
 	cliType := types.ParseClientType(config.GetString(types.ConfigClientType))
 	disableKeepAlive := config.GetBool(types.ConfigHTTPDisableKeepAlive)
 
-	logFields["lAddr"] = host
-	logFields["lsxPath"] = lsxPath
-	logFields["clientType"] = cliType
-	logFields["disableKeepAlive"] = disableKeepAlive
-
-	httpTransport := &http.Transport{
-		Dial: func(string, string) (net.Conn, error) {
-
-			if tlsConfig == nil {
-				conn, err := net.Dial(proto, lAddr)
-				if err != nil {
-					return nil, err
-				}
-				d.ctx.Debug("successful connection")
-				return conn, nil
-			}
+	// each endpoint has its own transport so that its connections are dialed
+	// and verified with the endpoint's own address and TLS configuration
+	for _, addr := range addrs {
 
-			conn, err := tls.Dial(proto, lAddr, &tlsConfig.Config)
-			if err != nil {
-				return nil, err
-			}
-
-			if !tlsConfig.VerifyPeers {
-				d.ctx.Debug("successful tls connection; not verifying peers")
-				return conn, nil
-			}
-
-			const errMatch = "error matching peer fingerprint"
-
-			// get the fqdn/IP of the endpoint to which the connection
-			// is being made in case an ErrKnownHost error occurs
-			hostSansPort := lAddr
-			if hostParts := strings.Split(lAddr, ":"); len(hostParts) > 1 {
-				hostSansPort = hostParts[0]
-			}
-
-			peerCerts := conn.ConnectionState().PeerCertificates
-
-			if ok, err := verifyKnownHost(
-				d.ctx,
-				hostSansPort,
-				peerCerts,
-				tlsConfig.KnownHost); ok {
-
-				return conn, nil
-
-			} else if err != nil {
+		proto, lAddr, err := gotil.ParseAddress(addr)
+		if err != nil {
+			return err
+		}
 
-				d.ctx.WithError(err).Error(errMatch)
-				return nil, err
-			}
+		epTLSConfig, err := utils.ParseTLSConfig(
+			d.ctx, config, proto, logFields, types.ConfigClient)
+		if err != nil {
+			return err
+		}
 
-			if ok, err := verifyKnownHostFiles(
-				d.ctx,
-				hostSansPort,
-				peerCerts,
-				tlsConfig.UsrKnownHosts,
-				tlsConfig.SysKnownHosts); ok {
+		epHost := getHost(d.ctx, proto, lAddr, epTLSConfig)
+		if len(endpoints) == 0 {
+			host = epHost
+			tlsConfig = epTLSConfig
+		}
 
-				return conn, nil
+		endpoints = append(endpoints, &balancer.Endpoint{
+			Host: epHost,
+			Transport: &http.Transport{
+				Dial:              d.newDialer(proto, lAddr, epTLSConfig),
+				DisableKeepAlives: disableKeepAlive,
+			},
+		})
+	}
 
-			} else if err != nil {
+	roundRobin := config.GetBool(types.ConfigClientEndpointsRoundRobin)
+	downtime, err := time.ParseDuration(
+		config.GetString(types.ConfigClientEndpointsDowntime))
+	if err != nil {
+		d.ctx.WithError(err).Warn("invalid endpoint downtime")
+		downtime = time.Duration(30) * time.Second
+	}
 
-				d.ctx.WithError(err).Error(errMatch)
-				return nil, err
-			}
+	logFields["lAddr"] = host
+	logFields["endpoints"] = addrs
+	logFields["endpointsRoundRobin"] = roundRobin
+	logFields["endpointsDowntime"] = downtime
+	logFields["lsxPath"] = lsxPath
+	logFields["clientType"] = cliType
+	logFields["disableKeepAlive"] = disableKeepAlive
 
-			return nil, newErrKnownHost(hostSansPort, peerCerts)
-		},
-		DisableKeepAlives: disableKeepAlive,
-	}
+	// the requests of a transaction are sent to the same endpoint so that a
+	// transaction's retries reach the server that recorded its outcome
+	httpTransport := balancer.New(endpoints, balancer.Options{
+		RoundRobin:   roundRobin,
+		Downtime:     downtime,
+		StickyHeader: types.TransactionHeader,
+		StickyTTL:    time.Duration(10) * time.Minute,
+	})
 
 	apiClient := apiclient.New(host, httpTransport)
 	logReq := config.GetBool(types.ConfigLogHTTPRequests)
@@ -158,6 +141,10 @@ func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 	apiClient.LogRequests(logReq)
 	apiClient.LogResponses(logRes)
 
+	retryPolicy := getRetryPolicy(d.ctx, config)
+	apiClient.SetRetryPolicy(retryPolicy)
+	logFields["retryPolicy"] = retryPolicy
+
 	logFields["enableInstanceIDHeaders"] = EnableInstanceIDHeaders
 	logFields["enableLocalDevicesHeaders"] = EnableLocalDevicesHeaders
 	logFields["logRequests"] = logReq
@@ -170,15 +157,31 @@ func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 	lsxMutexPath := path.Join(pathConfig.Run, lsxMutexName)
 	logFields["lsxMutexPath"] = lsxMutexPath
 
+	lsxLockTimeout, err := time.ParseDuration(
+		config.GetString(types.ConfigExecutorLockTimeout))
+	if err != nil {
+		d.ctx.WithError(err).Warn("invalid executor lock timeout")
+		lsxLockTimeout = time.Duration(5) * time.Minute
+	}
+	lsxLockShared := config.GetBool(types.ConfigExecutorLockShared)
+	logFields["lsxLockTimeout"] = lsxLockTimeout
+	logFields["lsxLockShared"] = lsxLockShared
+
+	lsxSocketPath := config.GetString(types.ConfigExecutorSocket)
+	logFields["lsxSocketPath"] = lsxSocketPath
+
 	d.client = client{
-		APIClient:    apiClient,
-		ctx:          d.ctx,
-		config:       config,
-		tlsConfig:    tlsConfig,
-		pathConfig:   pathConfig,
-		clientType:   cliType,
-		lsxMutexPath: lsxMutexPath,
-		serviceCache: &lss{Store: utils.NewStore()},
+		APIClient:      apiClient,
+		ctx:            d.ctx,
+		config:         config,
+		tlsConfig:      tlsConfig,
+		pathConfig:     pathConfig,
+		clientType:     cliType,
+		lsxMutexPath:   lsxMutexPath,
+		lsxLockTimeout: lsxLockTimeout,
+		lsxLockShared:  lsxLockShared,
+		lsxSocketPath:  lsxSocketPath,
+		serviceCache:   &lss{Store: utils.NewStore()},
 	}
 
 	if d.clientType == types.IntegrationClient {
@@ -207,3 +210,118 @@ func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 	d.ctx.Info("successefully dialed libStorage server")
 	return nil
 }
+
+// getRetryPolicy returns the configured policy with which the client retries
+// the requests that fail for transient reasons.
+func getRetryPolicy(ctx types.Context, config gofig.Config) *types.RetryPolicy {
+
+	p := &types.RetryPolicy{
+		MaxAttempts: config.GetInt(types.ConfigClientRetryMaxAttempts),
+		Backoff:     time.Duration(250) * time.Millisecond,
+		MaxBackoff:  time.Duration(5) * time.Second,
+		Errors:      utils.GetStringList(config, types.ConfigClientRetryErrors),
+		Mutating:    config.GetBool(types.ConfigClientRetryMutating),
+	}
+
+	if v, err := time.ParseDuration(
+		config.GetString(types.ConfigClientRetryBackoff)); err == nil {
+		p.Backoff = v
+	} else {
+		ctx.WithError(err).Warn("invalid retry backoff")
+	}
+	if v, err := time.ParseDuration(
+		config.GetString(types.ConfigClientRetryMaxBackoff)); err == nil {
+		p.MaxBackoff = v
+	} else {
+		ctx.WithError(err).Warn("invalid retry max backoff")
+	}
+
+	for _, v := range utils.GetStringList(
+		config, types.ConfigClientRetryStatusCodes) {
+		code, err := strconv.Atoi(v)
+		if err != nil {
+			ctx.WithField("statusCode", v).Warn("invalid retry status code")
+			continue
+		}
+		p.StatusCodes = append(p.StatusCodes, code)
+	}
+
+	return p
+}
+
+// newDialer returns a function that dials the endpoint at the given address.
+// A connection to a TLS endpoint is verified against the endpoint's known
+// host. The errors that occur before a connection is established are returned
+// as balancer.DialError so that the request is sent to another endpoint.
+func (d *driver) newDialer(
+	proto, lAddr string,
+	tlsConfig *types.TLSConfig) func(string, string) (net.Conn, error) {
+
+	return func(string, string) (net.Conn, error) {
+
+		if tlsConfig == nil {
+			conn, err := net.Dial(proto, lAddr)
+			if err != nil {
+				return nil, &balancer.DialError{Err: err}
+			}
+			d.ctx.WithField("lAddr", lAddr).Debug("successful connection")
+			return conn, nil
+		}
+
+		conn, err := tls.Dial(proto, lAddr, &tlsConfig.Config)
+		if err != nil {
+			return nil, &balancer.DialError{Err: err}
+		}
+
+		if !tlsConfig.VerifyPeers {
+			d.ctx.WithField("lAddr", lAddr).Debug(
+				"successful tls connection; not verifying peers")
+			return conn, nil
+		}
+
+		const errMatch = "error matching peer fingerprint"
+
+		// get the fqdn/IP of the endpoint to which the connection
+		// is being made in case an ErrKnownHost error occurs
+		hostSansPort := lAddr
+		if hostParts := strings.Split(lAddr, ":"); len(hostParts) > 1 {
+			hostSansPort = hostParts[0]
+		}
+
+		peerCerts := conn.ConnectionState().PeerCertificates
+
+		if ok, err := verifyKnownHost(
+			d.ctx,
+			hostSansPort,
+			peerCerts,
+			tlsConfig.KnownHost); ok {
+
+			return conn, nil
+
+		} else if err != nil {
+
+			d.ctx.WithError(err).Error(errMatch)
+			conn.Close()
+			return nil, err
+		}
+
+		if ok, err := verifyKnownHostFiles(
+			d.ctx,
+			hostSansPort,
+			peerCerts,
+			tlsConfig.UsrKnownHosts,
+			tlsConfig.SysKnownHosts); ok {
+
+			return conn, nil
+
+		} else if err != nil {
+
+			d.ctx.WithError(err).Error(errMatch)
+			conn.Close()
+			return nil, err
+		}
+
+		conn.Close()
+		return nil, newErrKnownHost(hostSansPort, peerCerts)
+	}
+}
@@ -88,6 +88,21 @@ func (d *driver) Volumes(
 	return objs, nil
 }
 
+func (d *driver) VolumesPage(
+	ctx types.Context,
+	opts *types.VolumesOpts,
+	page *types.PageOpts) ([]*types.Volume, string, error) {
+
+	ctx = d.requireCtx(ctx)
+	serviceName, ok := context.ServiceName(ctx)
+	if !ok {
+		return nil, "", goof.New("missing service name")
+	}
+
+	return d.client.VolumesByServicePage(
+		ctx, serviceName, opts.Attachments, page)
+}
+
 func (d *driver) VolumeInspect(
 	ctx types.Context,
 	volumeID string,
@@ -136,6 +151,7 @@ func (d *driver) VolumeCreate(
 		IOPS:             opts.IOPS,
 		Size:             opts.Size,
 		Type:             opts.Type,
+		Labels:           opts.Labels,
 		Opts:             opts.Opts.Map(),
 	}
 
@@ -203,6 +219,28 @@ func (d *driver) VolumeSnapshot(
 	return d.client.VolumeSnapshot(ctx, serviceName, volumeID, req)
 }
 
+func (d *driver) VolumeResize(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeResizeOpts) (*types.Volume, error) {
+
+	ctx = d.requireCtx(ctx)
+	serviceName, ok := context.ServiceName(ctx)
+	if !ok {
+		return nil, goof.New("missing service name")
+	}
+
+	req := &types.VolumeResizeRequest{
+		Force: opts.Force,
+		Opts:  opts.Opts.Map(),
+	}
+	if opts.Size != nil {
+		req.Size = *opts.Size
+	}
+
+	return d.client.VolumeResize(ctx, serviceName, volumeID, req)
+}
+
 func (d *driver) VolumeRemove(
 	ctx types.Context,
 	volumeID string,
@@ -300,6 +338,20 @@ func (d *driver) Snapshots(
 	return objs, nil
 }
 
+func (d *driver) SnapshotsPage(
+	ctx types.Context,
+	opts types.Store,
+	page *types.PageOpts) ([]*types.Snapshot, string, error) {
+
+	ctx = d.requireCtx(ctx)
+	serviceName, ok := context.ServiceName(ctx)
+	if !ok {
+		return nil, "", goof.New("missing service name")
+	}
+
+	return d.client.SnapshotsByServicePage(ctx, serviceName, page)
+}
+
 func (d *driver) SnapshotInspect(
 	ctx types.Context,
 	snapshotID string,
@@ -355,3 +407,15 @@ func (d *driver) assertProvidesAPIClient() types.ProvidesAPIClient {
 func (d *driver) assertProvidesStorageExecutorCLI() types.ProvidesStorageExecutorCLI {
 	return d
 }
+
+func (d *driver) assertStorageDriverWithVolumeResize() types.StorageDriverWithVolumeResize {
+	return d
+}
+
+func (d *driver) assertStorageDriverWithVolumesPage() types.StorageDriverWithVolumesPage {
+	return d
+}
+
+func (d *driver) assertStorageDriverWithSnapshotsPage() types.StorageDriverWithSnapshotsPage {
+	return d
+}
@@ -354,6 +354,35 @@ func (d *driver) VolumeRemove(
 	return nil
 }
 
+func (d *driver) VolumeResize(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeResizeOpts) (*types.Volume, error) {
+
+	ctx.WithFields(log.Fields{
+		"volumeID": volumeID,
+		"size":     opts.Size,
+	}).Debug("mockDriver.VolumeResize")
+
+	var modVol *types.Volume
+	for _, vol := range d.volumes {
+		if strings.ToLower(vol.ID) == strings.ToLower(volumeID) {
+			modVol = vol
+			break
+		}
+	}
+
+	if modVol == nil {
+		return nil, utils.NewNotFoundError(volumeID)
+	}
+
+	if opts.Size != nil {
+		modVol.Size = *opts.Size
+	}
+
+	return modVol, nil
+}
+
 func (d *driver) VolumeAttach(
 	ctx types.Context,
 	volumeID string,
@@ -92,6 +92,15 @@ func (d *driver) Login(ctx types.Context) (interface{}, error) {
 	return sess, nil
 }
 
+func (d *driver) HealthCheck(ctx types.Context) error {
+	for _, p := range []string{d.volPath, d.snapPath} {
+		if !gotil.FileExists(p) {
+			return goof.WithField("path", p, "vfs dir does not exist")
+		}
+	}
+	return nil
+}
+
 func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
 	return types.Object, nil
 }
@@ -235,6 +244,9 @@ func (d *driver) VolumeCreate(
 		if opts.Type != nil {
 			fields["type"] = *opts.Type
 		}
+		if len(opts.Labels) > 0 {
+			fields["labels"] = opts.Labels
+		}
 		//if opts.Opts != nil {
 		//	fields["opts"] = opts.Opts
 		//}
@@ -263,6 +275,12 @@ func (d *driver) VolumeCreate(
 	if opts.Encrypted != nil {
 		v.Encrypted = *opts.Encrypted
 	}
+	if len(opts.Labels) > 0 {
+		v.Labels = map[string]string{}
+		for k, lv := range opts.Labels {
+			v.Labels[k] = lv
+		}
+	}
 	if customFields := opts.Opts.GetStore("opts"); customFields != nil {
 		for _, k := range customFields.Keys() {
 			v.Fields[k] = customFields.GetString(k)
@@ -425,6 +443,44 @@ func (d *driver) VolumeRemove(
 	return nil
 }
 
+func (d *driver) VolumeResize(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeResizeOpts) (*types.Volume, error) {
+
+	context.MustSession(ctx)
+
+	if opts.Size == nil || *opts.Size < minSizeGiB {
+		return nil, goof.WithField("size", opts.Size, "invalid volume size")
+	}
+
+	v, err := d.getVolumeByID(volumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if *opts.Size < v.Size && !opts.Force {
+		return nil, goof.WithFields(goof.Fields{
+			"volumeID": volumeID,
+			"size":     v.Size,
+			"newSize":  *opts.Size,
+		}, "cannot shrink volume without force")
+	}
+
+	ctx.WithFields(map[string]interface{}{
+		"volumeID": volumeID,
+		"size":     v.Size,
+		"newSize":  *opts.Size,
+	}).Debug("resizing volume")
+
+	v.Size = *opts.Size
+	if err := d.writeVolume(v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
 func (d *driver) VolumeAttach(
 	ctx types.Context,
 	volumeID string,
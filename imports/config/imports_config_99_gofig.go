@@ -4,6 +4,7 @@ package config
 
 import (
 	"os"
+	"path"
 	"runtime"
 
 	log "github.com/Sirupsen/logrus"
@@ -83,8 +84,14 @@ func init() {
 			rk(gofig.String, pathConfig.LSX, "", types.ConfigExecutorPath)
 
 			rk(gofig.Bool, false, "", types.ConfigExecutorNoDownload)
+			rk(gofig.String, path.Join(pathConfig.Run, "lsx.sock"), "",
+				types.ConfigExecutorSocket)
+			rk(gofig.String, "5m", "", types.ConfigExecutorLockTimeout)
+			rk(gofig.Bool, true, "", types.ConfigExecutorLockShared)
 			rk(gofig.Bool, false, "", types.ConfigIgVolOpsMountPreempt)
 			rk(gofig.Int, 0, "", types.ConfigIgVolOpsMountRetryCount)
+			rk(gofig.String, path.Join(pathConfig.Lib, "mountrefs.json"), "",
+				types.ConfigIgVolOpsMountRefsPath)
 			rk(gofig.String, "5s", "", types.ConfigIgVolOpsMountRetryWait)
 			rk(gofig.Bool, false, "", types.ConfigIgVolOpsCreateDisable)
 			rk(gofig.Bool, false, "", types.ConfigIgVolOpsRemoveDisable)
@@ -93,11 +100,50 @@ func init() {
 			rk(gofig.Bool, true, "", types.ConfigIgVolOpsPathCacheEnabled)
 			rk(gofig.Bool, true, "", types.ConfigIgVolOpsPathCacheAsync)
 			rk(gofig.String, "30m", "", types.ConfigClientCacheInstanceID)
+			rk(gofig.Bool, false, "",
+				types.ConfigClientEndpointsRoundRobin)
+			rk(gofig.String, "30s", "", types.ConfigClientEndpointsDowntime)
+			rk(gofig.Int, 4, "", types.ConfigClientRetryMaxAttempts)
+			rk(gofig.String, "250ms", "", types.ConfigClientRetryBackoff)
+			rk(gofig.String, "5s", "", types.ConfigClientRetryMaxBackoff)
+			rk(gofig.String, "502,503,504", "",
+				types.ConfigClientRetryStatusCodes)
+			rk(gofig.String, "connect,timeout,network", "",
+				types.ConfigClientRetryErrors)
+			rk(gofig.Bool, true, "", types.ConfigClientRetryMutating)
 			rk(gofig.String, "30s", "", types.ConfigDeviceAttachTimeout)
 			rk(gofig.Int, 0, "", types.ConfigDeviceScanType)
 			rk(gofig.Bool, false, "", types.ConfigEmbedded)
 			rk(gofig.String, "1m", "", types.ConfigServerTasksExeTimeout)
 			rk(gofig.String, "0s", "", types.ConfigServerTasksLogTimeout)
+			rk(gofig.Int, 1, "", types.ConfigServerTasksWorkers)
+			rk(gofig.String, "file", "", types.ConfigServerTasksStoreType)
+			rk(gofig.String, path.Join(pathConfig.Lib, "tasks"), "",
+				types.ConfigServerTasksStorePath)
+			rk(gofig.String, "24h", "",
+				types.ConfigServerTasksStoreRetention)
+			rk(gofig.String, path.Join(pathConfig.Lib, "quotas.json"), "",
+				types.ConfigServerQuotasPath)
+			rk(gofig.Bool, false, "", types.ConfigServerAuditDisabled)
+			rk(gofig.Int, 1000, "", types.ConfigServerAuditRecent)
+			rk(gofig.String, "file", "", types.ConfigServerAuditSinkType)
+			rk(gofig.String, path.Join(pathConfig.Log, "audit.log"), "",
+				types.ConfigServerAuditSinkPath)
+			rk(gofig.Int, 100, "", types.ConfigServerAuditSinkMaxSize)
+			rk(gofig.Int, 5, "", types.ConfigServerAuditSinkMaxBackups)
+			rk(gofig.String, "10s", "", types.ConfigServerHealthTimeout)
+			rk(gofig.Bool, false, "", types.ConfigServerHealthCritical)
+			rk(gofig.Int, 5, "", types.ConfigServerWebhooksRetries)
+			rk(gofig.String, "1s", "", types.ConfigServerWebhooksBackoff)
+			rk(gofig.String, "1m", "", types.ConfigServerWebhooksMaxBackoff)
+			rk(gofig.String, "10s", "", types.ConfigServerWebhooksTimeout)
+			rk(gofig.String, path.Join(pathConfig.Log, "webhooks-dead.log"), "",
+				types.ConfigServerWebhooksDeadLetterPath)
+			rk(gofig.String, "subject", "", types.ConfigServerRateLimitKey)
+			rk(gofig.String, "0", "", types.ConfigServerRateLimitRate)
+			rk(gofig.Int, 0, "", types.ConfigServerRateLimitBurst)
+			rk(gofig.Bool, false, "", types.ConfigServerIdempotencyDisabled)
+			rk(gofig.String, "10m", "", types.ConfigServerIdempotencyWindow)
 			rk(gofig.Bool, false, "", types.ConfigServerParseRequestOpts)
 
 			// tls config